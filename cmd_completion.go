@@ -0,0 +1,388 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// completeCommand 补全脚本调用的内部命令，参数是正在输入的命令行，不会被当作全局参数解析
+const completeCommand = "__complete"
+
+func init() {
+	registerCommand(cliCommand{
+		Name:   "completion",
+		Usage:  "cmd.completion.usage",
+		Hidden: true,
+		Run:    runCompletion,
+		Complete: func(args []string) []completion {
+			if len(args) == 0 {
+				return completeWords("bash", "zsh", "fish", "powershell")
+			}
+			return nil
+		},
+	})
+	registerCommand(cliCommand{
+		Name:   completeCommand,
+		Usage:  "cmd.completion.complete_usage",
+		Hidden: true,
+		Run:    runComplete,
+	})
+}
+
+// completion 一个补全候选项，Desc 在 zsh、fish 和 PowerShell 中作为说明显示
+type completion struct {
+	Value string
+	Desc  string
+}
+
+// globalFlags 可以出现在命令行任意位置的全局参数，由 main 在执行命令前移除
+var globalFlags = []commandFlag{
+	{Name: "--lang", Desc: "cmd.completion.flag_lang", Value: true, Values: func() []completion {
+		return completeWords(utils.Locales()...)
+	}},
+	{Name: "--color", Desc: "cmd.completion.flag_color", Value: true, Values: func() []completion {
+		return completeWords(utils.ColorAuto, utils.ColorAlways, utils.ColorNever)
+	}},
+	{Name: "--theme", Desc: "cmd.completion.flag_theme", Value: true, Values: completeThemes},
+	{Name: "--no-color", Desc: "cmd.completion.flag_no_color"},
+}
+
+// globalWords 调试级别等不带 -- 前缀的全局参数
+var globalWords = []completion{
+	{Value: "debug", Desc: "cmd.completion.word_debug"},
+	{Value: "debug-2", Desc: "cmd.completion.word_debug2"},
+}
+
+func runCompletion(args []string) error {
+	if len(args) == 0 {
+		return errors.New(utils.T("cmd.completion.usage_error"))
+	}
+
+	prog := filepath.Base(os.Args[0])
+	if len(args) > 1 {
+		prog = args[1]
+	}
+	name := strings.TrimSuffix(prog, ".exe")
+	fn := "_" + regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(name, "_") + "_complete"
+
+	var script string
+	switch args[0] {
+	case "bash":
+		script = fmt.Sprintf(bashCompletion, prog, fn, completeCommand)
+	case "zsh":
+		script = fmt.Sprintf(zshCompletion, prog, fn, completeCommand)
+	case "fish":
+		script = fmt.Sprintf(fishCompletion, prog, fn, completeCommand)
+	case "powershell", "pwsh":
+		script = fmt.Sprintf(powershellCompletion, name, name+".exe", completeCommand)
+	default:
+		return fmt.Errorf(utils.T("cmd.completion.unsupported_shell"), args[0])
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// runComplete 输出补全候选项，每行一个，值和说明之间以制表符分隔。
+// 第一个参数是光标之前已输入完整的参数个数，其后是这些参数以及正在输入的参数；
+// 正在输入的参数为空时，部分 shell 会省略这个空字符串，因此用个数判断而不是取最后一个
+func runComplete(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(args[0])
+	words := args[1:]
+	if err != nil || n < 0 || n > len(words) {
+		return nil
+	}
+	prev, current := words[:n], ""
+	if len(words) > n {
+		current = words[n]
+	}
+
+	for _, c := range completeLine(prev, current) {
+		if !strings.HasPrefix(c.Value, current) {
+			continue
+		}
+		if c.Desc == "" {
+			fmt.Println(c.Value)
+		} else {
+			fmt.Printf("%s\t%s\n", c.Value, strings.ReplaceAll(c.Desc, "\n", " "))
+		}
+	}
+	return nil
+}
+
+// completeLine 根据已输入的参数返回下一个参数的候选项
+func completeLine(prev []string, current string) []completion {
+	if len(prev) > 0 {
+		if flag := findFlag(globalFlags, prev[len(prev)-1]); flag != nil && flag.Value {
+			return flagValues(flag)
+		}
+	}
+
+	// 与 main 一致，全局参数可以出现在任意位置
+	var args []string
+	for i := 0; i < len(prev); i++ {
+		word := prev[i]
+		if flag := findFlag(globalFlags, word); flag != nil {
+			if flag.Value {
+				i++
+			}
+			continue
+		}
+		if isGlobalWord(word) || (strings.HasPrefix(word, "--") && strings.Contains(word, "=")) {
+			continue
+		}
+		args = append(args, word)
+	}
+
+	if strings.HasPrefix(current, "--") {
+		if name, _, ok := strings.Cut(current, "="); ok {
+			var candidates []completion
+			if flag := findFlag(globalFlags, name); flag != nil {
+				for _, c := range flagValues(flag) {
+					candidates = append(candidates, completion{Value: name + "=" + c.Value, Desc: c.Desc})
+				}
+			}
+			return candidates
+		}
+		return describeFlags(globalFlags)
+	}
+	if len(args) == 0 {
+		var candidates []completion
+		for _, name := range visibleCommands() {
+			_, desc := splitUsage(utils.T(commands[name].Usage))
+			candidates = append(candidates, completion{Value: name, Desc: desc})
+		}
+		for _, word := range globalWords {
+			candidates = append(candidates, completion{Value: word.Value, Desc: utils.T(word.Desc)})
+		}
+		return candidates
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return nil
+	}
+	if cmd.Complete != nil {
+		return cmd.Complete(args[1:])
+	}
+	if len(cmd.Flags) == 0 {
+		return nil
+	}
+	return completeFlags(args[1:], cmd.Flags)
+}
+
+func findFlag(flags []commandFlag, name string) *commandFlag {
+	for i := range flags {
+		if flags[i].Name == name {
+			return &flags[i]
+		}
+	}
+	return nil
+}
+
+func isGlobalWord(word string) bool {
+	if word == "debug-1" {
+		return true
+	}
+	for _, w := range globalWords {
+		if w.Value == word {
+			return true
+		}
+	}
+	return false
+}
+
+func flagValues(flag *commandFlag) []completion {
+	if flag.Values == nil {
+		return nil
+	}
+	return flag.Values()
+}
+
+func describeFlags(flags []commandFlag) []completion {
+	candidates := make([]completion, 0, len(flags))
+	for _, flag := range flags {
+		candidates = append(candidates, completion{Value: flag.Name, Desc: utils.T(flag.Desc)})
+	}
+	return candidates
+}
+
+// completeFlags 补全子命令的 flag：上一个参数是需要取值的 flag 时补全取值，否则补全 flag 名和 words
+func completeFlags(args []string, flags []commandFlag, words ...completion) []completion {
+	if len(args) > 0 {
+		name := "-" + strings.TrimLeft(args[len(args)-1], "-")
+		if flag := findFlag(flags, name); flag != nil && flag.Value {
+			return flagValues(flag)
+		}
+	}
+	return append(words, describeFlags(flags)...)
+}
+
+func completeWords(words ...string) []completion {
+	candidates := make([]completion, 0, len(words))
+	for _, word := range words {
+		candidates = append(candidates, completion{Value: word})
+	}
+	return candidates
+}
+
+// completeClusters 从数据目录中的节点列表缓存补全节点 ID 和名称，补全时不访问网络。
+// 含空白的名称在 bash 中无法正确转义，只补全它的 ID
+func completeClusters() []completion {
+	cache, err := service.LoadNodeCache()
+	if err != nil || cache == nil {
+		return nil
+	}
+	var candidates []completion
+	for _, node := range cache.Nodes {
+		candidates = append(candidates, completion{Value: node.ID, Desc: node.Name})
+	}
+	for _, node := range cache.Nodes {
+		if node.Name != "" && !strings.ContainsAny(node.Name, " \t") {
+			candidates = append(candidates, completion{Value: node.Name, Desc: node.ID})
+		}
+	}
+	return candidates
+}
+
+// completeConfig 读取配置文件生成候选项，配置无法读取时不补全
+func completeConfig(fn func(cfg *models.Config) []completion) func() []completion {
+	return func() []completion {
+		cfg, err := service.NewConfig().Load()
+		if err != nil {
+			return nil
+		}
+		return fn(cfg)
+	}
+}
+
+var completeChannels = completeConfig(func(cfg *models.Config) []completion {
+	var candidates []completion
+	for _, ch := range cfg.Notify.Channels {
+		candidates = append(candidates, completion{Value: ch.Name, Desc: ch.Type})
+	}
+	return candidates
+})
+
+var completeReportJobs = completeConfig(func(cfg *models.Config) []completion {
+	var candidates []completion
+	for _, job := range cfg.Reports {
+		candidates = append(candidates, completion{Value: job.Name, Desc: job.Period})
+	}
+	return candidates
+})
+
+var completeWebUsers = completeConfig(func(cfg *models.Config) []completion {
+	var candidates []completion
+	for _, user := range cfg.Web.Users {
+		candidates = append(candidates, completion{Value: user.Username, Desc: user.EffectiveRole()})
+	}
+	return candidates
+})
+
+func completeThemes() []completion {
+	var custom map[string]map[string]string
+	if cfg, err := service.NewConfig().Load(); err == nil {
+		custom = cfg.UI.Themes
+	}
+	return completeWords(utils.ThemeNames(custom)...)
+}
+
+// 各 shell 的补全脚本只负责调用 __complete 并把结果交给 shell，补全逻辑都在 completeLine 中。
+// 参数依次为程序名、脚本函数名和内部补全命令
+
+const bashCompletion = `# bash completion for %[1]s
+# source <(%[1]s completion bash)
+
+%[2]s() {
+    local value desc
+    COMPREPLY=()
+    while IFS=$'\t' read -r value desc; do
+        [[ -n $value ]] && COMPREPLY+=("$value")
+    done < <("${COMP_WORDS[0]}" %[3]s "$((COMP_CWORD - 1))" "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+}
+
+complete -o default -F %[2]s %[1]s
+`
+
+const zshCompletion = `#compdef %[1]s
+# source <(%[1]s completion zsh)
+
+%[2]s() {
+    local -a candidates
+    local line value desc
+    for line in "${(@f)$("${(Q)words[1]}" %[3]s "$((CURRENT - 2))" "${(@Q)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        value=${line%%%%$'\t'*}
+        desc=${line#*$'\t'}
+        if [[ $desc == $line ]]; then
+            candidates+=("${value//:/\\:}")
+        else
+            candidates+=("${value//:/\\:}:$desc")
+        fi
+    done
+    if (( ${#candidates} == 0 )); then
+        _files
+        return
+    fi
+    _describe -t values '' candidates
+}
+
+if [[ ${zsh_eval_context[-1]} == loadautofunc ]]; then
+    %[2]s "$@"
+else
+    compdef %[2]s %[1]s
+fi
+`
+
+const fishCompletion = `# fish completion for %[1]s
+# %[1]s completion fish | source
+
+function %[2]s
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    set -l results ($tokens[1] %[3]s (math (count $tokens) - 1) $tokens[2..-1] $current 2>/dev/null)
+    if test (count $results) -eq 0
+        __fish_complete_path $current
+        return
+    end
+    printf '%%s\n' $results
+end
+
+complete -c %[1]s -f -a '(%[2]s)'
+`
+
+const powershellCompletion = `# PowerShell completion for %[1]s
+# %[1]s completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName '%[1]s', '%[2]s' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $words = @($commandAst.CommandElements |
+        Where-Object { $_.Extent.StartOffset -lt $cursorPosition } |
+        ForEach-Object { $_.Extent.Text })
+    if ($wordToComplete -ne '') {
+        $words = @($words | Select-Object -SkipLast 1)
+    }
+    $rest = @($words | Select-Object -Skip 1)
+
+    & $words[0] %[3]s $rest.Count @rest $wordToComplete 2>$null | ForEach-Object {
+        $value, $desc = $_ -split "` + "`" + `t", 2
+        if (-not $desc) { $desc = $value }
+        $text = $value
+        if ($value -match '\s') { $text = "'" + ($value -replace "'", "''") + "'" }
+        [System.Management.Automation.CompletionResult]::new($text, $value, 'ParameterValue', $desc)
+    }
+}
+`
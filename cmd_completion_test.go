@@ -0,0 +1,160 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// captureStdout 返回 fn 执行期间写到标准输出的内容
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	err = fn()
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return <-out
+}
+
+func values(candidates []completion) []string {
+	var out []string
+	for _, c := range candidates {
+		out = append(out, c.Value)
+	}
+	return out
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// useNodeCache 切换到临时目录，配置的数据目录中保存给定的节点列表缓存
+func useNodeCache(t *testing.T, nodes []models.Node) {
+	t.Helper()
+	dir := t.TempDir()
+	oldConfig := service.ConfigFile
+	service.ConfigFile = filepath.Join(dir, "config.json")
+	t.Cleanup(func() { service.ConfigFile = oldConfig })
+	dataDir := filepath.ToSlash(filepath.Join(dir, "data"))
+	if err := os.WriteFile(service.ConfigFile, []byte(`{"dataDir": "`+dataDir+`"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	service.SaveNodeCache(nodes)
+}
+
+func TestCompleteLineWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     []string
+		current  string
+		want     []string
+		notWant  []string
+		onlyWant bool
+	}{
+		{name: "命令和全局参数", want: []string{"uptime", "serve", "debug"}, notWant: []string{"completion", completeCommand}},
+		{name: "全局 flag", current: "--", want: []string{"--lang", "--color", "--no-color"}},
+		{name: "全局 flag 的取值", prev: []string{"--lang"}, want: utils.Locales(), onlyWant: true},
+		{name: "等号形式的取值", current: "--color=", want: []string{"--color=auto", "--color=always", "--color=never"}, onlyWant: true},
+		{name: "跳过全局参数后补全子命令", prev: []string{"--lang", "en", "debug", "completion"}, want: []string{"bash", "zsh", "fish", "powershell"}, onlyWant: true},
+		{name: "子命令的 flag", prev: []string{"daemon"}, want: []string{"-expose"}},
+		{name: "未知命令", prev: []string{"nope"}, onlyWant: true},
+	}
+	for _, tt := range tests {
+		got := values(completeLine(tt.prev, tt.current))
+		for _, want := range tt.want {
+			if !contains(got, want) {
+				t.Errorf("%s: 候选项 %v 中缺少 %q", tt.name, got, want)
+			}
+		}
+		for _, unwanted := range tt.notWant {
+			if contains(got, unwanted) {
+				t.Errorf("%s: 候选项中不应有 %q", tt.name, unwanted)
+			}
+		}
+		if tt.onlyWant && len(got) != len(tt.want) {
+			t.Errorf("%s: 候选项应为 %v，实际为 %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCompleteNodeIDsFromCache(t *testing.T) {
+	useNodeCache(t, []models.Node{
+		{ID: "5f0c1e2d3a4b5c6d7e8f9012", Name: "node-1"},
+		{ID: "6a1b2c3d4e5f6a7b8c9d0e1f", Name: "has space"},
+	})
+
+	got := values(completeLine([]string{"uptime"}, ""))
+	for _, want := range []string{"poll", "5f0c1e2d3a4b5c6d7e8f9012", "node-1", "6a1b2c3d4e5f6a7b8c9d0e1f"} {
+		if !contains(got, want) {
+			t.Errorf("候选项 %v 中缺少 %q", got, want)
+		}
+	}
+	if contains(got, "has space") {
+		t.Error("含空白的节点名称不应作为候选项")
+	}
+
+	// __complete 只输出与正在输入的参数前缀匹配的候选项，说明以制表符分隔
+	out := captureStdout(t, func() error { return runComplete([]string{"1", "uptime", "node"}) })
+	if out != "node-1\t5f0c1e2d3a4b5c6d7e8f9012\n" {
+		t.Errorf("__complete 输出 %q", out)
+	}
+	// 正在输入的参数为空且被 shell 省略时，按个数补全下一个参数
+	out = captureStdout(t, func() error { return runComplete([]string{"1", "uptime"}) })
+	if !strings.Contains(out, "poll\n") || !strings.Contains(out, "node-1\t") {
+		t.Errorf("省略空参数时应补全全部候选项，实际为 %q", out)
+	}
+	// 个数无效时不输出
+	if out := captureStdout(t, func() error { return runComplete([]string{"3", "uptime"}) }); out != "" {
+		t.Errorf("个数大于参数数量时不应输出，实际为 %q", out)
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		script := captureStdout(t, func() error { return runCompletion([]string{shell, "oba-bd"}) })
+		if !strings.Contains(script, completeCommand) || strings.Contains(script, "%!") {
+			t.Errorf("%s 补全脚本应调用 %s 且没有格式化错误:\n%s", shell, completeCommand, script)
+		}
+		if shell != "powershell" && !strings.Contains(script, "_oba_bd_complete") {
+			t.Errorf("%s 补全脚本的函数名应只含字母数字和下划线:\n%s", shell, script)
+		}
+	}
+	if err := runCompletion([]string{"tcsh"}); err == nil {
+		t.Error("不支持的 shell 应返回错误")
+	}
+
+	// 有 bash 时检查脚本语法
+	if _, err := exec.LookPath("bash"); err == nil {
+		script := captureStdout(t, func() error { return runCompletion([]string{"bash", "oba-bd"}) })
+		cmd := exec.Command("bash", "-n")
+		cmd.Stdin = strings.NewReader(script)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("bash 补全脚本有语法错误: %v\n%s", err, out)
+		}
+	}
+}
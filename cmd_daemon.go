@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "daemon",
+		Usage: "cmd.daemon.usage",
+		Run:   runDaemon,
+		Flags: []commandFlag{
+			{Name: "-expose", Desc: "cmd.daemon.flag_expose"},
+		},
+	})
+}
+
+func runDaemon(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	expose := fs.Bool("expose", false, utils.T("cmd.daemon.flag_expose"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *expose {
+		applyExpose(&cfg.Web)
+	}
+	if fs.NArg() > 0 {
+		port, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || port < 0 || port > 65535 {
+			return fmt.Errorf(utils.T("cmd.invalid_port"), fs.Arg(0))
+		}
+		cfg.Daemon.WebPort = port
+	}
+
+	daemonService := service.NewDaemon(cfg)
+	if err := daemonService.Setup(); err != nil {
+		return err
+	}
+
+	// PID 文件防止重复启动多个实例
+	release, err := utils.AcquirePidFile(daemonService.PidFile())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.daemon.started", os.Getpid())))
+	table := utils.NewTable(
+		utils.TableColumn{Title: utils.T("cmd.daemon.col_job")},
+		utils.TableColumn{Title: utils.T("cmd.daemon.col_schedule")},
+		utils.TableColumn{Title: utils.T("cmd.daemon.col_next_run")},
+	)
+	for _, job := range daemonService.Jobs() {
+		table.AddRow(job.Name, job.Schedule, job.NextRun.Format("2006-01-02 15:04:05"))
+	}
+	table.Print()
+
+	if err := daemonService.Run(ctx); err != nil {
+		return err
+	}
+	fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("cmd.daemon.stopped")))
+	return nil
+}
+
+// applyExpose 开启 expose，仍为本机地址时改为监听所有网络接口
+func applyExpose(cfg *models.WebConfig) {
+	cfg.Expose = true
+	if ip := net.ParseIP(cfg.Listen); cfg.Listen == "" || cfg.Listen == "localhost" || (ip != nil && ip.IsLoopback()) {
+		cfg.Listen = "0.0.0.0"
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// man 生成 roff 格式的手册页，供打包时安装到 man1 目录
+func init() {
+	registerCommand(cliCommand{
+		Name:   "man",
+		Usage:  "cmd.man.usage",
+		Hidden: true,
+		Run:    runMan,
+		Flags: []commandFlag{
+			{Name: "-name", Desc: "cmd.man.flag_name", Value: true},
+		},
+	})
+}
+
+func runMan(args []string) error {
+	fs := flag.NewFlagSet("man", flag.ContinueOnError)
+	name := fs.String("name", strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe"), utils.T("cmd.man.flag_name"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New(utils.T("cmd.man.empty_name"))
+	}
+	date := time.Now().Format("2006-01-02")
+
+	// 不指定目录时只输出主手册页
+	if fs.NArg() == 0 {
+		fmt.Print(mainManPage(*name, date))
+		return nil
+	}
+
+	dir := fs.Arg(0)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf(utils.T("cmd.man.mkdir_failed"), err)
+	}
+	pages := map[string]string{*name + ".1": mainManPage(*name, date)}
+	for _, cmd := range visibleCommands() {
+		pages[*name+"-"+cmd+".1"] = commandManPage(*name, cmd, date)
+	}
+	for file, page := range pages {
+		path := filepath.Join(dir, file)
+		if err := ioutil.WriteFile(path, []byte(page), 0644); err != nil {
+			return fmt.Errorf(utils.T("cmd.man.write_failed"), err)
+		}
+	}
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.TN("cmd.man.written", len(pages), dir)))
+	return nil
+}
+
+// roffEscape 转义 roff 中有特殊含义的字符，避免行首的 . 和 ' 被当作请求
+func roffEscape(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// manHeader 输出 .TH 和名称段
+func manHeader(b *bytes.Buffer, title, date, summary string) {
+	fmt.Fprintf(b, ".TH %s 1 \"%s\" \"%s\" \"%s\"\n", strings.ToUpper(roffEscape(title)), date, "OpenBMCLAPI-API-Go", utils.T("man.manual"))
+	fmt.Fprintf(b, ".SH %s\n%s \\- %s\n", utils.T("man.name"), roffEscape(title), roffEscape(summary))
+}
+
+func manFlags(b *bytes.Buffer, flags []commandFlag) {
+	for _, f := range flags {
+		b.WriteString(".TP\n")
+		if f.Value {
+			fmt.Fprintf(b, ".B %s \\fI%s\\fR\n", roffEscape(f.Name), roffEscape(utils.T("man.value")))
+		} else {
+			fmt.Fprintf(b, ".B %s\n", roffEscape(f.Name))
+		}
+		b.WriteString(roffEscape(utils.T(f.Desc)) + "\n")
+	}
+}
+
+func mainManPage(name, date string) string {
+	var b bytes.Buffer
+	manHeader(&b, name, date, utils.T("man.summary"))
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.synopsis"))
+	fmt.Fprintf(&b, ".B %s\n%s\n", roffEscape(name), roffEscape(utils.T("man.synopsis_args")))
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.description"))
+	b.WriteString(roffEscape(utils.T("man.intro")) + "\n")
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.commands"))
+	for _, cmd := range visibleCommands() {
+		usage, desc := splitUsage(utils.T(commands[cmd].Usage))
+		// 宏参数个数有限，用法说明整体作为一个带引号的参数
+		fmt.Fprintf(&b, ".TP\n.B \"%s\"\n%s\n", strings.ReplaceAll(roffEscape(usage), `"`, `""`), roffEscape(desc))
+	}
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.options"))
+	manFlags(&b, globalFlags)
+	for _, word := range globalWords {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(word.Value), roffEscape(utils.T(word.Desc)))
+	}
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.files"))
+	for _, file := range []struct{ path, desc string }{
+		{service.ConfigFile, "man.file_config"},
+		{"cookie.json", "man.file_cookie"},
+		{filepath.Join(models.DefaultConfig().DataDir, service.NodeCacheFile), "man.file_node_cache"},
+	} {
+		fmt.Fprintf(&b, ".TP\n.I %s\n%s\n", roffEscape(file.path), roffEscape(utils.T(file.desc)))
+	}
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.environment"))
+	for _, env := range []struct{ name, desc string }{
+		{"LC_ALL, LC_MESSAGES, LANG", "man.env_lang"},
+		{"NO_COLOR", "man.env_no_color"},
+	} {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", env.name, roffEscape(utils.T(env.desc)))
+	}
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.see_also"))
+	var refs []string
+	for _, cmd := range visibleCommands() {
+		refs = append(refs, fmt.Sprintf(".BR %s (1)", roffEscape(name+"-"+cmd)))
+	}
+	b.WriteString(strings.Join(refs, " ,\n") + "\n")
+	return b.String()
+}
+
+func commandManPage(name, cmd, date string) string {
+	var b bytes.Buffer
+	usage, desc := splitUsage(utils.T(commands[cmd].Usage))
+	manHeader(&b, name+"-"+cmd, date, desc)
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.synopsis"))
+	fmt.Fprintf(&b, ".B %s\n%s\n", roffEscape(name), roffEscape(usage))
+
+	fmt.Fprintf(&b, ".SH %s\n", utils.T("man.description"))
+	b.WriteString(roffEscape(desc) + "\n")
+
+	if flags := commands[cmd].Flags; len(flags) > 0 {
+		fmt.Fprintf(&b, ".SH %s\n", utils.T("man.options"))
+		manFlags(&b, flags)
+	}
+
+	fmt.Fprintf(&b, ".SH %s\n.BR %s (1)\n", utils.T("man.see_also"), roffEscape(name))
+	return b.String()
+}
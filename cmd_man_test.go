@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRoffEscape(t *testing.T) {
+	got := roffEscape(".start\n'quote a-b\\c")
+	if want := `\&.start` + "\n" + `\&'quote a\-b\ec`; got != want {
+		t.Errorf("roffEscape = %q，应为 %q", got, want)
+	}
+}
+
+// checkRoff 检查手册页的每一行都是已知的 roff 请求或不以 . 和 ' 开头的正文
+func checkRoff(t *testing.T, name, page string) {
+	t.Helper()
+	requests := map[string]bool{".TH": true, ".SH": true, ".TP": true, ".B": true, ".I": true, ".BR": true}
+	for _, line := range strings.Split(strings.TrimSuffix(page, "\n"), "\n") {
+		if strings.HasPrefix(line, "'") {
+			t.Errorf("%s: 行首的 ' 没有转义: %q", name, line)
+		}
+		if strings.HasPrefix(line, ".") && !requests[strings.Fields(line)[0]] {
+			t.Errorf("%s: 未知的 roff 请求: %q", name, line)
+		}
+	}
+}
+
+func TestManPages(t *testing.T) {
+	page := mainManPage("oba-bd", "2026-01-02")
+	if !strings.HasPrefix(page, `.TH OBA\-BD 1 "2026-01-02"`) {
+		t.Errorf("主手册页应以 .TH 开头:\n%s", page)
+	}
+	checkRoff(t, "oba-bd.1", page)
+	for _, cmd := range visibleCommands() {
+		if !strings.Contains(page, `.BR oba\-bd\-`+roffEscape(cmd)+" (1)") {
+			t.Errorf("主手册页应引用 %s 的手册页", cmd)
+		}
+		sub := commandManPage("oba-bd", cmd, "2026-01-02")
+		if !strings.HasPrefix(sub, `.TH OBA\-BD\-`+strings.ToUpper(roffEscape(cmd))+" 1") {
+			t.Errorf("%s 手册页的标题不正确:\n%s", cmd, sub)
+		}
+		checkRoff(t, "oba-bd-"+cmd+".1", sub)
+	}
+
+	// 指定目录时为每个命令生成一个手册页
+	dir := filepath.Join(t.TempDir(), "man1")
+	captureStdout(t, func() error { return runMan([]string{"-name", "oba-bd", dir}) })
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(visibleCommands())+1 {
+		t.Errorf("应生成 %d 个手册页，实际为 %d", len(visibleCommands())+1, len(files))
+	}
+	if err := runMan([]string{"-name", ""}); err == nil {
+		t.Error("名称为空时应返回错误")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "test-notify",
+		Usage: "cmd.notify.usage",
+		Run:   runTestNotify,
+		Complete: func(args []string) []completion {
+			return completeChannels()
+		},
+	})
+}
+
+func runTestNotify(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	notifyService := service.NewNotify(cfg.Notify)
+
+	channels := notifyService.Channels()
+	if len(args) > 0 {
+		channels = nil
+		for _, name := range args {
+			ch, ok := notifyService.FindChannel(name)
+			if !ok {
+				return fmt.Errorf(utils.T("cmd.channel_not_found"), name)
+			}
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		return fmt.Errorf(utils.T("cmd.notify.no_channels"), service.ConfigFile)
+	}
+
+	alert := models.Alert{
+		Rule:     "test-notify",
+		Severity: models.SeverityInfo,
+		Title:    utils.T("cmd.notify.test_title"),
+		Message:  utils.T("cmd.notify.test_message"),
+		Time:     time.Now(),
+	}
+
+	failed := 0
+	for _, ch := range channels {
+		if err := notifyService.SendTo(ch, alert); err != nil {
+			failed++
+			fmt.Println(utils.ColorText(utils.RoleError, fmt.Sprintf("❌ %s (%s): %v", ch.Name, ch.Type, err)))
+			continue
+		}
+		fmt.Println(utils.ColorText(utils.RoleOK, fmt.Sprintf("✓ %s (%s)", ch.Name, ch.Type)))
+	}
+	if failed > 0 {
+		return errors.New(utils.TN("cmd.notify.failed", failed))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "rank",
+		Usage: "cmd.rank.usage",
+		Run:   runRank,
+		Complete: func(args []string) []completion {
+			if len(args) == 0 {
+				return completeWords("snapshot", "report")
+			}
+			return nil
+		},
+	})
+}
+
+func runRank(args []string) error {
+	if len(args) == 0 {
+		return errors.New(utils.T("cmd.rank.usage_error"))
+	}
+
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	historyService := service.NewRankHistory(cfg.DataDir)
+
+	switch args[0] {
+	case "snapshot":
+		snapshot, err := fetchRankSnapshot()
+		if err != nil {
+			return err
+		}
+		saved, err := historyService.Save(*snapshot)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("cmd.rank.exists", snapshot.Date)))
+			return nil
+		}
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.rank.saved", snapshot.Date, len(snapshot.Entries))))
+		return nil
+	case "report":
+		top := 10
+		if len(args) > 1 {
+			if top, err = strconv.Atoi(args[1]); err != nil || top <= 0 {
+				return fmt.Errorf(utils.T("cmd.rank.invalid_count"), args[1])
+			}
+		}
+		// 与已保存的快照比较，实时排行榜是当天截至现在的累计值，和前一天的全天数据不可比
+		snapshot, err := historyService.Latest()
+		if err != nil {
+			return err
+		}
+		if snapshot == nil {
+			return errors.New(utils.T("cmd.rank.no_snapshots"))
+		}
+		report, err := historyService.Report(*snapshot, ownClusterIDs(), cfg.WatchClusters, top)
+		if err != nil {
+			return err
+		}
+		historyService.DisplayReport(report)
+		return nil
+	default:
+		return fmt.Errorf(utils.T("cmd.unknown_subcommand"), args[0])
+	}
+}
+
+// fetchRankSnapshot 获取当前排行榜
+func fetchRankSnapshot() (*models.RankSnapshot, error) {
+	ranks, err := service.NewNode().GetNodeMetricRank(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf(utils.T("common.fetch_rank_failed"), err)
+	}
+	snapshot := service.NewRankSnapshot(ranks, time.Now())
+	return &snapshot, nil
+}
+
+// ownClusterIDs 返回当前账号下的节点 ID，未登录时返回空
+func ownClusterIDs() []string {
+	nodes, err := service.NewNode().GetNodeList()
+	if err != nil {
+		utils.DebugLog(1, "[Rank] 获取自己的节点失败，仅显示关注节点: %v", err)
+		return nil
+	}
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
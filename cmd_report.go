@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:     "report",
+		Usage:    "cmd.report.usage",
+		Run:      runReport,
+		Flags:    reportFlags,
+		Complete: completeReport,
+	})
+}
+
+var reportFlags = []commandFlag{
+	{Name: "-from", Desc: "cmd.report.flag_from", Value: true},
+	{Name: "-to", Desc: "cmd.report.flag_to", Value: true},
+	{Name: "-format", Desc: "cmd.report.flag_format", Value: true, Values: func() []completion {
+		return completeWords("markdown", "html", "text")
+	}},
+	{Name: "-out", Desc: "cmd.report.flag_out", Value: true},
+	{Name: "-send", Desc: "cmd.report.flag_send", Value: true, Values: completeChannels},
+}
+
+func completeReport(args []string) []completion {
+	if len(args) == 0 {
+		return completeFlags(args, reportFlags, completeWords("daily", "weekly", "run")...)
+	}
+	if args[0] == "run" {
+		if len(args) == 1 {
+			return completeReportJobs()
+		}
+		return nil
+	}
+	return completeFlags(args, reportFlags)
+}
+
+func runReport(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	reportService := service.NewReport(cfg)
+
+	// 执行配置文件中的报告任务
+	if len(args) > 0 && args[0] == "run" {
+		if len(args) < 2 {
+			return errors.New(utils.T("cmd.report.usage_run"))
+		}
+		job, ok := reportService.FindJob(args[1])
+		if !ok {
+			return fmt.Errorf(utils.T("cmd.report.job_not_found"), args[1])
+		}
+		files, err := reportService.RunJob(job, time.Now())
+		for _, file := range files {
+			fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.report.generated", file)))
+		}
+		return err
+	}
+
+	period := "weekly"
+	if len(args) > 0 && (args[0] == "daily" || args[0] == "weekly") {
+		period = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", utils.T("cmd.report.flag_from"))
+	toFlag := fs.String("to", "", utils.T("cmd.report.flag_to"))
+	format := fs.String("format", "markdown", utils.T("cmd.report.flag_format"))
+	out := fs.String("out", "", utils.T("cmd.report.flag_out"))
+	send := fs.String("send", "", utils.T("cmd.report.flag_send"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	from, to, err := service.CustomReportRange(period, *fromFlag, *toFlag, time.Now())
+	if err != nil {
+		return err
+	}
+
+	report, err := reportService.Build(from, to)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		path, err := reportService.WriteFile(report, *format, *out, "report")
+		if err != nil {
+			return err
+		}
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.report.generated", path)))
+	} else if *send == "" {
+		content, err := reportService.Render(report, *format)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stdout, content)
+	}
+
+	if *send != "" {
+		if err := reportService.Send(report, *send); err != nil {
+			return err
+		}
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.report.sent", *send)))
+	}
+	return nil
+}
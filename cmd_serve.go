@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "serve",
+		Usage: "cmd.serve.usage",
+		Run:   runServe,
+		Flags: []commandFlag{
+			{Name: "-listen", Desc: "cmd.serve.flag_listen", Value: true},
+			{Name: "-expose", Desc: "cmd.serve.flag_expose"},
+			{Name: "-cors", Desc: "cmd.serve.flag_cors", Value: true},
+		},
+	})
+}
+
+func runServe(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", "", utils.T("cmd.serve.flag_listen"))
+	expose := fs.Bool("expose", false, utils.T("cmd.serve.flag_expose"))
+	cors := fs.String("cors", "", utils.T("cmd.serve.flag_cors"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *listen != "" {
+		cfg.Web.Listen = *listen
+	}
+	if *expose {
+		applyExpose(&cfg.Web)
+	}
+	if *cors != "" {
+		cfg.Web.CORS = strings.Split(*cors, ",")
+	}
+	// 默认与 daemon 和交互界面使用同一个端口 (daemon.webPort)
+	port := cfg.PanelPort()
+	if fs.NArg() > 0 {
+		if port, err = strconv.Atoi(fs.Arg(0)); err != nil || port < 0 || port > 65535 {
+			return fmt.Errorf(utils.T("cmd.invalid_port"), fs.Arg(0))
+		}
+	}
+
+	webService := service.NewWeb(port, cfg)
+	webService.SetHeadless(true)
+	if err := webService.StartServer(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.serve.started")))
+
+	// 收到退出信号或服务器自行退出时都要结束进程
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-webService.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := webService.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if serveErr != nil {
+		return serveErr
+	}
+	fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("cmd.serve.stopped")))
+	return nil
+}
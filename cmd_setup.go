@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "setup",
+		Usage: "cmd.setup.usage",
+		Run: func(args []string) error {
+			return service.NewSetup().Run()
+		},
+	})
+}
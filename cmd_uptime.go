@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "uptime",
+		Usage: "cmd.uptime.usage",
+		Run:   runUptime,
+		Complete: func(args []string) []completion {
+			if len(args) == 0 {
+				return append(completeWords("poll"), completeClusters()...)
+			}
+			return nil
+		},
+	})
+}
+
+func runUptime(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	uptimeService := service.NewUptime(cfg.DataDir)
+
+	if len(args) > 0 && args[0] == "poll" {
+		interval := 60 * time.Second
+		if len(args) > 1 {
+			seconds, err := strconv.Atoi(args[1])
+			if err != nil || seconds <= 0 {
+				return fmt.Errorf(utils.T("cmd.uptime.invalid_interval"), args[1])
+			}
+			interval = time.Duration(seconds) * time.Second
+		}
+		return pollUptime(uptimeService, interval)
+	}
+
+	records, err := uptimeService.Load()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		rec := service.FindRecord(records, args[0])
+		if rec == nil {
+			return fmt.Errorf(utils.T("cmd.uptime.no_records"), args[0])
+		}
+		uptimeService.DisplayOutageLog(rec)
+		return nil
+	}
+
+	uptimeService.DisplayReports(records)
+	return nil
+}
+
+// pollUptime 按固定间隔轮询节点列表并记录状态变化
+func pollUptime(uptimeService *service.UptimeService, interval time.Duration) error {
+	nodeService := service.NewNode()
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.uptime.polling", interval)))
+
+	for {
+		nodes, err := nodeService.GetNodeList()
+		if err != nil {
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("common.fetch_nodes_failed", err)))
+		} else if err := uptimeService.Record(nodes, time.Now(), interval); err != nil {
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("cmd.uptime.record_failed", err)))
+		} else {
+			utils.DebugLog(1, "[Uptime] 已记录 %d 个节点状态", len(nodes))
+		}
+		time.Sleep(interval)
+	}
+}
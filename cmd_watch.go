@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "watch",
+		Usage: "cmd.watch.usage",
+		Run:   runWatch,
+		Flags: []commandFlag{
+			{Name: "-interval", Desc: "cmd.watch.flag_interval", Value: true},
+			{Name: "-samples", Desc: "cmd.watch.flag_samples", Value: true},
+		},
+	})
+}
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := fs.Duration("interval", 30*time.Second, utils.T("cmd.watch.flag_interval"))
+	samples := fs.Int("samples", 120, utils.T("cmd.watch.flag_samples"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval < 5*time.Second {
+		return errors.New(utils.T("cmd.watch.interval_too_small"))
+	}
+	if *samples < 2 {
+		return errors.New(utils.T("cmd.watch.samples_too_small"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return service.NewWatch(*interval, *samples).Run(ctx)
+}
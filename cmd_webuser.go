@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func init() {
+	registerCommand(cliCommand{
+		Name:     "web-user",
+		Usage:    "cmd.webuser.usage",
+		Run:      runWebUser,
+		Complete: completeWebUser,
+	})
+}
+
+func completeWebUser(args []string) []completion {
+	switch {
+	case len(args) == 0:
+		return completeWords("list", "add", "role", "passwd", "remove")
+	case len(args) == 1 && args[0] != "add" && args[0] != "list":
+		return completeWebUsers()
+	case len(args) == 2 && (args[0] == "add" || args[0] == "role"):
+		return completeWords(models.RoleViewer, models.RoleOperator, models.RoleAdmin)
+	}
+	return nil
+}
+
+func runWebUser(args []string) error {
+	cfg, err := service.NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	authService, err := service.NewWebAuth(cfg.Web)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 || args[0] == "list" {
+		users := authService.Users()
+		if len(users) == 0 {
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("cmd.webuser.none")))
+			return nil
+		}
+		table := utils.NewTable(utils.TableColumn{Title: utils.T("common.username")}, utils.TableColumn{Title: utils.T("cmd.webuser.col_role")})
+		for _, user := range users {
+			table.AddRow(user.Username, string(user.EffectiveRole()))
+		}
+		return table.Fprint(os.Stdout)
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf(utils.T("cmd.webuser.usage_user"), args[0])
+	}
+	username := args[1]
+
+	switch args[0] {
+	case "add":
+		role := ""
+		if len(args) > 2 {
+			role = args[2]
+		}
+		password, err := readNewPassword()
+		if err != nil {
+			return err
+		}
+		if err := authService.SaveUser(username, password, role, true); err != nil {
+			return err
+		}
+	case "passwd":
+		password, err := readNewPassword()
+		if err != nil {
+			return err
+		}
+		if err := authService.SaveUser(username, password, "", false); err != nil {
+			return err
+		}
+	case "role":
+		if len(args) < 3 {
+			return errors.New(utils.T("cmd.webuser.usage_role"))
+		}
+		if err := authService.SaveUser(username, "", args[2], false); err != nil {
+			return err
+		}
+	case "remove":
+		if err := authService.RemoveUser(username); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf(utils.T("cmd.webuser.unknown_action"), args[0])
+	}
+
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("cmd.webuser.updated", username)))
+	return nil
+}
+
+// readNewPassword 读取两次密码并确认一致
+func readNewPassword() (string, error) {
+	password, err := utils.ReadPassword(utils.T("cmd.webuser.password"))
+	if err != nil {
+		return "", err
+	}
+	confirm, err := utils.ReadPassword(utils.T("cmd.webuser.password_again"))
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", errors.New(utils.T("cmd.webuser.password_mismatch"))
+	}
+	return password, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// cliCommand 定义一个命令行子命令
+type cliCommand struct {
+	Name   string
+	Usage  string // 用法说明的消息键，命令在 init 中注册时还没有选择语言
+	Hidden bool
+	Run    func(args []string) error
+	Flags  []commandFlag // 命令支持的 flag，用于补全和生成手册页
+	// Complete 返回 args 之后下一个参数的补全候选项，未设置时补全 Flags
+	Complete func(args []string) []completion
+}
+
+// commandFlag 命令的 flag，Desc 是说明的消息键
+type commandFlag struct {
+	Name   string
+	Desc   string
+	Value  bool                // 是否需要取值
+	Values func() []completion // 取值的补全候选项，为 nil 时交给 shell 补全文件名
+}
+
+var commands = map[string]cliCommand{}
+
+func registerCommand(cmd cliCommand) {
+	commands[cmd.Name] = cmd
+}
+
+func init() {
+	registerCommand(cliCommand{
+		Name:  "help",
+		Usage: "cmd.help.usage",
+		Run:   runHelp,
+	})
+	registerCommand(cliCommand{
+		Name:  "menu",
+		Usage: "cmd.menu.usage",
+		Run: func(args []string) error {
+			runMenu()
+			return nil
+		},
+	})
+}
+
+// runCommand 执行子命令，返回进程退出码
+func runCommand(args []string) int {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Println(utils.ColorText(utils.RoleError, utils.T("cmd.unknown_command", args[0])))
+		runHelp(nil)
+		return 2
+	}
+	if err := cmd.Run(args[1:]); err != nil {
+		fmt.Println(utils.ColorText(utils.RoleError, fmt.Sprintf("❌ %v", err)))
+		return 1
+	}
+	return 0
+}
+
+// visibleCommands 返回按名称排序的非隐藏命令
+func visibleCommands() []string {
+	names := make([]string, 0, len(commands))
+	for name, cmd := range commands {
+		if !cmd.Hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitUsage 把用法说明拆分为命令格式和功能描述，两者之间以两个空格分隔
+func splitUsage(usage string) (synopsis, desc string) {
+	synopsis, desc, _ = strings.Cut(usage, "  ")
+	return strings.TrimSpace(synopsis), strings.TrimSpace(desc)
+}
+
+func runHelp(args []string) error {
+	fmt.Printf(utils.T("cmd.help.synopsis"), os.Args[0])
+	fmt.Println(utils.T("cmd.help.intro"))
+	for _, name := range visibleCommands() {
+		fmt.Printf("  %s\n", utils.T(commands[name].Usage))
+	}
+	return nil
+}
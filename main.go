@@ -5,73 +5,171 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"time"
 
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/service"
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
 )
 
 var debugLevel = 0 // 全局调试级别
 
-// 添加格式化字节的函数
-func formatBytes(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
+var commandArgs []string // 子命令及其参数
+
+var colorFlag, themeFlag, langFlag string // --color、--theme 和 --lang 参数，优先于配置文件
 
 func init() {
 	// 处理命令行参数
-	for _, arg := range os.Args[1:] {
-		switch arg {
-		case "debug":
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == completeCommand:
+			// 补全命令的参数是正在输入的命令行，原样交给补全逻辑
+			commandArgs = append(commandArgs, args[i:]...)
+			i = len(args)
+		case arg == "debug":
 			debugLevel = 1
-		case "debug-1":
+		case arg == "debug-1":
 			debugLevel = 1
-		case "debug-2":
+		case arg == "debug-2":
 			debugLevel = 2
+		case arg == "--no-color":
+			colorFlag = utils.ColorNever
+		case arg == "--color" || arg == "--theme" || arg == "--lang":
+			value := ""
+			if i+1 < len(args) {
+				value = args[i+1]
+				i++
+			}
+			switch arg {
+			case "--color":
+				colorFlag = value
+			case "--theme":
+				themeFlag = value
+			default:
+				langFlag = value
+			}
+		case strings.HasPrefix(arg, "--color="):
+			colorFlag = strings.TrimPrefix(arg, "--color=")
+		case strings.HasPrefix(arg, "--theme="):
+			themeFlag = strings.TrimPrefix(arg, "--theme=")
+		case strings.HasPrefix(arg, "--lang="):
+			langFlag = strings.TrimPrefix(arg, "--lang=")
+		default:
+			commandArgs = append(commandArgs, arg)
 		}
 	}
 }
 
+// setupUI 按命令行参数和配置文件设置界面语言、颜色模式与主题，需要在全屏界面接管标准输出之前调用。
+// 语言依次取 --lang、配置文件中的 ui.language 和 LANG 等环境变量
+func setupUI() {
+	ui := models.DefaultConfig().UI
+	if cfg, err := service.NewConfig().Load(); err == nil {
+		ui = cfg.UI
+	}
+	if langFlag != "" {
+		ui.Language = langFlag
+	}
+	if ui.Language == "" {
+		ui.Language = utils.DetectLocale()
+	}
+	if err := utils.SetLocale(ui.Language); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if colorFlag != "" {
+		ui.Color = colorFlag
+	}
+	if themeFlag != "" {
+		ui.Theme = themeFlag
+	}
+
+	if err := utils.SetColorMode(ui.Color); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if err := utils.SetTheme(ui.Theme, ui.Themes); err != nil {
+		fmt.Fprintln(os.Stderr, utils.ColorText(utils.RoleWarn, utils.T("main.theme_fallback", err)))
+	}
+}
+
 func main() {
 	// 设置调试级别
 	service.SetDebugLevel(debugLevel)
+	setupUI()
+
+	// 首次在终端中运行时通过向导完成登录和基本设置；已有配置文件但登录凭据为空时只进行登录
+	if len(commandArgs) == 0 && utils.IsTerminal() {
+		setup := service.NewSetup()
+		configMissing, credentialsMissing, err := setup.Missing()
+		switch {
+		case err != nil:
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("main.setup_check_failed", err)))
+		case configMissing:
+			if err := setup.Run(); err != nil {
+				fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("main.setup_failed", err)))
+			}
+			setupUI()
+		case credentialsMissing:
+			if err := setup.Login(); err != nil {
+				fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("main.setup_failed", err)))
+			}
+		}
+	}
+
+	// 带子命令时直接执行，不进入交互菜单
+	if len(commandArgs) > 0 {
+		os.Exit(runCommand(commandArgs))
+	}
 
+	// 终端中默认进入全屏界面，失败或输出被重定向时使用逐行菜单
+	if utils.IsTerminal() {
+		err := service.NewTUI().Run()
+		if err == nil {
+			fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.goodbye")))
+			return
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("main.tui_fallback", err)))
+	}
+	runMenu()
+}
+
+// runMenu 逐行打印的交互菜单
+func runMenu() {
 	reader := bufio.NewReader(os.Stdin)
 	commonService := service.NewCommon()
 	authService := service.NewAuth()
 	dashboardService := service.NewDashboard()
 	nodeService := service.NewNode()
+	var webService *service.WebService // 管理面板，在菜单中启动和关闭
 
 	for {
 		commonService.ClearScreen()
-		fmt.Println(utils.ColorText(utils.Bold+utils.Cyan, "\n欢迎使用OpenBMCLAPI系统!"))
-		fmt.Println(utils.ColorText(utils.Yellow, "0. GitHub登录"))
-		fmt.Println(utils.ColorText(utils.Green, "1. 查看用户信息"))
-		fmt.Println(utils.ColorText(utils.Green, "2. 查看系统状态"))
-		fmt.Println(utils.ColorText(utils.Green, "3. 查看节点列表"))
-		fmt.Println(utils.ColorText(utils.Green, "4. 查看节点排行榜"))
-		fmt.Println(utils.ColorText(utils.Green, "5. 打开管理面板"))
-		fmt.Println(utils.ColorText(utils.Red, "6. 退出程序"))
-		fmt.Print(utils.ColorText(utils.Purple, "请选择操作 (0-6): "))
+		fmt.Println(utils.ColorText(utils.RoleTitle, utils.T("main.welcome")))
+		fmt.Println(utils.ColorText(utils.RoleLabel, utils.T("main.menu_login")))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_profile")))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_dashboard")))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_nodes")))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_rank")))
+		if webService != nil && webService.Running() {
+			fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_panel_close")) + utils.ColorText(utils.RoleValue, utils.T("main.panel_running", webService.URL())))
+		} else {
+			fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.menu_panel_open")) + utils.ColorText(utils.RoleWarn, utils.T("main.panel_stopped")))
+		}
+		fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.menu_exit")))
+		fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("main.menu_prompt")))
 
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
 
 		switch choice {
 		case "0":
-			fmt.Println(utils.ColorText(utils.Yellow, "\n1. 使用浏览器登录"))
-			fmt.Println(utils.ColorText(utils.Yellow, "2. 直接粘贴 Cookie"))
-			fmt.Print(utils.ColorText(utils.Purple, "请选择登录方式 (1-2): "))
+			fmt.Println(utils.ColorText(utils.RoleLabel, utils.T("main.login_browser")))
+			fmt.Println(utils.ColorText(utils.RoleLabel, utils.T("main.login_cookie")))
+			fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("main.login_prompt")))
 
 			loginChoice, _ := reader.ReadString('\n')
 			loginChoice = strings.TrimSpace(loginChoice)
@@ -79,182 +177,269 @@ func main() {
 			switch loginChoice {
 			case "1":
 				// 使用固定的 Github 授权 URL
-				authURL := "https://github.com/login/oauth/authorize?response_type=code&redirect_uri=https%3A%2F%2Fbd.bangbang93.com%2Fcallback%2Flogin%2Fgithub&client_id=03132c1f1a1d46e078ea"
+				authURL := service.GithubAuthorizeURL
 
 				if err := authService.OpenBrowser(authURL); err != nil {
-					fmt.Printf(utils.ColorText(utils.Yellow, "无法自动打开浏览器，请手动访问以下链接：\n%s\n"), authURL)
+					fmt.Printf(utils.ColorText(utils.RoleWarn, utils.T("common.open_browser_manually")), authURL)
 				}
 
-				fmt.Print(utils.ColorText(utils.Cyan, "\n请将授权完成后的回调URL粘贴到这里: "))
+				fmt.Print(utils.ColorText(utils.RoleValue, utils.T("main.paste_callback")))
 				callbackURL, _ := reader.ReadString('\n')
 				callbackURL = strings.TrimSpace(callbackURL)
 
 				if code := authService.ExtractCode(callbackURL); code != "" {
 					// 直接使用回调URL进行验证
 					if err := authService.VerifyCallback(callbackURL); err != nil {
-						fmt.Println(utils.ColorText(utils.Red, fmt.Sprintf("❌ 验证失败: %v", err)))
+						fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.verify_failed", err)))
 					} else {
-						fmt.Println(utils.ColorText(utils.Green, "✓ 登录成功！"))
+						fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.login_ok")))
 					}
 				} else {
-					fmt.Println(utils.ColorText(utils.Red, "❌ 无法获取授权码，请重试"))
+					fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.no_auth_code")))
 				}
 
 			case "2":
-				fmt.Println(utils.ColorText(utils.Yellow, "\n请从浏览器复制 Cookie 并粘贴到这里:"))
-				fmt.Println(utils.ColorText(utils.Blue, "提示: 在浏览器中登录后，按 F12 打开开发者工具，在 Network 标签页中找到请求，复制 Cookie"))
+				fmt.Println(utils.ColorText(utils.RolePrompt, utils.T("main.paste_cookie")))
+				fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("main.cookie_hint")))
 				cookieStr, _ := reader.ReadString('\n')
 				cookieStr = strings.TrimSpace(cookieStr)
 
 				if err := authService.SaveBrowserCookies(cookieStr); err != nil {
-					fmt.Println(utils.ColorText(utils.Red, fmt.Sprintf("❌ 保存 Cookie 失败: %v", err)))
+					fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.save_cookie_failed", err)))
 				} else {
-					fmt.Println(utils.ColorText(utils.Green, "✓ 登录成功！"))
+					fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.login_ok")))
 				}
 
 			default:
-				fmt.Println(utils.ColorText(utils.Red, "❌ 无效的选择"))
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.invalid_choice")))
 			}
 
-			fmt.Print(utils.ColorText(utils.Yellow, "\n按回车键继续..."))
+			fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("common.press_enter")))
 			reader.ReadString('\n')
 		case "1":
 			profile, err := authService.GetUserProfile()
 			if err != nil {
-				fmt.Println(utils.ColorText(utils.Red, fmt.Sprintf("获取用户信息失败: %v", err)))
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.profile_failed", err)))
 			} else {
-				fmt.Println(utils.ColorText(utils.Green, "\n✓ 获取用户信息成功"))
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.profile_ok")))
 
 				// 尝试显示ASCII头像
 				if ascii, err := utils.ImageToAscii(profile.Avatar, 40); err == nil {
-					fmt.Println(utils.ColorText(utils.Blue, "\n头像预览:"))
-					fmt.Println(utils.ColorText(utils.Blue, ascii))
+					fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("main.avatar_preview")))
+					fmt.Println(utils.ColorText(utils.RoleInfo, ascii))
 				}
 
-				fmt.Printf(utils.ColorText(utils.Cyan, "用户名: %s\n"), profile.Name)
-				fmt.Printf(utils.ColorText(utils.Cyan, "GitHub ID: %s\n"), profile.Username)
-				fmt.Printf(utils.ColorText(utils.Cyan, "头像URL: %s\n"), profile.Avatar)
+				fmt.Printf(utils.ColorText(utils.RoleValue, utils.T("main.profile_name")), profile.Name)
+				fmt.Printf(utils.ColorText(utils.RoleValue, "GitHub ID: %s\n"), profile.Username)
+				fmt.Printf(utils.ColorText(utils.RoleValue, utils.T("main.profile_avatar")), profile.Avatar)
 				if profile.RawProfile.Bio != "" {
-					fmt.Printf(utils.ColorText(utils.Cyan, "简介: %s\n"), profile.RawProfile.Bio)
+					fmt.Printf(utils.ColorText(utils.RoleValue, utils.T("main.profile_bio")), profile.RawProfile.Bio)
 				}
 				if profile.RawProfile.Blog != "" {
-					fmt.Printf(utils.ColorText(utils.Cyan, "博客: %s\n"), profile.RawProfile.Blog)
+					fmt.Printf(utils.ColorText(utils.RoleValue, utils.T("main.profile_blog")), profile.RawProfile.Blog)
 				}
 			}
-			fmt.Print(utils.ColorText(utils.Yellow, "\n按回车键继续..."))
+			fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("common.press_enter")))
 			reader.ReadString('\n')
 		case "2":
 			dashboard, err := dashboardService.GetDashboard()
 			if err != nil {
-				fmt.Println(utils.ColorText(utils.Red, fmt.Sprintf("获取面板数据失败: %v", err)))
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("main.dashboard_failed", err)))
 			} else {
 				dashboardService.DisplayDashboard(dashboard)
 			}
-			fmt.Print(utils.ColorText(utils.Yellow, "\n按回车键继续..."))
+			fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("common.press_enter")))
 			reader.ReadString('\n')
 		case "3":
 			nodes, err := nodeService.GetNodeList()
 			if err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "获取节点列表失败: %v\n"), err)
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("main.nodes_failed")), err)
 				commonService.WaitForEnter()
 				continue
 			}
+			service.SaveNodeCache(nodes)
 			nodeService.DisplayAndSelectNode(nodes)
 		case "4":
 			ranks, err := nodeService.GetNodeMetricRank(context.Background())
 			if err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "获取排行榜失败: %v\n"), err)
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("main.rank_failed")), err)
 				commonService.WaitForEnter()
 				continue
 			}
-			showNodeRank(ranks)
+			// 获取自己的节点用于在排行榜中标记，失败时不标记
+			own := make(map[string]bool)
+			if nodes, err := nodeService.GetNodeList(); err == nil {
+				for _, node := range nodes {
+					own[node.ID] = true
+				}
+			}
+			showNodeRank(ranks, own)
 			commonService.WaitForEnter()
 		case "5":
-			webService := service.NewWeb(8080)
-			if err := webService.StartServer(); err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "启动 Web 服务器失败: %v\n"), err)
+			if webService != nil && webService.Running() {
+				stopWebService(webService)
 				commonService.WaitForEnter()
 				continue
 			}
-			fmt.Println(utils.ColorText(utils.Yellow, "\n按回车键关闭服务器..."))
-			reader.ReadString('\n')
+
+			cfg, err := service.NewConfig().Load()
+			if err != nil {
+				fmt.Println(utils.ColorText(utils.RoleError, err.Error()))
+				commonService.WaitForEnter()
+				continue
+			}
+			webService = service.NewWeb(cfg.PanelPort(), cfg)
+			webService.SetPortFallback(true)
+			if err := webService.StartServer(); err != nil {
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("main.web_start_failed")), err)
+			} else {
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.panel_started")))
+			}
+			commonService.WaitForEnter()
 		case "6":
-			fmt.Println(utils.ColorText(utils.Green, "感谢使用，再见！"))
+			if webService != nil && webService.Running() {
+				stopWebService(webService)
+			}
+			fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.goodbye")))
 			return
 		default:
-			fmt.Println(utils.ColorText(utils.Red, "无效的选择，请重试"))
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("common.invalid_choice_retry")))
 			commonService.WaitForEnter()
 		}
 	}
 }
 
-func showNodeRank(ranks []service.NodeMetricRank) {
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📊 节点排行榜"))
-	fmt.Println(strings.Repeat("─", 100))
+// stopWebService 关闭管理面板，最多等待 5 秒让进行中的请求完成
+func stopWebService(webService *service.WebService) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := webService.Shutdown(ctx); err != nil {
+		fmt.Printf(utils.ColorText(utils.RoleError, utils.T("main.web_stop_failed")), err)
+		return
+	}
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("common.panel_closed")))
+}
 
-	// 分页显示
+// showNodeRank 分页显示排行榜，支持搜索、跳页和定位自己的节点
+func showNodeRank(ranks []service.NodeMetricRank, own map[string]bool) {
+	reader := bufio.NewReader(os.Stdin)
+	commonService := service.NewCommon()
 	pageSize := 10 // 每页显示的数量
-	totalPages := (len(ranks) + pageSize - 1) / pageSize
 	currentPage := 0
+	query := ""
+	ownCursor := -1 // 上一次按 m 跳到的自己节点在结果中的位置
+	message := ""
 
 	for {
-		commonService := service.NewCommon()
+		results := service.FilterRanks(query, ranks)
+		totalPages := max((len(results)+pageSize-1)/pageSize, 1)
+		currentPage = min(max(currentPage, 0), totalPages-1)
+
 		commonService.ClearScreen()
-		fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📊 节点排行榜"))
+		fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("main.rank_title")))
 		fmt.Println(strings.Repeat("─", 100))
 
-		// 显示表头
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, utils.ColorText(utils.Bold, "序号\t节点名称\t请求数\t流量\t状态\t赞助商"))
-		fmt.Fprintln(w, strings.Repeat("─", 100))
-
-		// 计算当前页的起始和结束索引
-		start := currentPage * pageSize
-		end := start + pageSize
-		if end > len(ranks) {
-			end = len(ranks)
-		}
+		table := utils.NewTable(
+			utils.TableColumn{Title: utils.T("common.rank"), Right: true},
+			utils.TableColumn{Title: utils.T("common.node_name"), MinWidth: 12},
+			utils.TableColumn{Title: utils.T("common.hits"), Right: true},
+			utils.TableColumn{Title: utils.T("common.traffic"), Right: true},
+			utils.TableColumn{Title: utils.T("common.status")},
+			utils.TableColumn{Title: utils.T("common.sponsor")},
+		)
 
 		// 显示当前页的数据
-		for i, rank := range ranks[start:end] {
-			status := utils.ColorText(utils.Green, "在线")
+		start := currentPage * pageSize
+		end := min(start+pageSize, len(results))
+		for _, r := range results[start:end] {
+			rank := ranks[r.Index]
+			status := utils.ColorText(utils.RoleOK, utils.T("common.online"))
 			if !rank.IsEnabled {
-				status = utils.ColorText(utils.Red, "离线")
+				status = utils.ColorText(utils.RoleError, utils.T("common.offline"))
+			}
+			name := utils.ColorText(utils.RoleValue, rank.Name)
+			if query != "" {
+				name = r.Match.Decorate(rank.Name)
+			}
+			if own[rank.ID] {
+				name = utils.ColorText(utils.RoleOK, "★ ") + name
 			}
 
-			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
-				start+i+1,
-				utils.ColorText(utils.Cyan, rank.Name),
-				utils.ColorText(utils.Yellow, fmt.Sprintf("%d", rank.Metric.Hits)),
-				utils.ColorText(utils.Purple, formatBytes(rank.Metric.Bytes)),
+			table.AddRow(
+				fmt.Sprintf("%d", r.Index+1),
+				name,
+				utils.ColorText(utils.RoleValue, utils.FormatNumber(rank.Metric.Hits)),
+				utils.ColorText(utils.RolePrompt, utils.FormatBytes(rank.Metric.Bytes)),
 				status,
 				rank.Sponsor.Name,
 			)
 		}
-		w.Flush()
+		table.Print()
 
 		// 显示分页信息和操作提示
-		fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, fmt.Sprintf("第 %d/%d 页 (共 %d 条记录)", currentPage+1, totalPages, len(ranks))))
-		fmt.Println("\n操作说明:")
-		fmt.Println(utils.ColorText(utils.Green, "n") + ": 下一页")
-		fmt.Println(utils.ColorText(utils.Green, "p") + ": 上一页")
-		fmt.Println(utils.ColorText(utils.Green, "q") + ": 返回主菜单")
-		fmt.Print("\n请输入操作: ")
-
-		var input string
-		fmt.Scanln(&input)
-
-		switch input {
-		case "n":
+		pageInfo := utils.T("main.rank_page", currentPage+1, totalPages, len(results))
+		if query != "" {
+			pageInfo = utils.T("main.rank_page_filtered", currentPage+1, totalPages, query, len(results), len(ranks))
+		}
+		fmt.Printf("\n%s\n", utils.ColorText(utils.RoleInfo, pageInfo))
+		if message != "" {
+			fmt.Println(message)
+			message = ""
+		}
+		fmt.Println(utils.T("main.rank_help"))
+		fmt.Println(utils.ColorText(utils.RoleOK, "n") + utils.T("main.rank_help_next"))
+		fmt.Println(utils.ColorText(utils.RoleOK, "p") + utils.T("main.rank_help_prev"))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.rank_help_number")) + utils.T("main.rank_help_goto"))
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("main.rank_help_keyword")) + utils.T("main.rank_help_search"))
+		fmt.Println(utils.ColorText(utils.RoleOK, "m") + utils.T("main.rank_help_own"))
+		fmt.Println(utils.ColorText(utils.RoleOK, "q") + utils.T("main.rank_help_quit"))
+		fmt.Print(utils.T("main.rank_prompt"))
+
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch {
+		case input == "n":
 			if currentPage < totalPages-1 {
 				currentPage++
 			}
-		case "p":
+		case input == "p":
 			if currentPage > 0 {
 				currentPage--
 			}
-		case "q":
+		case input == "q":
 			return
+		case input == "m":
+			var positions []int
+			for i, r := range results {
+				if own[ranks[r.Index].ID] {
+					positions = append(positions, i)
+				}
+			}
+			if len(positions) == 0 {
+				message = utils.ColorText(utils.RoleWarn, utils.T("main.rank_no_own"))
+				continue
+			}
+			next := positions[0]
+			for _, p := range positions {
+				if p > ownCursor {
+					next = p
+					break
+				}
+			}
+			ownCursor = next
+			currentPage = next / pageSize
+			message = utils.ColorText(utils.RoleOK, utils.T("main.rank_own", ranks[results[next].Index].Name, results[next].Index+1))
+		case strings.HasPrefix(input, "/"):
+			query = strings.TrimSpace(strings.TrimPrefix(input, "/"))
+			currentPage, ownCursor = 0, -1
+		default:
+			page, err := strconv.Atoi(input)
+			if err != nil || page < 1 || page > totalPages {
+				message = utils.ColorText(utils.RoleError, utils.T("main.rank_invalid", totalPages))
+				continue
+			}
+			currentPage = page - 1
 		}
 	}
 }
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// 告警级别
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert 定义一条告警
+type Alert struct {
+	Rule        string    `json:"rule"`
+	Severity    string    `json:"severity"`
+	Cluster     string    `json:"cluster,omitempty"`
+	ClusterName string    `json:"clusterName,omitempty"`
+	Title       string    `json:"title"`
+	Message     string    `json:"message"`
+	Time        time.Time `json:"time"`
+	Resolved    bool      `json:"resolved,omitempty"`
+}
+
+// NotifyChannel 定义一个通知渠道
+type NotifyChannel struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"` // webhook, discord, slack, telegram, dingtalk, feishu, wecom, email
+	Disabled   bool     `json:"disabled,omitempty"`
+	Severities []string `json:"severities,omitempty"` // 为空时接收所有级别
+	Clusters   []string `json:"clusters,omitempty"`   // 节点 ID 或名称，为空时接收所有节点
+	Template   string   `json:"template,omitempty"`   // text/template 格式的消息模板
+	URL        string   `json:"url,omitempty"`        // webhook 地址，telegram 为 API 地址
+	Secret     string   `json:"secret,omitempty"`     // 钉钉/飞书加签密钥
+	Token      string   `json:"token,omitempty"`      // telegram bot token
+	ChatID     string   `json:"chatId,omitempty"`     // telegram chat id
+
+	// SMTP 邮件配置
+	Host     string   `json:"host,omitempty"`
+	Port     int      `json:"port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+	Subject  string   `json:"subject,omitempty"` // text/template 格式的邮件标题
+	TLS      bool     `json:"tls,omitempty"`     // 使用隐式 TLS (通常为 465 端口)
+}
+
+// NotifyConfig 定义通知配置
+type NotifyConfig struct {
+	Retries    int             `json:"retries"`
+	RetryDelay int             `json:"retryDelay"` // 秒
+	Channels   []NotifyChannel `json:"channels"`
+}
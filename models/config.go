@@ -0,0 +1,53 @@
+package models
+
+// Config 定义本地配置文件结构
+type Config struct {
+	DataDir       string       `json:"dataDir"`
+	WatchClusters []string     `json:"watchClusters,omitempty"` // 额外关注的节点 ID 或名称
+	Notify        NotifyConfig `json:"notify"`
+	Reports       []ReportJob  `json:"reports,omitempty"`
+	Daemon        DaemonConfig `json:"daemon"`
+	Web           WebConfig    `json:"web"`
+	UI            UIConfig     `json:"ui"`
+}
+
+// DefaultPanelPort 交互界面中打开管理面板时，未配置 daemon.webPort 则使用该端口
+const DefaultPanelPort = 8080
+
+// PanelPort 返回交互界面中打开管理面板使用的端口
+func (c *Config) PanelPort() int {
+	if c.Daemon.WebPort > 0 {
+		return c.Daemon.WebPort
+	}
+	return DefaultPanelPort
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		DataDir: "data",
+		Notify: NotifyConfig{
+			Retries:    3,
+			RetryDelay: 5,
+		},
+		Daemon: DaemonConfig{
+			WebPort:      DefaultPanelPort,
+			Collect:      "@every 1m",
+			Alerts:       "@every 1m",
+			RankSnapshot: "55 23 * * *",
+		},
+		Web: WebConfig{
+			Listen:     "127.0.0.1",
+			SessionTTL: 24,
+			AccessLog: WebAccessLogConfig{
+				Format:     AccessLogJSON,
+				MaxSizeMB:  10,
+				MaxBackups: 5,
+			},
+		},
+		UI: UIConfig{
+			Color: "auto",
+			Theme: "dark",
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// DaemonConfig 定义 daemon 模式的配置，计划任务使用 cron 表达式，留空表示不启用
+type DaemonConfig struct {
+	PidFile      string `json:"pidFile,omitempty"` // 默认为 <dataDir>/daemon.pid
+	WebPort      int    `json:"webPort"`           // 0 表示不启动 Web 服务器
+	Collect      string `json:"collect"`           // 采集节点状态
+	Alerts       string `json:"alerts"`            // 评估告警
+	RankSnapshot string `json:"rankSnapshot"`      // 保存排行榜快照
+}
+
+// JobStatus 定义计划任务的运行状态
+type JobStatus struct {
+	Name         string    `json:"name"`
+	Schedule     string    `json:"schedule"`
+	Running      bool      `json:"running"`
+	LastRun      time.Time `json:"lastRun"`
+	NextRun      time.Time `json:"nextRun"`
+	LastDuration int64     `json:"lastDuration"` // 毫秒
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+// DaemonStatus 定义 daemon 的运行状态
+type DaemonStatus struct {
+	Pid       int         `json:"pid"`
+	StartedAt time.Time   `json:"startedAt"`
+	Jobs      []JobStatus `json:"jobs"`
+}
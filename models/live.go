@@ -0,0 +1,17 @@
+package models
+
+// NodeStatusChange 描述一次轮询中节点状态的变化
+type NodeStatusChange struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"` // online、offline、banned、unbanned、added、removed
+	IsEnabled bool   `json:"isEnabled"`
+	IsBanned  bool   `json:"isBanned"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// NodesEvent 定义推送给浏览器的节点列表更新
+type NodesEvent struct {
+	Nodes   []Node             `json:"nodes"`
+	Changes []NodeStatusChange `json:"changes"`
+}
@@ -44,3 +44,26 @@ type Node struct {
 	BanReason        string       `json:"banReason,omitempty"`
 	IsBanned         bool         `json:"isBanned"`
 }
+
+// NodeDetail 节点详情以及根据节点数据计算的指标
+type NodeDetail struct {
+	Node
+	OnlineSeconds            int64          `json:"onlineSeconds"`            // 本次上线以来的秒数，离线时为 0
+	SinceLastActivitySeconds int64          `json:"sinceLastActivitySeconds"` // 距最后活动的秒数，没有记录时为 -1
+	BandwidthUtilization     *float64       `json:"bandwidthUtilization"`     // 今日平均出网带宽 / 节点声明带宽
+	TodayRank                *NodeTodayRank `json:"todayRank"`                // 今日排行，排行榜中没有该节点时为 null
+}
+
+// NodeTodayRank 节点在今日排行榜中的位置和数据
+type NodeTodayRank struct {
+	Rank  int   `json:"rank"` // 按流量排序，从 1 开始
+	Total int   `json:"total"`
+	Bytes int64 `json:"bytes"`
+	Hits  int64 `json:"hits"`
+}
+
+// NodeCache 本地缓存的节点列表，供命令行补全等不便访问网络的场景使用
+type NodeCache struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	Nodes     []Node    `json:"nodes"`
+}
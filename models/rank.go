@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// RankEntry 定义排行榜快照中的一条记录
+type RankEntry struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Rank      int    `json:"rank"`
+	Hits      int64  `json:"hits"`
+	Bytes     int64  `json:"bytes"`
+	IsEnabled bool   `json:"isEnabled"`
+}
+
+// RankSnapshot 定义某一天的排行榜快照
+type RankSnapshot struct {
+	Date    string      `json:"date"` // 2006-01-02
+	Time    time.Time   `json:"time"`
+	Entries []RankEntry `json:"entries"`
+}
+
+// RankMovement 定义节点相对历史快照的排名与流量变化
+type RankMovement struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Rank       int    `json:"rank"`
+	PrevRank   int    `json:"prevRank"`   // 0 表示历史快照中不存在
+	RankChange int    `json:"rankChange"` // 正数表示排名上升
+	Hits       int64  `json:"hits"`
+	HitsDelta  int64  `json:"hitsDelta"`
+	Bytes      int64  `json:"bytes"`
+	BytesDelta int64  `json:"bytesDelta"`
+}
+
+// RankReport 定义排名变化报告
+type RankReport struct {
+	Date      string         `json:"date"`
+	Time      time.Time      `json:"time"` // 快照保存时间，当天未结束时数据为截至该时间的累计值
+	Yesterday string         `json:"yesterday,omitempty"`
+	LastWeek  string         `json:"lastWeek,omitempty"`
+	Tracked   []RankTracked  `json:"tracked"`
+	Risers    []RankMovement `json:"risers"`
+	Fallers   []RankMovement `json:"fallers"`
+	Missing   []string       `json:"missing,omitempty"` // 未出现在排行榜中的关注节点
+}
+
+// RankTracked 定义关注节点相对昨日与上周的变化
+type RankTracked struct {
+	Own       bool          `json:"own"`
+	Current   RankEntry     `json:"current"`
+	Yesterday *RankMovement `json:"yesterday,omitempty"`
+	LastWeek  *RankMovement `json:"lastWeek,omitempty"`
+}
+
+// RankHistoryPoint 定义节点在某一天快照中的排名与流量，Rank 为 0 表示当天未上榜
+type RankHistoryPoint struct {
+	Date  string `json:"date"`
+	Rank  int    `json:"rank"`
+	Hits  int64  `json:"hits"`
+	Bytes int64  `json:"bytes"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ReportJob 定义一个报告任务
+type ReportJob struct {
+	Name      string   `json:"name"`
+	Schedule  string   `json:"schedule,omitempty"` // cron 表达式，daemon 模式下按计划执行
+	Period    string   `json:"period"`             // daily 或 weekly
+	Formats   []string `json:"formats,omitempty"`  // markdown, html, text，默认全部
+	OutputDir string   `json:"outputDir,omitempty"`
+	Channels  []string `json:"channels,omitempty"` // 发送报告的通知渠道名称
+}
+
+// ReportDay 定义节点某一天的排行榜数据
+type ReportDay struct {
+	Date  string `json:"date"`
+	Rank  int    `json:"rank"` // 0 表示当天没有快照或未上榜
+	Hits  int64  `json:"hits"`
+	Bytes int64  `json:"bytes"`
+}
+
+// ReportCluster 定义报告中单个节点的汇总
+type ReportCluster struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Own        bool         `json:"own"`
+	Days       []ReportDay  `json:"days"`
+	TotalHits  int64        `json:"totalHits"`
+	TotalBytes int64        `json:"totalBytes"`
+	StartRank  int          `json:"startRank"`
+	EndRank    int          `json:"endRank"`
+	Uptime     UptimeReport `json:"uptime"`
+	Outages    []Outage     `json:"outages"`
+}
+
+// Report 定义一份周期报告
+type Report struct {
+	Title       string          `json:"title"`
+	From        string          `json:"from"` // 2006-01-02，含当天
+	To          string          `json:"to"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Dashboard   *Dashboard      `json:"dashboard,omitempty"`
+	Clusters    []ReportCluster `json:"clusters"`
+	Dates       []string        `json:"dates"`
+	DailyBytes  []int64         `json:"dailyBytes"` // 自己节点每日流量合计
+	TotalHits   int64           `json:"totalHits"`
+	TotalBytes  int64           `json:"totalBytes"`
+	Notes       []string        `json:"notes,omitempty"`
+}
@@ -4,10 +4,11 @@ import "time"
 
 // APIResponse 定义统一的API响应格式
 type APIResponse struct {
-	Code int         `json:"code"`
-	Msg  string      `json:"msg"`
-	Data interface{} `json:"data"`
-	Time int64       `json:"time"`
+	Code      int         `json:"code"`
+	Msg       string      `json:"msg"`
+	Data      interface{} `json:"data"`
+	Time      int64       `json:"time"`
+	RequestID string      `json:"requestId,omitempty"` // 出错时返回，便于在访问日志中查找对应请求
 }
 
 // ResponseSuccess 返回成功响应
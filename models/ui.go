@@ -0,0 +1,9 @@
+package models
+
+// UIConfig 定义终端输出的语言、颜色和主题
+type UIConfig struct {
+	Language string                       `json:"language,omitempty"` // zh-CN 或 en，为空时按 LC_ALL、LC_MESSAGES、LANG 环境变量选择
+	Color    string                       `json:"color"`              // auto、always 或 never，auto 时遵循 NO_COLOR 并在输出不是终端时关闭颜色
+	Theme    string                       `json:"theme"`              // dark、light、high-contrast、colorblind-safe 或自定义主题名称
+	Themes   map[string]map[string]string `json:"themes,omitempty"`   // 自定义主题：角色名 (ok、warn、error、label、value 等) 到颜色的映射，"base" 指定继承的内置主题
+}
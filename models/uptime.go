@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Outage 定义一次节点离线区间
+type Outage struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end,omitempty"` // 为零值表示仍在离线
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Gap 定义一段没有轮询数据的区间 (如 daemon 未运行)，状态未知，不计入可用性
+type Gap struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ClusterUptime 记录单个节点的轮询历史
+type ClusterUptime struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastPoll  time.Time `json:"lastPoll"`
+	IsEnabled bool      `json:"isEnabled"`
+	Outages   []Outage  `json:"outages"`
+	Gaps      []Gap     `json:"gaps,omitempty"`
+}
+
+// UptimeReport 定义节点在某个时间窗口内的可用性统计，时长单位均为秒
+type UptimeReport struct {
+	Window       string  `json:"window"`
+	Observed     int64   `json:"observed"` // 不含 Unknown
+	Unknown      int64   `json:"unknown"`  // 轮询中断、状态未知的时长
+	Downtime     int64   `json:"downtime"`
+	Availability float64 `json:"availability"` // 百分比，无观测数据时为 -1
+	Failures     int     `json:"failures"`
+	MTBF         int64   `json:"mtbf"`
+	MTTR         int64   `json:"mttr"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// WebConfig 定义本地 Web 管理面板的配置
+type WebConfig struct {
+	Listen     string             `json:"listen"`          // 监听地址，默认只监听 127.0.0.1
+	Expose     bool               `json:"expose"`          // 允许监听非本机地址，需要显式开启
+	SessionTTL int                `json:"sessionTtl"`      // 登录会话有效期 (小时)
+	Users      []WebUser          `json:"users,omitempty"` // 本地账号，未配置时只能使用启动时生成的访问令牌
+	TLS        WebTLSConfig       `json:"tls"`
+	CORS       []string           `json:"corsOrigins,omitempty"` // 允许跨域调用 API 的来源，如 https://grafana.example.com，"*" 表示任意来源
+	AccessLog  WebAccessLogConfig `json:"accessLog"`
+}
+
+// 访问日志格式
+const (
+	AccessLogJSON     = "json"     // 每行一个 JSON 对象
+	AccessLogCombined = "combined" // Apache/Nginx combined 格式，末尾附加请求 ID 和耗时 (毫秒)
+)
+
+// WebAccessLogConfig 定义访问日志配置
+type WebAccessLogConfig struct {
+	File       string `json:"file,omitempty"` // 日志文件路径，为空时只在 serve 模式下输出到标准输出
+	Format     string `json:"format"`         // json 或 combined
+	MaxSizeMB  int    `json:"maxSizeMB"`      // 单个文件的大小上限，超过后轮转
+	MaxBackups int    `json:"maxBackups"`     // 保留的旧日志文件数量
+}
+
+// HealthCheck 定义一项就绪检查的结果
+type HealthCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Latency int64  `json:"latency"` // 毫秒
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthStatus 定义就绪检查的汇总结果
+type HealthStatus struct {
+	Ready     bool          `json:"ready"`
+	CheckedAt time.Time     `json:"checkedAt"`
+	Checks    []HealthCheck `json:"checks,omitempty"` // 公开的就绪探针不返回各项检查
+}
+
+// WebTLSConfig 定义 HTTPS 配置
+type WebTLSConfig struct {
+	Enabled      bool     `json:"enabled"`
+	CertFile     string   `json:"certFile,omitempty"` // 证书和私钥都为空时自动生成自签名证书
+	KeyFile      string   `json:"keyFile,omitempty"`
+	Hosts        []string `json:"hosts,omitempty"`        // 自签名证书包含的主机名或 IP，默认 localhost 和本机回环地址
+	RedirectPort int      `json:"redirectPort,omitempty"` // 非 0 时在该端口把 HTTP 请求跳转到 HTTPS
+	HSTS         bool     `json:"hsts"`                   // 通过 HTTPS 访问时发送 Strict-Transport-Security 头
+}
+
+// 管理面板账号角色
+const (
+	RoleViewer   = "viewer"   // 只能查看
+	RoleOperator = "operator" // 可以修改节点信息
+	RoleAdmin    = "admin"    // 可以重置密钥和管理账号
+)
+
+// 角色对应的操作权限
+const (
+	CapView            = "view"
+	CapNodeUpdate      = "node:update"
+	CapNodeResetSecret = "node:reset-secret"
+	CapUserManage      = "user:manage"
+)
+
+// RoleCapabilities 定义每个角色拥有的权限
+var RoleCapabilities = map[string][]string{
+	RoleViewer:   {CapView},
+	RoleOperator: {CapView, CapNodeUpdate},
+	RoleAdmin:    {CapView, CapNodeUpdate, CapNodeResetSecret, CapUserManage},
+}
+
+// WebUser 定义管理面板的本地账号
+type WebUser struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"passwordHash"`   // bcrypt 哈希
+	Role         string `json:"role,omitempty"` // 未设置时视为 viewer
+}
+
+// EffectiveRole 返回账号角色，未设置或无效时为 viewer
+func (u WebUser) EffectiveRole() string {
+	if _, ok := RoleCapabilities[u.Role]; ok {
+		return u.Role
+	}
+	return RoleViewer
+}
+
+// WebSession 定义登录会话信息
+type WebSession struct {
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	Capabilities []string  `json:"capabilities"`
+	CSRFToken    string    `json:"csrfToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// Can 判断会话是否拥有指定权限
+func (s *WebSession) Can(capability string) bool {
+	for _, c := range s.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 内置告警规则
+const (
+	AlertRuleOffline = "cluster-offline"
+	AlertRuleBanned  = "cluster-banned"
+	AlertRuleVersion = "cluster-version"
+)
+
+// clusterState 记录上一次评估时的节点状态，用于检测状态变化
+type clusterState struct {
+	Name       string `json:"name"`
+	IsEnabled  bool   `json:"isEnabled"`
+	IsBanned   bool   `json:"isBanned"`
+	Version    string `json:"version"`
+	DownReason string `json:"downReason,omitempty"`
+}
+
+type AlertService struct {
+	dataDir string
+	notify  *NotifyService
+
+	// Evaluate 读取的状态和评估后的状态，由 Dispatch 在发送后保存
+	previous map[string]clusterState
+	next     map[string]clusterState
+}
+
+func NewAlert(dataDir string, notify *NotifyService) *AlertService {
+	return &AlertService{
+		dataDir: dataDir,
+		notify:  notify,
+	}
+}
+
+func (s *AlertService) path() string {
+	return filepath.Join(s.dataDir, "alert_state.json")
+}
+
+func (s *AlertService) load() (map[string]clusterState, error) {
+	states := make(map[string]clusterState)
+
+	data, err := ioutil.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return states, nil
+		}
+		return nil, fmt.Errorf(utils.T("alert.read_state_failed"), err)
+	}
+
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf(utils.T("alert.parse_state_failed"), err)
+	}
+	return states, nil
+}
+
+func (s *AlertService) save(states map[string]clusterState) error {
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return fmt.Errorf(utils.T("common.mkdir_data_failed"), err)
+	}
+
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf(utils.T("alert.marshal_state_failed"), err)
+	}
+	if err := ioutil.WriteFile(s.path(), data, 0644); err != nil {
+		return fmt.Errorf(utils.T("alert.save_state_failed"), err)
+	}
+	return nil
+}
+
+// Evaluate 对比上一次的节点状态，返回需要发送的告警，评估后的状态由 Dispatch 保存
+// 首次见到的节点只记录状态，不产生告警；不在节点列表中的节点从状态中移除
+func (s *AlertService) Evaluate(nodes []models.Node, at time.Time) ([]models.Alert, error) {
+	states, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	next := make(map[string]clusterState, len(nodes))
+	var alerts []models.Alert
+	for _, node := range nodes {
+		current := clusterState{
+			Name:       node.Name,
+			IsEnabled:  node.IsEnabled,
+			IsBanned:   node.IsBanned,
+			Version:    node.Version,
+			DownReason: node.DownReason,
+		}
+		prev, seen := states[node.ID]
+		next[node.ID] = current
+		if !seen {
+			continue
+		}
+
+		alert := models.Alert{
+			Cluster:     node.ID,
+			ClusterName: node.Name,
+			Time:        at,
+		}
+
+		switch {
+		case prev.IsEnabled && !node.IsEnabled:
+			alert.Rule = AlertRuleOffline
+			alert.Severity = models.SeverityCritical
+			alert.Title = utils.T("alert.offline_title", node.Name)
+			alert.Message = utils.T("alert.offline_message")
+			if node.DownReason != "" {
+				alert.Message = utils.T("alert.offline_reason", node.DownReason)
+			}
+			alerts = append(alerts, alert)
+		case !prev.IsEnabled && node.IsEnabled:
+			alert.Rule = AlertRuleOffline
+			alert.Severity = models.SeverityInfo
+			alert.Title = utils.T("alert.online_title", node.Name)
+			alert.Message = utils.T("alert.online_message")
+			alert.Resolved = true
+			alerts = append(alerts, alert)
+		}
+
+		alert.Resolved = false
+		switch {
+		case !prev.IsBanned && node.IsBanned:
+			alert.Rule = AlertRuleBanned
+			alert.Severity = models.SeverityCritical
+			alert.Title = utils.T("alert.banned_title", node.Name)
+			alert.Message = utils.T("alert.banned_message")
+			if node.BanReason != "" {
+				alert.Message = utils.T("alert.ban_reason", node.BanReason)
+			}
+			alerts = append(alerts, alert)
+		case prev.IsBanned && !node.IsBanned:
+			alert.Rule = AlertRuleBanned
+			alert.Severity = models.SeverityInfo
+			alert.Title = utils.T("alert.unbanned_title", node.Name)
+			alert.Message = utils.T("alert.unbanned_message")
+			alert.Resolved = true
+			alerts = append(alerts, alert)
+		}
+
+		if prev.Version != "" && node.Version != "" && prev.Version != node.Version {
+			alert.Rule = AlertRuleVersion
+			alert.Severity = models.SeverityInfo
+			alert.Title = utils.T("alert.version_title", node.Name)
+			alert.Message = fmt.Sprintf("%s → %s", prev.Version, node.Version)
+			alert.Resolved = false
+			alerts = append(alerts, alert)
+		}
+	}
+
+	s.previous, s.next = states, next
+	return alerts, nil
+}
+
+// Dispatch 将告警发送到匹配的通知渠道，然后保存 Evaluate 评估后的状态
+// 发送失败的告警对应的状态保留为上一次的值，下次评估时重新产生该告警
+func (s *AlertService) Dispatch(alerts []models.Alert) error {
+	var errs []error
+	for _, alert := range alerts {
+		if err := s.notify.Notify(alert); err != nil {
+			errs = append(errs, err)
+			s.keepPrevious(alert)
+		}
+	}
+
+	var err error
+	if len(errs) > 0 {
+		err = fmt.Errorf(utils.T("alert.send_failed"), len(alerts), len(errs), errs[0])
+	}
+	if s.next != nil {
+		if saveErr := s.save(s.next); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+	}
+	return err
+}
+
+// keepPrevious 将告警规则涉及的状态恢复为上一次评估时的值
+func (s *AlertService) keepPrevious(alert models.Alert) {
+	prev, ok := s.previous[alert.Cluster]
+	current, exists := s.next[alert.Cluster]
+	if !ok || !exists {
+		return
+	}
+	switch alert.Rule {
+	case AlertRuleOffline:
+		current.IsEnabled = prev.IsEnabled
+		current.DownReason = prev.DownReason
+	case AlertRuleBanned:
+		current.IsBanned = prev.IsBanned
+	case AlertRuleVersion:
+		current.Version = prev.Version
+	}
+	s.next[alert.Cluster] = current
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+// alertSink 记录 webhook 收到的告警，fail 为 true 时返回 500
+type alertSink struct {
+	mu     sync.Mutex
+	fail   bool
+	alerts []models.Alert
+}
+
+func (s *alertSink) setFail(fail bool) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+func newAlertService(t *testing.T) (*AlertService, *alertSink) {
+	t.Helper()
+	sink := &alertSink{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		if sink.fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var alert models.Alert
+		json.NewDecoder(r.Body).Decode(&alert)
+		sink.alerts = append(sink.alerts, alert)
+	}))
+	t.Cleanup(server.Close)
+
+	notify := NewNotify(models.NotifyConfig{Channels: []models.NotifyChannel{
+		{Name: "hook", Type: "webhook", URL: server.URL},
+	}})
+	return NewAlert(t.TempDir(), notify), sink
+}
+
+// evaluate 评估一轮节点状态并发送告警，返回产生的告警和发送错误
+func evaluate(t *testing.T, s *AlertService, nodes ...models.Node) ([]models.Alert, error) {
+	t.Helper()
+	alerts, err := s.Evaluate(nodes, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return alerts, s.Dispatch(alerts)
+}
+
+// rules 返回告警的规则和是否已恢复，便于比较
+func rules(alerts []models.Alert) []string {
+	var out []string
+	for _, alert := range alerts {
+		rule := alert.Rule
+		if alert.Resolved {
+			rule += "/resolved"
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+func sameRules(got []models.Alert, want ...string) bool {
+	r := rules(got)
+	if len(r) != len(want) {
+		return false
+	}
+	for i := range r {
+		if r[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEvaluateStateChanges(t *testing.T) {
+	s, sink := newAlertService(t)
+	node := models.Node{ID: "n1", Name: "node-1", IsEnabled: true, Version: "1.10.0"}
+
+	// 首次见到的节点只记录状态
+	if alerts, err := evaluate(t, s, node); err != nil || len(alerts) != 0 {
+		t.Fatalf("首次见到节点不应产生告警，实际为 %v (%v)", rules(alerts), err)
+	}
+
+	steps := []struct {
+		name   string
+		change func(n *models.Node)
+		want   []string
+	}{
+		{"离线", func(n *models.Node) { n.IsEnabled = false; n.DownReason = "timeout" }, []string{AlertRuleOffline}},
+		{"状态不变", func(n *models.Node) {}, nil},
+		{"恢复在线", func(n *models.Node) { n.IsEnabled = true; n.DownReason = "" }, []string{AlertRuleOffline + "/resolved"}},
+		{"封禁", func(n *models.Node) { n.IsBanned = true; n.BanReason = "abuse" }, []string{AlertRuleBanned}},
+		{"解封", func(n *models.Node) { n.IsBanned = false }, []string{AlertRuleBanned + "/resolved"}},
+		{"版本变化", func(n *models.Node) { n.Version = "1.11.0" }, []string{AlertRuleVersion}},
+		{"离线且版本变化", func(n *models.Node) { n.IsEnabled = false; n.Version = "1.12.0" }, []string{AlertRuleOffline, AlertRuleVersion}},
+	}
+	for _, step := range steps {
+		step.change(&node)
+		alerts, err := evaluate(t, s, node)
+		if err != nil {
+			t.Fatalf("%s: 发送失败: %v", step.name, err)
+		}
+		if !sameRules(alerts, step.want...) {
+			t.Errorf("%s: 告警应为 %v，实际为 %v", step.name, step.want, rules(alerts))
+		}
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.alerts) != 7 || sink.alerts[0].Cluster != node.ID {
+		t.Errorf("webhook 应收到节点 %s 的 7 条告警，实际为 %v", node.ID, sink.alerts)
+	}
+}
+
+func TestDispatchKeepsStateOfFailedAlerts(t *testing.T) {
+	s, sink := newAlertService(t)
+	node := models.Node{ID: "n1", Name: "node-1", IsEnabled: true, Version: "1.10.0"}
+	evaluate(t, s, node)
+
+	// 发送失败时不记录离线状态，下一次评估重新产生告警
+	node.IsEnabled = false
+	sink.setFail(true)
+	if _, err := evaluate(t, s, node); err == nil {
+		t.Fatal("发送失败时应返回错误")
+	}
+	sink.setFail(false)
+	alerts, err := evaluate(t, s, node)
+	if err != nil || !sameRules(alerts, AlertRuleOffline) {
+		t.Fatalf("发送失败的告警应在下一次评估时重新产生，实际为 %v (%v)", rules(alerts), err)
+	}
+	if alerts, _ := evaluate(t, s, node); len(alerts) != 0 {
+		t.Errorf("发送成功后不应重复告警，实际为 %v", rules(alerts))
+	}
+}
+
+func TestEvaluatePrunesMissingClusters(t *testing.T) {
+	s, _ := newAlertService(t)
+	kept := models.Node{ID: "n1", Name: "node-1", IsEnabled: true}
+	gone := models.Node{ID: "n2", Name: "node-2", IsEnabled: true}
+	evaluate(t, s, kept, gone)
+	evaluate(t, s, kept)
+
+	states, err := s.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := states[gone.ID]; ok || len(states) != 1 {
+		t.Errorf("不在节点列表中的节点应从状态中移除，实际为 %v", states)
+	}
+
+	// 重新出现的节点按首次见到处理
+	gone.IsEnabled = false
+	if alerts, _ := evaluate(t, s, kept, gone); len(alerts) != 0 {
+		t.Errorf("重新出现的节点不应产生告警，实际为 %v", rules(alerts))
+	}
+}
@@ -2,12 +2,11 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
@@ -15,12 +14,20 @@ import (
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
 )
 
-type AuthService struct{}
+type AuthService struct {
+	requestID string // 随上游请求发送的请求 ID
+}
 
 func NewAuth() *AuthService {
 	return &AuthService{}
 }
 
+// WithRequestID 设置随上游请求发送的请求 ID
+func (s *AuthService) WithRequestID(id string) *AuthService {
+	s.requestID = id
+	return s
+}
+
 // 定义请求状态
 type requestStatus int
 
@@ -34,20 +41,23 @@ const (
 func (s requestStatus) String() string {
 	switch s {
 	case preparing:
-		return "准备请求中"
+		return utils.T("status.preparing")
 	case requesting:
-		return "正在请求中"
+		return utils.T("status.requesting")
 	case overtime:
-		return "请求超过预期"
+		return utils.T("status.slow")
 	case timeout:
-		return "请求超时"
+		return utils.T("status.timeout")
 	default:
-		return "未知状态"
+		return utils.T("status.unknown")
 	}
 }
 
+// GithubAuthorizeURL 固定的 GitHub 授权地址，授权后回调到 bd.bangbang93.com
+const GithubAuthorizeURL = "https://github.com/login/oauth/authorize?response_type=code&redirect_uri=https%3A%2F%2Fbd.bangbang93.com%2Fcallback%2Flogin%2Fgithub&client_id=03132c1f1a1d46e078ea"
+
 func (s *AuthService) GetGithubAuthURL() (string, error) {
-	client := utils.NewHTTPClient()
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
 	respBody, err := client.DoGet("https://bd.bangbang93.com/openbmclapi/user/auth/github", nil)
 	if err != nil {
 		return "", err
@@ -55,43 +65,33 @@ func (s *AuthService) GetGithubAuthURL() (string, error) {
 
 	location := respBody.Header.Get("Location")
 	if location == "" {
-		return "", fmt.Errorf(utils.ColorText(utils.Red, "未找到重定向URL"))
+		return "", fmt.Errorf(utils.ColorText(utils.RoleError, utils.T("auth.no_redirect")))
 	}
 
 	// 检查并补全URL
 	if !strings.Contains(location, "client_id") {
-		return "", fmt.Errorf(utils.ColorText(utils.Red, "获取到的授权URL不完整"))
+		return "", fmt.Errorf(utils.ColorText(utils.RoleError, utils.T("auth.incomplete_url")))
 	}
 
 	fmt.Println()
-	fmt.Println(utils.ColorText(utils.Green, "✓ 获取授权地址成功"))
-	fmt.Printf(utils.ColorText(utils.Blue, "授权地址: %s\n"), location)
-	fmt.Println(utils.ColorText(utils.Yellow, "正在准备 GitHub 授权页面..."))
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("auth.got_url")))
+	fmt.Printf(utils.ColorText(utils.RoleInfo, utils.T("auth.url")), location)
+	fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("auth.preparing_page")))
 	return location, nil
 }
 
 func (s *AuthService) OpenBrowser(url string) error {
 	// 验证URL是否包含必要的参数
 	if !strings.Contains(url, "client_id") || !strings.Contains(url, "redirect_uri") {
-		return fmt.Errorf(utils.ColorText(utils.Red, "无效的授权URL"))
+		return fmt.Errorf(utils.ColorText(utils.RoleError, utils.T("auth.invalid_url")))
 	}
 
-	fmt.Println(utils.ColorText(utils.Green, "✓ 正在打开浏览器"))
-	fmt.Println(utils.ColorText(utils.Yellow, "请在浏览器中完成 GitHub 授权..."))
-
-	var err error
-	switch runtime.GOOS {
-	case "windows":
-		// 使用 rundll32 来打开 URL
-		err = exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
-	case "darwin":
-		err = exec.Command("open", url).Start()
-	default:
-		err = exec.Command("xdg-open", url).Start()
-	}
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("auth.opening_browser")))
+	fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("auth.authorize_in_browser")))
 
+	err := utils.OpenURL(url)
 	if err != nil {
-		fmt.Printf(utils.ColorText(utils.Yellow, "无法自动打开浏览器，请手动访问以下链接：\n%s\n"), url)
+		fmt.Printf(utils.ColorText(utils.RoleWarn, utils.T("common.open_browser_manually")), url)
 	}
 	return err
 }
@@ -117,7 +117,7 @@ func (s *AuthService) parseCode(callbackURL string) string {
 func (s *AuthService) VerifyCode(code string) error {
 	// 使用原来的 URL 路径
 	url := fmt.Sprintf("https://bd.bangbang93.com/openbmclapi/user/auth/github?code=%s", code)
-	client := utils.NewHTTPClient()
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
 	respBody, err := client.DoGet(url, nil)
 	if err != nil {
 		return err
@@ -126,7 +126,7 @@ func (s *AuthService) VerifyCode(code string) error {
 	// 获取所有 Set-Cookie 头
 	cookies := respBody.Header["Set-Cookie"]
 	if len(cookies) == 0 {
-		return fmt.Errorf("未找到 Cookie")
+		return errors.New(utils.T("auth.no_cookie"))
 	}
 
 	// 解析并存储 cookies
@@ -141,21 +141,21 @@ func (s *AuthService) VerifyCode(code string) error {
 	// 确保目录存在
 	dir := filepath.Dir("cookie.json")
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %v", err)
+		return fmt.Errorf(utils.T("common.mkdir_failed"), err)
 	}
 
 	// 将 cookies 写入文件
 	cookieData, err := json.MarshalIndent(cookieList, "", "  ")
 	if err != nil {
-		return fmt.Errorf("序列化 Cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("auth.marshal_cookie_failed"), err)
 	}
 
 	if err := ioutil.WriteFile("cookie.json", cookieData, 0644); err != nil {
-		return fmt.Errorf("保存 Cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("auth.save_cookie_failed"), err)
 	}
 
 	fmt.Println() // 清除进度显示的行
-	fmt.Println(utils.ColorText(utils.Green, "✓ Cookie 已保存"))
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("auth.cookie_saved")))
 	return nil
 }
 
@@ -198,15 +198,15 @@ func parseCookie(cookieStr string) *models.Cookie {
 func (s *AuthService) GetUserProfile() (*models.UserProfile, error) {
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return nil, fmt.Errorf("读取Cookie失败，请先登录: %v", err)
+		return nil, fmt.Errorf(utils.T("auth.read_cookie_login"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return nil, fmt.Errorf("解析Cookie失败: %v", err)
+		return nil, fmt.Errorf(utils.T("auth.parse_cookie_failed"), err)
 	}
 
-	client := utils.NewHTTPClient()
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
 	respBody, err := client.DoGet("https://bd.bangbang93.com/openbmclapi/user", cookies)
 	if err != nil {
 		return nil, err
@@ -214,7 +214,7 @@ func (s *AuthService) GetUserProfile() (*models.UserProfile, error) {
 
 	var profile models.UserProfile
 	if err := json.Unmarshal(respBody.Body, &profile); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_response_failed"), err)
 	}
 
 	return &profile, nil
@@ -224,19 +224,19 @@ func (s *AuthService) GetUserProfile() (*models.UserProfile, error) {
 func (s *AuthService) VerifyCallback(callbackURL string) error {
 	code := s.ExtractCode(callbackURL)
 	if code == "" {
-		return fmt.Errorf("无效的回调URL")
+		return errors.New(utils.T("auth.invalid_callback"))
 	}
 
 	// 显示请求状态
-	fmt.Print(utils.ColorText(utils.Yellow, "\r验证状态: 准备请求中"))
+	fmt.Print(utils.ColorText(utils.RoleWarn, utils.T("auth.verify_preparing")))
 	time.Sleep(500 * time.Millisecond) // 添加短暂延迟使状态变化可见
 
-	fmt.Print(utils.ColorText(utils.Yellow, "\r验证状态: 正在请求中"))
+	fmt.Print(utils.ColorText(utils.RoleWarn, utils.T("auth.verify_requesting")))
 
 	// 使用已有的 VerifyCode 方法进行验证
 	err := s.VerifyCode(code)
 	if err != nil {
-		fmt.Print(utils.ColorText(utils.Red, "\r验证状态: 请求失败    \n")) // 添加额外空格确保覆盖之前的状态
+		fmt.Print(utils.ColorText(utils.RoleError, utils.T("auth.verify_failed"))) // 添加额外空格确保覆盖之前的状态
 		return err
 	}
 
@@ -270,25 +270,25 @@ func (s *AuthService) SaveBrowserCookies(cookieStr string) error {
 	}
 
 	if len(cookieList) == 0 {
-		return fmt.Errorf("未找到有效的 Cookie")
+		return errors.New(utils.T("auth.no_valid_cookie"))
 	}
 
 	// 确保目录存在
 	dir := filepath.Dir("cookie.json")
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %v", err)
+		return fmt.Errorf(utils.T("common.mkdir_failed"), err)
 	}
 
 	// 将 cookies 写入文件
 	cookieData, err := json.MarshalIndent(cookieList, "", "  ")
 	if err != nil {
-		return fmt.Errorf("序列化 Cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("auth.marshal_cookie_failed"), err)
 	}
 
 	if err := ioutil.WriteFile("cookie.json", cookieData, 0644); err != nil {
-		return fmt.Errorf("保存 Cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("auth.save_cookie_failed"), err)
 	}
 
-	fmt.Println(utils.ColorText(utils.Green, "✓ Cookie 已保存"))
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("auth.cookie_saved")))
 	return nil
 }
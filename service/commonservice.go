@@ -30,13 +30,13 @@ func (s *CommonService) ClearScreen() {
 
 // WaitForEnter 等待用户按回车继续，然后清屏
 func (s *CommonService) WaitForEnter() {
-	fmt.Print(utils.ColorText(utils.Yellow, "\n按回车键继续..."))
+	fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("common.press_enter")))
 	bufio.NewReader(os.Stdin).ReadBytes('\n')
 	s.ClearScreen()
 }
 
 // WaitForEnterWithoutClear 等待用户按回车继续，但不清屏
 func (s *CommonService) WaitForEnterWithoutClear() {
-	fmt.Print(utils.ColorText(utils.Yellow, "\n按回车键继续..."))
+	fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("common.press_enter")))
 	bufio.NewReader(os.Stdin).ReadBytes('\n')
 }
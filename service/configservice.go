@@ -0,0 +1,53 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// ConfigFile 配置文件路径
+var ConfigFile = "config.json"
+
+type ConfigService struct{}
+
+func NewConfig() *ConfigService {
+	return &ConfigService{}
+}
+
+// Load 读取配置文件，文件不存在时返回默认配置
+func (s *ConfigService) Load() (*models.Config, error) {
+	cfg := models.DefaultConfig()
+
+	data, err := ioutil.ReadFile(ConfigFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf(utils.T("config.read_failed"), err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf(utils.T("config.parse_failed"), err)
+	}
+
+	return cfg, nil
+}
+
+// Save 保存配置文件
+func (s *ConfigService) Save(cfg *models.Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf(utils.T("config.marshal_failed"), err)
+	}
+
+	if err := ioutil.WriteFile(ConfigFile, data, 0600); err != nil {
+		return fmt.Errorf(utils.T("config.save_failed"), err)
+	}
+
+	return nil
+}
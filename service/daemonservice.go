@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 等待 Web 服务器和任务退出的最长时间
+const daemonShutdownTimeout = 30 * time.Second
+
+// DaemonService 在一个进程内运行 Web 服务器、状态采集、告警评估和报告任务
+type DaemonService struct {
+	config    *models.Config
+	scheduler *Scheduler
+	startedAt time.Time
+
+	// 采集任务的计划，用于估计轮询间隔以识别 daemon 停止运行的时段
+	collectSchedule *CronSchedule
+
+	// 最近一次采集到的节点列表，供告警任务复用，避免重复请求上游
+	mu      sync.Mutex
+	nodes   []models.Node
+	nodesAt time.Time
+}
+
+func NewDaemon(config *models.Config) *DaemonService {
+	return &DaemonService{
+		config:    config,
+		scheduler: NewScheduler(),
+	}
+}
+
+// PidFile 返回 PID 文件路径
+func (s *DaemonService) PidFile() string {
+	if s.config.Daemon.PidFile != "" {
+		return s.config.Daemon.PidFile
+	}
+	return filepath.Join(s.config.DataDir, "daemon.pid")
+}
+
+// Status 返回 daemon 与各任务的运行状态
+func (s *DaemonService) Status() models.DaemonStatus {
+	return models.DaemonStatus{
+		Pid:       os.Getpid(),
+		StartedAt: s.startedAt,
+		Jobs:      s.scheduler.Status(),
+	}
+}
+
+// Jobs 返回已注册任务的状态
+func (s *DaemonService) Jobs() []models.JobStatus {
+	return s.scheduler.Status()
+}
+
+// Setup 根据配置注册计划任务
+func (s *DaemonService) Setup() error {
+	cfg := s.config.Daemon
+	if cfg.Collect != "" {
+		if err := s.scheduler.Add("collect", cfg.Collect, s.collect); err != nil {
+			return err
+		}
+		s.collectSchedule, _ = ParseCron(cfg.Collect)
+	}
+	if cfg.Alerts != "" {
+		if err := s.scheduler.Add("alerts", cfg.Alerts, s.evaluateAlerts); err != nil {
+			return err
+		}
+	}
+	if cfg.RankSnapshot != "" {
+		if err := s.scheduler.Add("rank-snapshot", cfg.RankSnapshot, s.snapshotRank); err != nil {
+			return err
+		}
+	}
+	for _, job := range s.config.Reports {
+		if job.Schedule == "" {
+			continue
+		}
+		job := job
+		run := func(ctx context.Context) error {
+			_, err := NewReport(s.config).RunJob(job, time.Now())
+			return err
+		}
+		if err := s.scheduler.Add("report:"+job.Name, job.Schedule, run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run 运行 Web 服务器和计划任务，直到 ctx 被取消
+// 调用方应先通过 PidFile 获取实例锁
+func (s *DaemonService) Run(ctx context.Context) error {
+	s.startedAt = time.Now()
+
+	var webService *WebService
+	if s.config.Daemon.WebPort > 0 {
+		webService = NewWeb(s.config.Daemon.WebPort, s.config)
+		webService.SetDaemonMode(s.Status)
+		if err := webService.StartServer(); err != nil {
+			return fmt.Errorf(utils.T("common.web_start_failed"), err)
+		}
+	}
+
+	s.scheduler.Run(ctx)
+
+	if webService != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), daemonShutdownTimeout)
+		defer cancel()
+		if err := webService.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf(utils.T("common.web_stop_failed"), err)
+		}
+	}
+	return nil
+}
+
+// collect 采集节点状态并记录在线历史
+func (s *DaemonService) collect(ctx context.Context) error {
+	nodes, err := NewNode().GetNodeList()
+	if err != nil {
+		return fmt.Errorf(utils.T("common.fetch_nodes_failed"), err)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.nodes = nodes
+	s.nodesAt = now
+	s.mu.Unlock()
+
+	var interval time.Duration
+	if s.collectSchedule != nil {
+		interval = s.collectSchedule.Next(now).Sub(now)
+	}
+	return NewUptime(s.config.DataDir).Record(nodes, now, interval)
+}
+
+// evaluateAlerts 评估节点状态变化并发送告警
+func (s *DaemonService) evaluateAlerts(ctx context.Context) error {
+	s.mu.Lock()
+	nodes, at := s.nodes, s.nodesAt
+	s.mu.Unlock()
+
+	// 采集任务未启用或数据已过期时自行获取
+	if nodes == nil || time.Since(at) > 5*time.Minute {
+		var err error
+		if nodes, err = NewNode().GetNodeList(); err != nil {
+			return fmt.Errorf(utils.T("common.fetch_nodes_failed"), err)
+		}
+		at = time.Now()
+	}
+
+	alertService := NewAlert(s.config.DataDir, NewNotify(s.config.Notify))
+	alerts, err := alertService.Evaluate(nodes, at)
+	if err != nil {
+		return err
+	}
+	if len(alerts) > 0 {
+		utils.DebugLog(1, "[Daemon] 产生 %d 条告警", len(alerts))
+	}
+	// 没有告警时也要保存状态，记录新出现的节点并移除已消失的节点
+	return alertService.Dispatch(alerts)
+}
+
+// snapshotRank 保存当天的排行榜快照，替换当天较早的快照
+func (s *DaemonService) snapshotRank(ctx context.Context) error {
+	ranks, err := NewNode().GetNodeMetricRank(ctx)
+	if err != nil {
+		return fmt.Errorf(utils.T("common.fetch_rank_failed"), err)
+	}
+	saved, err := NewRankHistory(s.config.DataDir).Save(NewRankSnapshot(ranks, time.Now()))
+	if err == nil && !saved {
+		utils.DebugLog(1, "[Daemon] 今日已有更晚的排行榜快照，保留已有快照")
+	}
+	return err
+}
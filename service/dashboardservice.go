@@ -11,14 +11,22 @@ import (
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
 )
 
-type DashboardService struct{}
+type DashboardService struct {
+	requestID string // 随上游请求发送的请求 ID
+}
 
 func NewDashboard() *DashboardService {
 	return &DashboardService{}
 }
 
+// WithRequestID 设置随上游请求发送的请求 ID
+func (s *DashboardService) WithRequestID(id string) *DashboardService {
+	s.requestID = id
+	return s
+}
+
 func (s *DashboardService) GetDashboard() (*models.Dashboard, error) {
-	client := utils.NewHTTPClient()
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
 	respBody, err := client.DoGet("https://bd.bangbang93.com/openbmclapi/metric/dashboard", nil)
 	if err != nil {
 		return nil, err
@@ -26,50 +34,66 @@ func (s *DashboardService) GetDashboard() (*models.Dashboard, error) {
 
 	var dashboard models.Dashboard
 	if err := json.Unmarshal(respBody.Body, &dashboard); err != nil {
-		return nil, fmt.Errorf("解析数据失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_data_failed"), err)
 	}
+	// 命令行、全屏界面、watch、报告和 Web 面板都直接使用这里排好的顺序
+	dashboard.Hourly = chronologicalHourly(dashboard.Hourly)
 
 	return &dashboard, nil
 }
 
-// 修改带宽格式化函数
-func formatBandwidth(bandwidth float64) string {
-	// bandwidth 输入单位是 Mbps
-	if bandwidth < 1000 {
-		return fmt.Sprintf("%.2f Mbps", bandwidth)
+// chronologicalHourly 把每小时数据按时间先后排列，最后一项是最近的一小时。
+// _id 是小时 (0-23)，最近 24 小时的数据会跨过零点，不能直接按 _id 排序；
+// 这里根据相邻两项的 _id 是递增还是递减判断上游的顺序，递减时反转
+func chronologicalHourly(hourly []models.HourlyMetric) []models.HourlyMetric {
+	forward, backward := 0, 0
+	for i := 1; i < len(hourly); i++ {
+		switch hourly[i].ID {
+		case (hourly[i-1].ID + 1) % 24:
+			forward++
+		case (hourly[i-1].ID + 23) % 24:
+			backward++
+		}
+	}
+	if backward <= forward {
+		return hourly
 	}
-	return fmt.Sprintf("%.2f Gbps", bandwidth/1000)
+	sorted := make([]models.HourlyMetric, len(hourly))
+	for i, h := range hourly {
+		sorted[len(hourly)-1-i] = h
+	}
+	return sorted
 }
 
 func (s *DashboardService) DisplayDashboard(dashboard *models.Dashboard) {
-	fmt.Println(utils.ColorText(utils.Bold+utils.Green, "\n=== OpenBMCLAPI 系统状态面板 ==="))
+	fmt.Println(utils.ColorText(utils.RoleTitle, utils.T("dashboard.title")))
 
 	boxWidth := 24
 
 	// 第一行：关键指标
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📊 关键指标"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("common.key_metrics")))
 	fmt.Printf("┌%s┐ ┌%s┐ ┌%s┐\n",
 		strings.Repeat("─", boxWidth),
 		strings.Repeat("─", boxWidth),
 		strings.Repeat("─", boxWidth))
 
 	// 标题行
-	title1 := utils.PadString("当前在线节点数", boxWidth-2, true)
-	title2 := utils.PadString("当前出网带宽", boxWidth-2, true)
-	title3 := utils.PadString("系统负载", boxWidth-2, true)
+	title1 := utils.PadString(utils.T("dashboard.online_nodes"), boxWidth-2, true)
+	title2 := utils.PadString(utils.T("dashboard.bandwidth"), boxWidth-2, true)
+	title3 := utils.PadString(utils.T("common.load"), boxWidth-2, true)
 	fmt.Printf("│ %s │ │ %s │ │ %s │\n",
-		utils.ColorText(utils.Yellow, title1),
-		utils.ColorText(utils.Yellow, title2),
-		utils.ColorText(utils.Yellow, title3))
+		utils.ColorText(utils.RoleLabel, title1),
+		utils.ColorText(utils.RoleLabel, title2),
+		utils.ColorText(utils.RoleLabel, title3))
 
 	// 数值行
-	value1 := utils.PadString(fmt.Sprintf("%d 个", dashboard.CurrentNodes), boxWidth-2, false)
-	value2 := utils.PadString(formatBandwidth(dashboard.CurrentBandwidth), boxWidth-2, false)
+	value1 := utils.PadString(utils.TN("common.count_nodes", dashboard.CurrentNodes), boxWidth-2, false)
+	value2 := utils.PadString(utils.FormatBandwidth(dashboard.CurrentBandwidth), boxWidth-2, false)
 	value3 := utils.PadString(fmt.Sprintf("%.2f%%", dashboard.Load*100), boxWidth-2, false)
 	fmt.Printf("│ %s │ │ %s │ │ %s │\n",
-		utils.ColorText(utils.Cyan, value1),
-		utils.ColorText(utils.Cyan, value2),
-		utils.ColorText(utils.Cyan, value3))
+		utils.ColorText(utils.RoleValue, value1),
+		utils.ColorText(utils.RoleValue, value2),
+		utils.ColorText(utils.RoleValue, value3))
 
 	fmt.Printf("└%s┘ └%s┘ └%s┘\n",
 		strings.Repeat("─", boxWidth),
@@ -77,29 +101,29 @@ func (s *DashboardService) DisplayDashboard(dashboard *models.Dashboard) {
 		strings.Repeat("─", boxWidth))
 
 	// 第二行：累计数据
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📈 累计数据"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("dashboard.totals")))
 	fmt.Printf("┌%s┐ ┌%s┐ ┌%s┐\n",
 		strings.Repeat("─", boxWidth),
 		strings.Repeat("─", boxWidth),
 		strings.Repeat("─", boxWidth))
 
 	// 标题行
-	title1 = utils.PadString("当日总流量", boxWidth-2, true)
-	title2 = utils.PadString("当日请求次数", boxWidth-2, true)
-	title3 = utils.PadString("带宽上限", boxWidth-2, true)
+	title1 = utils.PadString(utils.T("dashboard.traffic_today"), boxWidth-2, true)
+	title2 = utils.PadString(utils.T("dashboard.hits_today"), boxWidth-2, true)
+	title3 = utils.PadString(utils.T("common.bandwidth_limit"), boxWidth-2, true)
 	fmt.Printf("│ %s │ │ %s │ │ %s │\n",
-		utils.ColorText(utils.Yellow, title1),
-		utils.ColorText(utils.Yellow, title2),
-		utils.ColorText(utils.Yellow, title3))
+		utils.ColorText(utils.RoleLabel, title1),
+		utils.ColorText(utils.RoleLabel, title2),
+		utils.ColorText(utils.RoleLabel, title3))
 
 	// 数值行
-	value1 = utils.PadString(models.FormatBytes(dashboard.Bytes), boxWidth-2, false)
-	value2 = utils.PadString(fmt.Sprintf("%d 次", dashboard.Hits), boxWidth-2, false)
-	value3 = utils.PadString(formatBandwidth(dashboard.Bandwidth), boxWidth-2, false)
+	value1 = utils.PadString(utils.FormatBytes(dashboard.Bytes), boxWidth-2, false)
+	value2 = utils.PadString(utils.TN("common.count_times", dashboard.Hits), boxWidth-2, false)
+	value3 = utils.PadString(utils.FormatBandwidth(dashboard.Bandwidth), boxWidth-2, false)
 	fmt.Printf("│ %s │ │ %s │ │ %s │\n",
-		utils.ColorText(utils.Cyan, value1),
-		utils.ColorText(utils.Cyan, value2),
-		utils.ColorText(utils.Cyan, value3))
+		utils.ColorText(utils.RoleValue, value1),
+		utils.ColorText(utils.RoleValue, value2),
+		utils.ColorText(utils.RoleValue, value3))
 
 	fmt.Printf("└%s┘ └%s┘ └%s┘\n",
 		strings.Repeat("─", boxWidth),
@@ -107,199 +131,122 @@ func (s *DashboardService) DisplayDashboard(dashboard *models.Dashboard) {
 		strings.Repeat("─", boxWidth))
 
 	// 第三行：历史数据
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📅 最近24小时数据趋势"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("dashboard.trend_24h")))
 	s.displayHourlyChart(dashboard.Hourly)
 
 	// 修改图表显示部分
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📊 数据趋势"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("dashboard.trends")))
 
 	// 节点数趋势图
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, "每小时在线节点数 (个)"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleLabel, utils.T("dashboard.chart_nodes")))
 	s.displayLineChart(dashboard.Hourly, func(h models.HourlyMetric) float64 {
 		return float64(h.Nodes)
-	}, 120)
+	})
 
 	// 带宽趋势图
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, "平均每小时出网带宽 (Gbps)"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleLabel, utils.T("dashboard.chart_bandwidth")))
 	s.displayLineChart(dashboard.Hourly, func(h models.HourlyMetric) float64 {
 		return h.Bandwidth / 1000 // 转换为 Gbps
-	}, 15)
+	})
 
 	// 流量趋势图
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, "每小时流量分布 (TB)"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleLabel, utils.T("dashboard.chart_traffic")))
 	s.displayLineChart(dashboard.Hourly, func(h models.HourlyMetric) float64 {
 		return float64(h.Bytes) / (1024 * 1024 * 1024 * 1024) // 转换为 TB
-	}, 6)
+	})
 
 	// 请求数趋势图
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, "每小时请求次数 (万次)"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleLabel, utils.T("dashboard.chart_hits")))
 	s.displayLineChart(dashboard.Hourly, func(h models.HourlyMetric) float64 {
 		return float64(h.Hits) / 10000
-	}, 180)
+	})
 
 	// 修改详细数据表格
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📋 最近6小时详细数据"))
-	fmt.Printf("┌──────┬──────────┬────────────┬──────────────┬────────────┐\n")
-	fmt.Printf("│ %-4s │ %-8s │ %-10s │ %-12s │ %-10s │\n",
-		utils.ColorText(utils.Yellow, "时段"),
-		utils.ColorText(utils.Yellow, "节点数"),
-		utils.ColorText(utils.Yellow, "流量"),
-		utils.ColorText(utils.Yellow, "请求次数"),
-		utils.ColorText(utils.Yellow, "平均带宽"))
-	fmt.Printf("├──────┼──────────┼────────────┼──────────────┼────────────┤\n")
-
-	recentHours := dashboard.Hourly[len(dashboard.Hourly)-6:]
-	// 反转顺序，从小时数小的开始显示
-	for i := 0; i < len(recentHours); i++ {
-		hour := recentHours[i]
-		fmt.Printf("│ %2d时  │ %4d台   │ %8s │ %10d次  │ %8s │\n",
-			hour.ID,
-			hour.Nodes,
-			models.FormatBytes(hour.Bytes),
-			hour.Hits,
-			formatBandwidth(hour.Bandwidth))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("dashboard.recent_6h")))
+	table := utils.NewTable(
+		utils.TableColumn{Title: utils.T("dashboard.col_hour")},
+		utils.TableColumn{Title: utils.T("dashboard.col_nodes"), Right: true},
+		utils.TableColumn{Title: utils.T("common.traffic"), Right: true},
+		utils.TableColumn{Title: utils.T("dashboard.col_hits"), Right: true},
+		utils.TableColumn{Title: utils.T("dashboard.col_bandwidth"), Right: true},
+	)
+	table.Border = true
+	table.HeaderColor = utils.RoleLabel
+
+	recentHours := dashboard.Hourly[max(len(dashboard.Hourly)-6, 0):]
+	for _, hour := range recentHours {
+		table.AddRow(
+			utils.T("common.hour", hour.ID),
+			utils.T("dashboard.cell_nodes", hour.Nodes),
+			utils.FormatBytes(hour.Bytes),
+			utils.T("dashboard.cell_hits", hour.Hits),
+			utils.FormatBandwidth(hour.Bandwidth))
 	}
-	fmt.Printf("└──────┴──────────┴────────────┴──────────────┴────────────┘\n")
+	table.Print()
 }
 
-// 修改图表显示函数
-func (s *DashboardService) displayLineChart(hourly []models.HourlyMetric, getValue func(models.HourlyMetric) float64, maxY float64) {
-	height := 10
-	width := 24 // 固定24小时
-
-	// 重新排序数据，从1点到24点
-	sortedHourly := make([]models.HourlyMetric, len(hourly))
-	copy(sortedHourly, hourly)
-	for i, j := 0, len(sortedHourly)-1; i < j; i, j = i+1, j-1 {
-		sortedHourly[i], sortedHourly[j] = sortedHourly[j], sortedHourly[i]
-	}
-
-	// 创建图表数据
-	chart := make([][]string, height)
-	for i := range chart {
-		chart[i] = make([]string, width)
-		for j := range chart[i] {
-			chart[i][j] = " "
-		}
+// hourLabels 每小时数据的时间标签
+func hourLabels(hourly []models.HourlyMetric) []string {
+	labels := make([]string, len(hourly))
+	for i, h := range hourly {
+		labels[i] = utils.T("common.hour", h.ID)
 	}
+	return labels
+}
 
-	// 填充图表数据
-	for x, metric := range sortedHourly {
-		value := getValue(metric)
-		y := int((value / maxY) * float64(height-1))
-		if y >= height {
-			y = height - 1
-		}
-		for i := 0; i <= y; i++ {
-			chart[height-1-i][x] = "█"
-		}
+// hourlyValues 取出每小时数据中的一项，顺序与 hourly 相同 (GetDashboard 已按时间先后排列)
+func hourlyValues(hourly []models.HourlyMetric, getValue func(models.HourlyMetric) float64) []float64 {
+	values := make([]float64, len(hourly))
+	for i, h := range hourly {
+		values[i] = getValue(h)
 	}
+	return values
+}
 
-	// 显示Y轴刻度和图表
-	for i := 0; i < height; i++ {
-		value := maxY * float64(height-i) / float64(height)
-		fmt.Printf("%6.0f┤", value)
-		for j := 0; j < width; j++ {
-			if j < len(sortedHourly) {
-				fmt.Print(utils.ColorText(utils.Blue, chart[i][j]))
-			} else {
-				fmt.Print(" ")
-			}
-		}
-		fmt.Println()
-	}
+// chartWidth 图表宽度跟随终端，过宽时限制在 120 列
+func chartWidth() int {
+	return min(utils.TerminalWidth(), 120)
+}
 
-	// 显示X轴
-	fmt.Print("      └")
-	for i := 0; i < width; i++ {
-		fmt.Print("─")
+// displayLineChart 显示每小时数据的折线图，纵轴根据数据范围自动缩放
+func (s *DashboardService) displayLineChart(hourly []models.HourlyMetric, getValue func(models.HourlyMetric) float64) {
+	chart := utils.Chart{
+		Series: []utils.ChartSeries{{Values: hourlyValues(hourly, getValue), Color: utils.RoleInfo}},
+		Labels: hourLabels(hourly),
+		Height: 10,
+		Width:  chartWidth(),
 	}
-	fmt.Println()
-
-	// 修改时间刻度显示
-	fmt.Print("        ")
-	for i := 0; i < width; i += 6 {
-		if i < len(sortedHourly) {
-			fmt.Printf("%2d时   ", sortedHourly[i].ID)
-		}
+	for _, line := range chart.Render() {
+		fmt.Println(line)
 	}
-	fmt.Println()
 }
 
-// 显示小时数据的趋势图
+// displayHourlyChart 用柱状图显示节点数和带宽的变化趋势
 func (s *DashboardService) displayHourlyChart(hourly []models.HourlyMetric) {
-	// 重新排序数据
-	sortedHourly := make([]models.HourlyMetric, len(hourly))
-	copy(sortedHourly, hourly)
-	for i, j := 0, len(sortedHourly)-1; i < j; i, j = i+1, j-1 {
-		sortedHourly[i], sortedHourly[j] = sortedHourly[j], sortedHourly[i]
+	charts := []struct {
+		title string
+		color utils.Role
+		value func(models.HourlyMetric) float64
+	}{
+		{utils.T("dashboard.nodes_trend"), utils.RoleInfo, func(h models.HourlyMetric) float64 { return float64(h.Nodes) }},
+		{utils.T("dashboard.bandwidth_trend"), utils.RoleValue, func(h models.HourlyMetric) float64 { return h.Bandwidth / 1000 }},
 	}
-
-	// 找出节点数的最大值，用于计算比例
-	maxNodes := 0
-	for _, h := range sortedHourly {
-		if h.Nodes > maxNodes {
-			maxNodes = h.Nodes
+	for i, c := range charts {
+		if i > 0 {
+			fmt.Println()
 		}
-	}
-
-	// 显示节点数趋势
-	fmt.Println(utils.ColorText(utils.Yellow, "节点数变化趋势:"))
-	for i := 0; i < 3; i++ {
-		fmt.Printf("%3d┤", (maxNodes*(3-i))/3)
-		for _, h := range sortedHourly {
-			height := (h.Nodes * 3) / maxNodes
-			if height >= (3 - i) {
-				fmt.Print(utils.ColorText(utils.Blue, "█"))
-			} else {
-				fmt.Print(" ")
-			}
-		}
-		fmt.Println()
-	}
-	fmt.Print("  0┤")
-	for range sortedHourly {
-		fmt.Print("─")
-	}
-	fmt.Println()
-	fmt.Print("   ")
-	for i := 0; i < len(sortedHourly); i += 6 {
-		fmt.Printf("%5d时", sortedHourly[i].ID)
-	}
-	fmt.Println()
-
-	// 修改带宽趋势显示
-	maxBandwidth := 0.0
-	for _, h := range sortedHourly {
-		if h.Bandwidth/1000 > maxBandwidth { // 转换为 Gbps
-			maxBandwidth = h.Bandwidth / 1000
+		fmt.Println(utils.ColorText(utils.RoleLabel, c.title))
+		chart := utils.Chart{
+			Series: []utils.ChartSeries{{Values: hourlyValues(hourly, c.value), Color: c.color}},
+			Labels: hourLabels(hourly),
+			Height: 4,
+			Width:  chartWidth(),
+			Mode:   utils.ChartBar,
 		}
-	}
-
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Yellow, "带宽使用趋势 (Gbps):"))
-	for i := 0; i < 3; i++ {
-		scale := float64(3-i) / 3.0
-		fmt.Printf("%7.1f┤", maxBandwidth*scale)
-		for _, h := range sortedHourly {
-			height := int((h.Bandwidth / 1000 * 3) / maxBandwidth)
-			if height >= (3 - i) {
-				fmt.Print(utils.ColorText(utils.Cyan, "█"))
-			} else {
-				fmt.Print(" ")
-			}
+		for _, line := range chart.Render() {
+			fmt.Println(line)
 		}
-		fmt.Println()
-	}
-	fmt.Print("   0┤")
-	for range sortedHourly {
-		fmt.Print("─")
 	}
-	fmt.Println()
-	fmt.Print("   ")
-	for i := 0; i < len(sortedHourly); i += 6 {
-		fmt.Printf("%5d时", sortedHourly[i].ID)
-	}
-	fmt.Println()
 }
 
 // 获取节点列表
@@ -307,19 +254,19 @@ func (s *DashboardService) GetNodeList() ([]models.Node, error) {
 	// 读取 cookie
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return nil, fmt.Errorf("读取 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return nil, fmt.Errorf("解析 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
 	// 创建请求
 	client := &http.Client{}
 	req, err := http.NewRequest("GET", "https://bd.bangbang93.com/openbmclapi/mgmt/cluster/my", nil)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.create_request_failed"), err)
 	}
 
 	// 添加 cookie
@@ -333,20 +280,20 @@ func (s *DashboardService) GetNodeList() ([]models.Node, error) {
 	// 发送请求
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.request_failed"), err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.read_response_failed"), err)
 	}
 
 	// 解析响应
 	var nodes []models.Node
 	if err := json.Unmarshal(body, &nodes); err != nil {
-		return nil, fmt.Errorf("解析数据失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_data_failed"), err)
 	}
 
 	return nodes, nil
@@ -354,64 +301,38 @@ func (s *DashboardService) GetNodeList() ([]models.Node, error) {
 
 // 显示节点列表
 func (s *DashboardService) DisplayNodeList(nodes []models.Node) {
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📡 节点列表"))
-	fmt.Printf("┌──────────────────────┬──────────┬──────────┬──────────┬─────────┬──────────────┐\n")
-	fmt.Printf("│ %-20s │ %-8s │ %-8s │ %-8s │ %-7s │ %-10s │\n",
-		utils.ColorText(utils.Yellow, "节点名称"),
-		utils.ColorText(utils.Yellow, "状态"),
-		utils.ColorText(utils.Yellow, "带宽"),
-		utils.ColorText(utils.Yellow, "实测带宽"),
-		utils.ColorText(utils.Yellow, "信任度"),
-		utils.ColorText(utils.Yellow, "最后活动"))
-	fmt.Printf("├──────────────────────┼──────────┼──────────┼──────────┼─────────┼──────────────┤\n")
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("common.node_list_title")))
+	table := utils.NewTable(
+		utils.TableColumn{Title: utils.T("common.node_name"), Wrap: true, MinWidth: 12},
+		utils.TableColumn{Title: utils.T("common.status")},
+		utils.TableColumn{Title: utils.T("common.bandwidth"), Right: true},
+		utils.TableColumn{Title: utils.T("common.measured_bandwidth"), Right: true},
+		utils.TableColumn{Title: utils.T("common.trust"), Right: true},
+		utils.TableColumn{Title: utils.T("common.last_active")},
+	)
+	table.Border = true
+	table.HeaderColor = utils.RoleLabel
 
 	for _, node := range nodes {
 		// 状态颜色
-		statusColor := utils.Green
-		status := "在线"
+		status := utils.ColorText(utils.RoleOK, utils.T("common.online"))
 		if !node.IsEnabled {
-			statusColor = utils.Red
-			status = "离线"
+			status = utils.ColorText(utils.RoleError, utils.T("common.offline"))
 		}
 
 		// 信任度颜色
-		trustColor := utils.Green
+		trustColor := utils.RoleOK
 		if node.Trust < 0 {
-			trustColor = utils.Red
+			trustColor = utils.RoleError
 		}
 
-		// 格式化节点名称
-		nodeName := utils.PadString(truncateString(node.Name, 20), 20, true)
-
-		// 格式化状态
-		statusStr := utils.PadString(status, 8, true)
-
-		// 格式化带宽和实测带宽
-		bandwidthStr := fmt.Sprintf("%8d", node.Bandwidth)
-		measureBandwidthStr := fmt.Sprintf("%8d", node.MeasureBandwidth)
-
-		// 格式化信任度
-		trustStr := fmt.Sprintf("%7d", node.Trust)
-
-		// 格式化时间
-		timeStr := utils.PadString(node.LastActivity.Format("01-02 15:04"), 10, true)
-
-		fmt.Printf("│ %s │ %s │ %s │ %s │ %s │ %s │\n",
-			utils.ColorText(utils.Cyan, nodeName),
-			utils.ColorText(statusColor, statusStr),
-			bandwidthStr,
-			measureBandwidthStr,
-			utils.ColorText(trustColor, trustStr),
-			timeStr)
+		table.AddRow(
+			utils.ColorText(utils.RoleValue, node.Name),
+			status,
+			fmt.Sprintf("%d", node.Bandwidth),
+			fmt.Sprintf("%d", node.MeasureBandwidth),
+			utils.ColorText(trustColor, fmt.Sprintf("%d", node.Trust)),
+			node.LastActivity.Format("01-02 15:04"))
 	}
-	fmt.Printf("└──────────────────────┴──────────┴──────────┴──────────┴─────────┴──────────────┘\n")
-}
-
-// 辅助函数：截断字符串，考虑中文字符
-func truncateString(s string, length int) string {
-	r := []rune(s)
-	if len(r) > length {
-		return string(r[:length-3]) + "..."
-	}
-	return s
+	table.Print()
 }
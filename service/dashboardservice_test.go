@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func hourIDs(hourly []models.HourlyMetric) []int {
+	ids := make([]int, len(hourly))
+	for i, h := range hourly {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func hoursOf(ids ...int) []models.HourlyMetric {
+	hourly := make([]models.HourlyMetric, len(ids))
+	for i, id := range ids {
+		hourly[i] = models.HourlyMetric{ID: id}
+	}
+	return hourly
+}
+
+func TestChronologicalHourly(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{"ascending", []int{20, 21, 22, 23, 0, 1}, []int{20, 21, 22, 23, 0, 1}},
+		{"descending", []int{1, 0, 23, 22, 21, 20}, []int{20, 21, 22, 23, 0, 1}},
+		{"descending full day", []int{5, 4, 3, 2, 1, 0, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6}, []int{6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 0, 1, 2, 3, 4, 5}},
+		{"single", []int{7}, []int{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hourIDs(chronologicalHourly(hoursOf(tt.input...)))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v，应为 %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v，应为 %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 就绪检查结果的缓存时间，避免探针频繁请求上游
+const healthCacheTTL = 10 * time.Second
+
+// 后台刷新就绪检查结果的间隔，公开的就绪探针只读取刷新后的结果
+const healthRefreshInterval = 30 * time.Second
+
+// healthCheck 是一项就绪检查
+type healthCheck struct {
+	name string
+	run  func() error
+}
+
+// HealthService 检查上游服务是否可用以及登录凭据是否有效
+type HealthService struct {
+	// mu 只保护 last 和 expiry，请求上游期间不持有，Last 不会被检查阻塞
+	mu     sync.Mutex
+	last   *models.HealthStatus
+	expiry time.Time
+	// checking 保证同一时间只有一轮检查在请求上游
+	checking sync.Mutex
+	checks   []healthCheck
+}
+
+func NewHealth() *HealthService {
+	return &HealthService{checks: []healthCheck{
+		{"upstream", checkUpstream},
+		{"credentials", checkCredentials},
+	}}
+}
+
+// cached 返回未过期的缓存结果
+func (s *HealthService) cached() (models.HealthStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last != nil && time.Now().Before(s.expiry) {
+		return *s.last, true
+	}
+	return models.HealthStatus{}, false
+}
+
+// Check 返回就绪检查结果，10 秒内重复调用时使用缓存
+func (s *HealthService) Check() models.HealthStatus {
+	if status, ok := s.cached(); ok {
+		return status
+	}
+	s.checking.Lock()
+	defer s.checking.Unlock()
+	// 等待期间其他调用可能已经完成了检查
+	if status, ok := s.cached(); ok {
+		return status
+	}
+
+	status := models.HealthStatus{Ready: true, CheckedAt: time.Now()}
+	for _, check := range s.checks {
+		start := time.Now()
+		err := check.run()
+		result := models.HealthCheck{
+			Name:    check.name,
+			OK:      err == nil,
+			Latency: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			status.Ready = false
+		}
+		status.Checks = append(status.Checks, result)
+	}
+
+	s.mu.Lock()
+	s.last = &status
+	s.expiry = time.Now().Add(healthCacheTTL)
+	s.mu.Unlock()
+	return status
+}
+
+// Last 返回最近一次检查的结果，不请求上游；尚未检查过时返回 nil
+func (s *HealthService) Last() *models.HealthStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last == nil {
+		return nil
+	}
+	status := *s.last
+	return &status
+}
+
+// run 启动后立即检查一次，之后定期刷新结果，直到 ctx 取消
+func (s *HealthService) run(ctx context.Context) {
+	ticker := time.NewTicker(healthRefreshInterval)
+	defer ticker.Stop()
+	for {
+		s.Check()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkUpstream 请求不需要登录的仪表盘接口
+func checkUpstream() error {
+	_, err := NewDashboard().GetDashboard()
+	return err
+}
+
+// checkCredentials 使用 cookie.json 获取用户信息，凭据过期时上游不会返回用户 ID
+func checkCredentials() error {
+	profile, err := NewAuth().GetUserProfile()
+	if err != nil {
+		return err
+	}
+	if profile.ID == "" {
+		return errors.New(utils.T("common.credentials_invalid"))
+	}
+	return nil
+}
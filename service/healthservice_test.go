@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestPublicReadyHidesCheckDetails(t *testing.T) {
+	s := NewWeb(0, models.DefaultConfig())
+
+	// 尚未完成检查时返回 503，且不触发上游请求
+	rec := httptest.NewRecorder()
+	s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("尚未检查时应返回 503，实际为 %d", rec.Code)
+	}
+	if s.health.Last() != nil {
+		t.Fatal("公开的就绪探针不应触发检查")
+	}
+
+	s.health.last = &models.HealthStatus{
+		Ready:     false,
+		CheckedAt: time.Now(),
+		Checks:    []models.HealthCheck{{Name: "credentials", Error: "upstream said: invalid cookie"}},
+	}
+	s.health.expiry = time.Now().Add(time.Hour)
+
+	rec = httptest.NewRecorder()
+	s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+	var resp struct {
+		Data models.HealthStatus `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || resp.Data.Ready {
+		t.Fatalf("未就绪时应返回 503，实际为 %d", rec.Code)
+	}
+	if len(resp.Data.Checks) != 0 {
+		t.Fatalf("公开的就绪探针不应返回检查详情: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleReadyDetails(rec, httptest.NewRequest(http.MethodGet, "/api/ready/details", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Checks) != 1 {
+		t.Fatalf("详情接口应返回各项检查: %s", rec.Body.String())
+	}
+}
+
+func TestReadyDoesNotWaitForCheckInFlight(t *testing.T) {
+	s := NewWeb(0, models.DefaultConfig())
+	s.health.last = &models.HealthStatus{Ready: true, CheckedAt: time.Now()}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.health.checks = []healthCheck{{"upstream", func() error {
+		close(started)
+		<-release
+		return nil
+	}}}
+	done := make(chan struct{})
+	go func() {
+		s.health.Check()
+		close(done)
+	}()
+	<-started
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	served := make(chan int, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+		served <- rec.Code
+	}()
+	select {
+	case code := <-served:
+		if code != http.StatusOK {
+			t.Fatalf("应返回上一次的检查结果 200，实际为 %d", code)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("检查进行中时就绪探针被阻塞")
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// NodeCacheFile 节点列表缓存的文件名，保存在配置的数据目录中
+const NodeCacheFile = "nodes-cache.json"
+
+// nodeCachePath 返回节点列表缓存路径，配置无法读取时使用默认数据目录
+func nodeCachePath() string {
+	dataDir := models.DefaultConfig().DataDir
+	if cfg, err := NewConfig().Load(); err == nil {
+		dataDir = cfg.DataDir
+	}
+	return filepath.Join(dataDir, NodeCacheFile)
+}
+
+// SaveNodeCache 缓存节点列表，只由列出节点的界面调用 (节点列表、全屏界面、watch)，
+// 失败只记录调试日志，不影响调用方
+func SaveNodeCache(nodes []models.Node) {
+	data, err := json.Marshal(models.NodeCache{
+		UpdatedAt: time.Now(),
+		Nodes:     nodes,
+	})
+	if err != nil {
+		utils.DebugLog(1, "[节点] 序列化节点缓存失败: %v", err)
+		return
+	}
+	path := nodeCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		utils.DebugLog(1, "[节点] 创建数据目录失败: %v", err)
+		return
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		utils.DebugLog(1, "[节点] 保存节点缓存失败: %v", err)
+	}
+}
+
+// LoadNodeCache 读取缓存的节点列表，从未成功获取过时返回 nil
+func LoadNodeCache() (*models.NodeCache, error) {
+	data, err := ioutil.ReadFile(nodeCachePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(utils.T("node.read_cache_failed"), err)
+	}
+
+	var cache models.NodeCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf(utils.T("node.parse_cache_failed"), err)
+	}
+	return &cache, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestNodeCacheInDataDir(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "state")
+	oldConfig := ConfigFile
+	ConfigFile = filepath.Join(dir, "config.json")
+	defer func() { ConfigFile = oldConfig }()
+	if err := ioutil.WriteFile(ConfigFile, []byte(`{"dataDir": "`+filepath.ToSlash(dataDir)+`"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache, err := LoadNodeCache(); err != nil || cache != nil {
+		t.Fatalf("没有缓存时应返回 nil，实际为 %v, %v", cache, err)
+	}
+
+	SaveNodeCache([]models.Node{{ID: "abc", Name: "node-1"}})
+	if _, err := os.Stat(filepath.Join(dataDir, NodeCacheFile)); err != nil {
+		t.Fatalf("缓存应写入数据目录: %v", err)
+	}
+	cache, err := LoadNodeCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache == nil || len(cache.Nodes) != 1 || cache.Nodes[0].ID != "abc" {
+		t.Fatalf("读取的缓存不正确: %+v", cache)
+	}
+}
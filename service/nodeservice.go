@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -16,33 +18,68 @@ import (
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
 )
 
-type NodeService struct{}
+// ErrNodeNotFound 表示节点不存在或不属于当前账号
+var ErrNodeNotFound = utils.NewError("node.not_found")
+
+// ErrInvalidNodeID 表示节点 ID 含有上游 ID 之外的字符
+var ErrInvalidNodeID = utils.NewError("node.invalid_id")
+
+// ErrNotLoggedIn 表示没有保存登录信息，或上游拒绝了保存的登录信息
+var ErrNotLoggedIn = utils.NewError("node.not_logged_in")
+
+// nodeIDPattern 上游节点 ID 的字符集。ID 来自 URL 路径参数，其中解码后的 / 或 ? 会改变请求的上游接口，
+// 因此拼接地址前必须校验
+var nodeIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// clusterURL 返回节点在上游管理接口中的地址，读取和修改节点的请求都通过它拼接，suffix 为空或以 / 开头；ID 不合法时返回 ErrInvalidNodeID
+func clusterURL(nodeID, suffix string) (string, error) {
+	if !nodeIDPattern.MatchString(nodeID) {
+		return "", ErrInvalidNodeID
+	}
+	return "https://bd.bangbang93.com/openbmclapi/mgmt/cluster/" + url.PathEscape(nodeID) + suffix, nil
+}
+
+type NodeService struct {
+	requestID string // 随上游请求发送的请求 ID
+}
 
 func NewNode() *NodeService {
 	return &NodeService{}
 }
 
+// WithRequestID 设置随上游请求发送的请求 ID
+func (s *NodeService) WithRequestID(id string) *NodeService {
+	s.requestID = id
+	return s
+}
+
 // GetNodeList 获取节点列表
 func (s *NodeService) GetNodeList() ([]models.Node, error) {
 	cookieData, err := ioutil.ReadFile("cookie.json")
+	if os.IsNotExist(err) {
+		return nil, ErrNotLoggedIn
+	}
 	if err != nil {
-		return nil, fmt.Errorf("读取 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return nil, fmt.Errorf("解析 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
-	client := utils.NewHTTPClient()
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
 	respBody, err := client.DoGet("https://bd.bangbang93.com/openbmclapi/mgmt/cluster/my", cookies)
 	if err != nil {
 		return nil, err
 	}
+	if respBody.StatusCode == http.StatusUnauthorized || respBody.StatusCode == http.StatusForbidden {
+		return nil, ErrNotLoggedIn
+	}
 
 	var nodes []models.Node
 	if err := json.Unmarshal(respBody.Body, &nodes); err != nil {
-		return nil, fmt.Errorf("解析数据失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_data_failed"), err)
 	}
 
 	return nodes, nil
@@ -50,58 +87,116 @@ func (s *NodeService) GetNodeList() ([]models.Node, error) {
 
 // GetNodeDetail 获取节点详情
 func (s *NodeService) GetNodeDetail(nodeID string) (*models.Node, error) {
+	endpoint, err := clusterURL(nodeID, "")
+	if err != nil {
+		return nil, err
+	}
+
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return nil, fmt.Errorf("读取 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return nil, fmt.Errorf("解析 cookie 失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
-	url := fmt.Sprintf("https://bd.bangbang93.com/openbmclapi/mgmt/cluster/%s", nodeID)
-	client := utils.NewHTTPClient()
-	respBody, err := client.DoGet(url, cookies)
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
+	respBody, err := client.DoGet(endpoint, cookies)
 	if err != nil {
 		return nil, err
 	}
 
+	if respBody.StatusCode == http.StatusNotFound {
+		return nil, ErrNodeNotFound
+	}
+	if respBody.StatusCode >= 400 {
+		return nil, fmt.Errorf(utils.T("node.detail_http_failed"), respBody.StatusCode)
+	}
+
 	var node models.Node
 	if err := json.Unmarshal(respBody.Body, &node); err != nil {
-		return nil, fmt.Errorf("解析数据失败: %v", err)
+		return nil, fmt.Errorf(utils.T("common.parse_data_failed"), err)
+	}
+	if node.ID == "" {
+		return nil, ErrNodeNotFound
 	}
 
 	return &node, nil
 }
 
-// DisplayAndSelectNode 显示节点列表并处理选择
+// BuildNodeDetail 根据节点数据和今日排行榜计算派生指标，ranks 可以为空
+func (s *NodeService) BuildNodeDetail(node *models.Node, ranks []NodeMetricRank, now time.Time) *models.NodeDetail {
+	detail := &models.NodeDetail{Node: *node, SinceLastActivitySeconds: -1}
+	if node.IsEnabled && !node.Uptime.IsZero() {
+		detail.OnlineSeconds = int64(now.Sub(node.Uptime).Seconds())
+	}
+	if !node.LastActivity.IsZero() {
+		detail.SinceLastActivitySeconds = int64(now.Sub(node.LastActivity).Seconds())
+	}
+
+	// 排名与排行榜列表和快照一致，使用上游返回的顺序
+	for i, rank := range ranks {
+		if rank.ID != node.ID {
+			continue
+		}
+		detail.TodayRank = &models.NodeTodayRank{
+			Rank:  i + 1,
+			Total: len(ranks),
+			Bytes: rank.Metric.Bytes,
+			Hits:  rank.Metric.Hits,
+		}
+
+		// 今日平均带宽 (Mbps) 与声明带宽之比
+		year, month, day := now.Date()
+		elapsed := now.Sub(time.Date(year, month, day, 0, 0, 0, 0, now.Location())).Seconds()
+		if node.Bandwidth > 0 && elapsed > 0 {
+			ratio := float64(rank.Metric.Bytes) * 8 / elapsed / 1e6 / float64(node.Bandwidth)
+			detail.BandwidthUtilization = &ratio
+		}
+		break
+	}
+
+	return detail
+}
+
+// DisplayAndSelectNode 显示节点列表并处理选择，输入编号打开详情，输入其他内容按名称、ID、赞助商或用户搜索
 func (s *NodeService) DisplayAndSelectNode(nodes []models.Node) {
 	reader := bufio.NewReader(os.Stdin)
 	commonService := NewCommon()
+	query := ""
 
 	for {
-		commonService.ClearScreen() // 每次显示列表前清屏
-		fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📡 节点列表"))
-		fmt.Println(strings.Repeat("─", 50))
+		results := FilterNodes(query, nodes)
 
-		for i, node := range nodes {
-			statusColor := utils.Green
-			status := "在线"
+		commonService.ClearScreen() // 每次显示列表前清屏
+		fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("common.node_list_title")))
+		table := utils.NewTable(
+			utils.TableColumn{Title: utils.T("node.col_number"), Right: true},
+			utils.TableColumn{Title: utils.T("common.node_name"), Wrap: true, MinWidth: 12},
+			utils.TableColumn{Title: utils.T("common.status")},
+			utils.TableColumn{Title: "ID"},
+		)
+		for i, r := range results {
+			node := nodes[r.Index]
+			status := utils.ColorText(utils.RoleOK, utils.T("common.online"))
 			if !node.IsEnabled {
-				statusColor = utils.Red
-				status = "离线"
+				status = utils.ColorText(utils.RoleError, utils.T("common.offline"))
 			}
-
-			fmt.Printf("%d. %s [%s] (ID: %s)\n",
-				i+1,
-				utils.ColorText(utils.Cyan, node.Name),
-				utils.ColorText(statusColor, status),
-				node.ID)
+			name := utils.ColorText(utils.RoleValue, node.Name)
+			if query != "" {
+				name = r.Match.Decorate(node.Name)
+			}
+			table.AddRow(fmt.Sprintf("%d", i+1), name, status, node.ID)
 		}
+		table.Print()
 
 		fmt.Println(strings.Repeat("─", 50))
-		fmt.Print(utils.ColorText(utils.Yellow, "请选择节点编号 (输入 q 返回): "))
+		if query != "" {
+			fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("node.filtered", query, len(results), len(nodes))))
+		}
+		fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("node.select_prompt")))
 
 		input, _ := reader.ReadString('\n')
 		input = strings.TrimSpace(input)
@@ -109,19 +204,31 @@ func (s *NodeService) DisplayAndSelectNode(nodes []models.Node) {
 		if input == "q" {
 			return
 		}
+		if input == "" {
+			query = ""
+			continue
+		}
 
-		var index int
-		if _, err := fmt.Sscanf(input, "%d", &index); err != nil || index < 1 || index > len(nodes) {
-			fmt.Println(utils.ColorText(utils.Red, "无效的选择，请重试"))
+		index, err := strconv.Atoi(input)
+		if err != nil {
+			// 不是编号时作为搜索关键字，只有一个结果时直接打开
+			query = input
+			results = FilterNodes(query, nodes)
+			if len(results) != 1 {
+				continue
+			}
+			index = 1
+		} else if index < 1 || index > len(results) {
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("common.invalid_choice_retry")))
 			commonService.WaitForEnter() // 使用通用的等待函数
 			continue
 		}
 
-		selectedNode := nodes[index-1]
-		fmt.Printf("选择的节点 ID: %s\n", selectedNode.ID)
+		selectedNode := nodes[results[index-1].Index]
+		fmt.Printf(utils.T("node.selected_id"), selectedNode.ID)
 		nodeDetail, err := s.GetNodeDetail(selectedNode.ID)
 		if err != nil {
-			fmt.Printf(utils.ColorText(utils.Red, "获取节点详情失败: %v\n"), err)
+			fmt.Printf(utils.ColorText(utils.RoleError, utils.T("node.detail_failed")), err)
 			commonService.WaitForEnter() // 使用通用的等待函数
 			continue
 		}
@@ -138,19 +245,23 @@ type NodeUpdateInfo struct {
 
 // UpdateNode 更新节点信息
 func (s *NodeService) UpdateNode(nodeID string, info NodeUpdateInfo) error {
+	endpoint, err := clusterURL(nodeID, "")
+	if err != nil {
+		return err
+	}
+
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return fmt.Errorf("读取 cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return fmt.Errorf("解析 cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
-	url := fmt.Sprintf("https://bd.bangbang93.com/openbmclapi/mgmt/cluster/%s", nodeID)
-	client := utils.NewHTTPClient()
-	_, err = client.DoPatch(url, info, cookies)
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
+	_, err = client.DoPatch(endpoint, info, cookies)
 	return err
 }
 
@@ -161,41 +272,49 @@ type NodeSponsorUpdate struct {
 
 // UpdateNodeSponsor 更新节点赞助商信息
 func (s *NodeService) UpdateNodeSponsor(nodeID string, sponsor models.NodeSponsor) error {
+	endpoint, err := clusterURL(nodeID, "")
+	if err != nil {
+		return err
+	}
+
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return fmt.Errorf("读取 cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return fmt.Errorf("解析 cookie 失败: %v", err)
+		return fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
 	updateInfo := NodeSponsorUpdate{
 		Sponsor: sponsor,
 	}
 
-	url := fmt.Sprintf("https://bd.bangbang93.com/openbmclapi/mgmt/cluster/%s", nodeID)
-	client := utils.NewHTTPClient()
-	_, err = client.DoPatch(url, updateInfo, cookies)
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
+	_, err = client.DoPatch(endpoint, updateInfo, cookies)
 	return err
 }
 
 // ResetNodeSecret 重置节点密钥
 func (s *NodeService) ResetNodeSecret(nodeId string) (string, error) {
+	endpoint, err := clusterURL(nodeId, "/reset-secret")
+	if err != nil {
+		return "", err
+	}
+
 	cookieData, err := ioutil.ReadFile("cookie.json")
 	if err != nil {
-		return "", fmt.Errorf("读取 cookie 失败: %v", err)
+		return "", fmt.Errorf(utils.T("common.read_cookie_failed"), err)
 	}
 
 	var cookies []models.Cookie
 	if err := json.Unmarshal(cookieData, &cookies); err != nil {
-		return "", fmt.Errorf("解析 cookie 失败: %v", err)
+		return "", fmt.Errorf(utils.T("common.parse_cookie_failed"), err)
 	}
 
-	client := utils.NewHTTPClient()
-	url := fmt.Sprintf("https://bd.bangbang93.com/openbmclapi/mgmt/cluster/%s/reset-secret", nodeId)
-	respBody, err := client.DoPatch(url, nil, cookies)
+	client := utils.NewHTTPClient().WithRequestID(s.requestID)
+	respBody, err := client.DoPatch(endpoint, nil, cookies)
 	if err != nil {
 		return "", err
 	}
@@ -205,7 +324,7 @@ func (s *NodeService) ResetNodeSecret(nodeId string) (string, error) {
 	}
 
 	if err := json.Unmarshal(respBody.Body, &result); err != nil {
-		return "", fmt.Errorf("解析响应失败: %w", err)
+		return "", fmt.Errorf(utils.T("node.parse_response_failed"), err)
 	}
 
 	return result.Secret, nil
@@ -220,13 +339,13 @@ func (s *NodeService) DisplayNodeDetail(node *models.Node) {
 		s.showNodeDetail(node)
 
 		fmt.Println(strings.Repeat("─", 50))
-		fmt.Println(utils.ColorText(utils.Yellow, "操作选项:"))
-		fmt.Println("1. 修改节点信息")
-		fmt.Println("2. 修改赞助商信息")
-		fmt.Println("3. 重置节点密钥")
-		fmt.Println("4. 刷新节点信息")
-		fmt.Println("q. 返回上级菜单")
-		fmt.Print(utils.ColorText(utils.Purple, "请选择操作: "))
+		fmt.Println(utils.ColorText(utils.RoleLabel, utils.T("node.actions")))
+		fmt.Println(utils.T("node.action_edit"))
+		fmt.Println(utils.T("node.action_sponsor"))
+		fmt.Println(utils.T("node.action_reset"))
+		fmt.Println(utils.T("node.action_refresh"))
+		fmt.Println(utils.T("node.action_back"))
+		fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("node.action_prompt")))
 
 		reader := bufio.NewReader(os.Stdin)
 		input, _ := reader.ReadString('\n')
@@ -235,10 +354,10 @@ func (s *NodeService) DisplayNodeDetail(node *models.Node) {
 		switch input {
 		case "1":
 			if err := s.editNodeInfo(node); err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "修改失败: %v\n"), err)
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("node.update_failed")), err)
 				commonService.WaitForEnter()
 			} else {
-				fmt.Println(utils.ColorText(utils.Green, "修改成功!"))
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("node.update_ok")))
 				// 刷新节点信息
 				updatedNode, err := s.GetNodeDetail(node.ID)
 				if err == nil {
@@ -248,10 +367,10 @@ func (s *NodeService) DisplayNodeDetail(node *models.Node) {
 			}
 		case "2":
 			if err := s.editSponsorInfo(node); err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "修改失败: %v\n"), err)
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("node.update_failed")), err)
 				commonService.WaitForEnter()
 			} else {
-				fmt.Println(utils.ColorText(utils.Green, "修改成功!"))
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("node.update_ok")))
 				// 刷新节点信息
 				updatedNode, err := s.GetNodeDetail(node.ID)
 				if err == nil {
@@ -261,35 +380,35 @@ func (s *NodeService) DisplayNodeDetail(node *models.Node) {
 			}
 		case "3":
 			commonService.ClearScreen() // 重置密钥前清屏
-			fmt.Print(utils.ColorText(utils.Red, "\n⚠️ 警告: 重置密钥将导致节点需要重新配置!\n"))
-			fmt.Print(utils.ColorText(utils.Yellow, "确认重置? (输入 'RESET' 确认): "))
+			fmt.Print(utils.ColorText(utils.RoleError, utils.T("node.reset_warning")))
+			fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("node.reset_confirm")))
 			confirm, _ := reader.ReadString('\n')
 			confirm = strings.TrimSpace(confirm)
 
 			if confirm == "RESET" {
 				if secret, err := s.ResetNodeSecret(node.ID); err != nil {
-					fmt.Printf(utils.ColorText(utils.Red, "重置失败: %v\n"), err)
+					fmt.Printf(utils.ColorText(utils.RoleError, utils.T("node.reset_failed")), err)
 				} else {
-					fmt.Printf(utils.ColorText(utils.Green, "重置成功!\n"))
-					fmt.Printf(utils.ColorText(utils.Yellow, "新密钥: %s\n"), secret)
+					fmt.Printf(utils.ColorText(utils.RoleOK, utils.T("node.reset_ok")))
+					fmt.Printf(utils.ColorText(utils.RoleWarn, utils.T("node.new_secret")), secret)
 				}
 			} else {
-				fmt.Println(utils.ColorText(utils.Yellow, "已取消重置"))
+				fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("node.reset_cancelled")))
 			}
 			commonService.WaitForEnter()
 		case "4":
 			updatedNode, err := s.GetNodeDetail(node.ID)
 			if err != nil {
-				fmt.Printf(utils.ColorText(utils.Red, "刷新失败: %v\n"), err)
+				fmt.Printf(utils.ColorText(utils.RoleError, utils.T("node.refresh_failed")), err)
 			} else {
 				node = updatedNode
-				fmt.Println(utils.ColorText(utils.Green, "刷新成功!"))
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("node.refresh_ok")))
 			}
 			commonService.WaitForEnter()
 		case "q":
 			return
 		default:
-			fmt.Println(utils.ColorText(utils.Red, "无效的选择"))
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("node.invalid_choice")))
 			commonService.WaitForEnter()
 		}
 	}
@@ -299,18 +418,18 @@ func (s *NodeService) DisplayNodeDetail(node *models.Node) {
 func (s *NodeService) editNodeInfo(node *models.Node) error {
 	reader := bufio.NewReader(os.Stdin)
 
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📝 编辑节点信息"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("node.edit_title")))
 	fmt.Println(strings.Repeat("─", 50))
 
 	// 显示当前值并获取新值
-	fmt.Printf("节点名称 (当前: %s): ", node.Name)
+	fmt.Printf(utils.T("node.edit_name"), node.Name)
 	newName, _ := reader.ReadString('\n')
 	newName = strings.TrimSpace(newName)
 	if newName == "" {
 		newName = node.Name
 	}
 
-	fmt.Printf("带宽限制 (当前: %d Mbps): ", node.Bandwidth)
+	fmt.Printf(utils.T("node.edit_bandwidth"), node.Bandwidth)
 	bandwidthStr, _ := reader.ReadString('\n')
 	bandwidthStr = strings.TrimSpace(bandwidthStr)
 	newBandwidth := node.Bandwidth
@@ -321,7 +440,7 @@ func (s *NodeService) editNodeInfo(node *models.Node) error {
 	}
 
 	// 确认修改
-	fmt.Print(utils.ColorText(utils.Yellow, "\n确认修改? (y/N): "))
+	fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("node.confirm_update")))
 	confirm, _ := reader.ReadString('\n')
 	confirm = strings.ToLower(strings.TrimSpace(confirm))
 
@@ -340,25 +459,25 @@ func (s *NodeService) editNodeInfo(node *models.Node) error {
 func (s *NodeService) editSponsorInfo(node *models.Node) error {
 	reader := bufio.NewReader(os.Stdin)
 
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📝 编辑赞助商信息"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("node.sponsor_title")))
 	fmt.Println(strings.Repeat("─", 50))
 
 	// 显示当前值并获取新值
-	fmt.Printf("赞助商名称 (当前: %s): ", node.Sponsor.Name)
+	fmt.Printf(utils.T("node.sponsor_name"), node.Sponsor.Name)
 	newName, _ := reader.ReadString('\n')
 	newName = strings.TrimSpace(newName)
 	if newName == "" {
 		newName = node.Sponsor.Name
 	}
 
-	fmt.Printf("赞助商网址 (当前: %s): ", node.Sponsor.URL)
+	fmt.Printf(utils.T("node.sponsor_url"), node.Sponsor.URL)
 	newURL, _ := reader.ReadString('\n')
 	newURL = strings.TrimSpace(newURL)
 	if newURL == "" {
 		newURL = node.Sponsor.URL
 	}
 
-	fmt.Printf("赞助商图片 (当前: %s): ", node.Sponsor.Banner)
+	fmt.Printf(utils.T("node.sponsor_banner"), node.Sponsor.Banner)
 	newBanner, _ := reader.ReadString('\n')
 	newBanner = strings.TrimSpace(newBanner)
 	if newBanner == "" {
@@ -366,7 +485,7 @@ func (s *NodeService) editSponsorInfo(node *models.Node) error {
 	}
 
 	// 确认修改
-	fmt.Print(utils.ColorText(utils.Yellow, "\n确认修改? (y/N): "))
+	fmt.Print(utils.ColorText(utils.RolePrompt, utils.T("node.confirm_update")))
 	confirm, _ := reader.ReadString('\n')
 	confirm = strings.ToLower(strings.TrimSpace(confirm))
 
@@ -379,7 +498,7 @@ func (s *NodeService) editSponsorInfo(node *models.Node) error {
 		if err := s.UpdateNodeSponsor(node.ID, sponsor); err != nil {
 			return err
 		}
-		fmt.Println(utils.ColorText(utils.Yellow, "\n提示: 赞助商信息的修改需要管理员审核后才会生效"))
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("node.sponsor_review")))
 	}
 
 	return nil
@@ -387,84 +506,84 @@ func (s *NodeService) editSponsorInfo(node *models.Node) error {
 
 // 显示节点详情（原来的显示逻辑）
 func (s *NodeService) showNodeDetail(node *models.Node) {
-	fmt.Printf("\n%s\n", utils.ColorText(utils.Bold+utils.Blue, "📝 节点详情"))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("node.detail_title")))
 	fmt.Println(strings.Repeat("─", 50))
 
 	// 基本信息
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "节点名称:"),
-		utils.ColorText(utils.Cyan, node.Name))
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_name")),
+		utils.ColorText(utils.RoleValue, node.Name))
 
 	// 运行状态
-	statusColor := utils.Green
-	status := "在线"
+	statusColor := utils.RoleOK
+	status := utils.T("common.online")
 	if !node.IsEnabled {
-		statusColor = utils.Red
-		status = "离线"
+		statusColor = utils.RoleError
+		status = utils.T("common.offline")
 		if node.DownReason != "" {
-			status = fmt.Sprintf("离线 (%s)", node.DownReason)
+			status = utils.T("node.offline_reason", node.DownReason)
 		}
 	}
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "运行状态:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_status")),
 		utils.ColorText(statusColor, status))
 
 	// Ban 状态
 	if node.IsBanned {
 		fmt.Printf("%s %s\n",
-			utils.ColorText(utils.Yellow, "封禁状态:"),
-			utils.ColorText(utils.Red, fmt.Sprintf("已封禁 (%s)", node.BanReason)))
+			utils.ColorText(utils.RoleLabel, utils.T("node.field_ban")),
+			utils.ColorText(utils.RoleError, utils.T("node.banned_reason", node.BanReason)))
 	}
 
 	// 其他信息保持不变...
 	fmt.Printf("%s %d Mbps\n",
-		utils.ColorText(utils.Yellow, "设置带宽:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_bandwidth")),
 		node.Bandwidth)
 
 	fmt.Printf("%s %d Mbps\n",
-		utils.ColorText(utils.Yellow, "实测带宽:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_measured")),
 		node.MeasureBandwidth)
 
 	// 运行信息
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "运行环境:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_runtime")),
 		node.Flavor.Runtime)
 
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "存储类型:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_storage")),
 		node.Flavor.Storage)
 
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "程序版本:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_version")),
 		node.Version)
 
 	// 节点状态
-	trustColor := utils.Green
+	trustColor := utils.RoleOK
 	if node.Trust < 0 {
-		trustColor = utils.Red
+		trustColor = utils.RoleError
 	}
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "信任度:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_trust")),
 		utils.ColorText(trustColor, fmt.Sprintf("%d", node.Trust)))
 
 	// 时间信息
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "创建时间:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_created")),
 		node.CreatedAt.Format("2006-01-02 15:04:05"))
 
 	fmt.Printf("%s %s\n",
-		utils.ColorText(utils.Yellow, "最后活动:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_last_active")),
 		node.LastActivity.Format("2006-01-02 15:04:05"))
 
 	if !node.Uptime.IsZero() {
 		fmt.Printf("%s %s\n",
-			utils.ColorText(utils.Yellow, "上线时间:"),
+			utils.ColorText(utils.RoleLabel, utils.T("node.field_up_since")),
 			node.Uptime.Format("2006-01-02 15:04:05"))
 	}
 
 	if !node.Downtime.IsZero() {
 		fmt.Printf("%s %s\n",
-			utils.ColorText(utils.Yellow, "下线时间:"),
+			utils.ColorText(utils.RoleLabel, utils.T("node.field_down_since")),
 			node.Downtime.Format("2006-01-02 15:04:05"))
 	}
 
@@ -472,17 +591,17 @@ func (s *NodeService) showNodeDetail(node *models.Node) {
 	if node.Sponsor.Name != "" {
 		fmt.Println(strings.Repeat("─", 50))
 		fmt.Printf("%s %s\n",
-			utils.ColorText(utils.Yellow, "赞助商:"),
+			utils.ColorText(utils.RoleLabel, utils.T("node.field_sponsor")),
 			node.Sponsor.Name)
 		fmt.Printf("%s %s\n",
-			utils.ColorText(utils.Yellow, "赞助商网站:"),
+			utils.ColorText(utils.RoleLabel, utils.T("node.field_sponsor_url")),
 			node.Sponsor.URL)
 	}
 
 	// 节点地址
 	fmt.Println(strings.Repeat("─", 50))
 	fmt.Printf("%s %s://%s:%d\n",
-		utils.ColorText(utils.Yellow, "节点地址:"),
+		utils.ColorText(utils.RoleLabel, utils.T("node.field_endpoint")),
 		node.Endpoint.Proto,
 		node.Endpoint.Host,
 		node.Endpoint.Port)
@@ -529,6 +648,11 @@ func (s *NodeService) GetNodeMetricRank(ctx context.Context) ([]NodeMetricRank,
 	if token := ctx.Value("token"); token != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
+	if id := s.requestID; id != "" {
+		req.Header.Set(utils.RequestIDHeader, id)
+	} else if id := RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(utils.RequestIDHeader, id)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -543,33 +667,3 @@ func (s *NodeService) GetNodeMetricRank(ctx context.Context) ([]NodeMetricRank,
 
 	return ranks, nil
 }
-
-// 修改响应处理函数
-func (s *NodeService) HandleGetNodeMetricRank(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		resp := models.ResponseError(http.StatusMethodNotAllowed, "Method not allowed")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(resp.Code)
-		json.NewEncoder(w).Encode(resp)
-		return
-	}
-
-	ranks, err := s.GetNodeMetricRank(r.Context())
-	if err != nil {
-		resp := models.ResponseError(http.StatusInternalServerError, err.Error())
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(resp.Code)
-		json.NewEncoder(w).Encode(resp)
-		return
-	}
-
-	resp := models.ResponseSuccess(ranks)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
-}
-
-// 在 RegisterRoutes 方法中添加新的路由
-func (s *NodeService) RegisterRoutes() {
-	// ... 其他路由 ...
-	http.HandleFunc("/api/nodes/rank", s.HandleGetNodeMetricRank)
-}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestBuildNodeDetailUsesRankListOrder(t *testing.T) {
+	// 上游排行榜的顺序不一定与今日流量一致
+	ranks := make([]NodeMetricRank, 3)
+	for i, r := range []struct {
+		id    string
+		bytes int64
+	}{{"a", 100}, {"b", 300}, {"c", 200}} {
+		ranks[i].ID = r.id
+		ranks[i].Metric.Bytes = r.bytes
+	}
+
+	detail := NewNode().BuildNodeDetail(&models.Node{ID: "c"}, ranks, time.Now())
+	if detail.TodayRank == nil {
+		t.Fatal("节点在排行榜中时应返回今日排名")
+	}
+	// 与 NewRankSnapshot 和排行榜列表一致
+	want := NewRankSnapshot(ranks, time.Now()).Entries[2].Rank
+	if detail.TodayRank.Rank != want || detail.TodayRank.Total != 3 {
+		t.Fatalf("今日排名为 %d/%d，应为 %d/3", detail.TodayRank.Rank, detail.TodayRank.Total, want)
+	}
+}
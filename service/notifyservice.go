@@ -0,0 +1,449 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 默认消息模板，t 按当前语言输出消息
+const defaultNotifyTemplate = `[{{.Severity}}] {{.Title}}
+{{.Message}}{{if .ClusterName}}
+{{t "common.node"}}: {{.ClusterName}} ({{.Cluster}}){{end}}
+{{t "notify.time"}}: {{.Time.Format "2006-01-02 15:04:05"}}`
+
+const defaultNotifySubject = `[OpenBMCLAPI][{{.Severity}}] {{.Title}}`
+
+const defaultTelegramAPI = "https://api.telegram.org"
+
+type NotifyService struct {
+	config models.NotifyConfig
+}
+
+func NewNotify(config models.NotifyConfig) *NotifyService {
+	return &NotifyService{
+		config: config,
+	}
+}
+
+// Channels 返回已启用的通知渠道，已禁用的渠道只能通过 FindChannel 按名称取得
+func (s *NotifyService) Channels() []models.NotifyChannel {
+	var channels []models.NotifyChannel
+	for _, ch := range s.config.Channels {
+		if !ch.Disabled {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+// FindChannel 按名称查找通知渠道
+func (s *NotifyService) FindChannel(name string) (models.NotifyChannel, bool) {
+	for _, ch := range s.config.Channels {
+		if ch.Name == name {
+			return ch, true
+		}
+	}
+	return models.NotifyChannel{}, false
+}
+
+// Notify 将告警发送到所有匹配级别和节点的渠道
+func (s *NotifyService) Notify(alert models.Alert) error {
+	var errs []error
+	for _, ch := range s.config.Channels {
+		if ch.Disabled || !channelMatches(ch, alert) {
+			continue
+		}
+		if err := s.SendTo(ch, alert); err != nil {
+			errs = append(errs, fmt.Errorf(utils.T("notify.channel_error"), ch.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SendTo 向指定渠道发送告警，失败时按配置重试
+func (s *NotifyService) SendTo(ch models.NotifyChannel, alert models.Alert) error {
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	text, err := renderTemplate(ch.Template, defaultNotifyTemplate, alert)
+	if err != nil {
+		return err
+	}
+
+	if ch.Type == "email" {
+		subject, err := renderTemplate(ch.Subject, defaultNotifySubject, alert)
+		if err != nil {
+			return err
+		}
+		return s.retry(ch, func() error {
+			return sendEmail(ch, subject, text, "text/plain")
+		})
+	}
+	return s.deliverParts(ch, alert, text)
+}
+
+// SendDocument 向指定渠道发送一份完整文档 (如周期报告)，不套用消息模板，文档正文应自带标题
+// contentType 仅对邮件渠道生效，例如 text/plain 或 text/html
+func (s *NotifyService) SendDocument(ch models.NotifyChannel, title, body, contentType string) error {
+	if ch.Type == "email" {
+		return s.retry(ch, func() error {
+			return sendEmail(ch, title, body, contentType)
+		})
+	}
+	alert := models.Alert{
+		Rule:     "document",
+		Severity: models.SeverityInfo,
+		Title:    title,
+		Message:  body,
+		Time:     time.Now(),
+	}
+	return s.deliverParts(ch, alert, body)
+}
+
+// deliverParts 按渠道的消息长度限制拆分消息，逐段投递，每段分别重试，避免重试时重复发送已送达的部分
+func (s *NotifyService) deliverParts(ch models.NotifyChannel, alert models.Alert, text string) error {
+	for _, part := range splitMessage(text, messageLimits[ch.Type]) {
+		if err := s.retry(ch, func() error {
+			return deliver(ch, alert, part)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry 执行 fn，失败时按配置重试，重试次数为负数时按 0 处理，至少执行一次
+func (s *NotifyService) retry(ch models.NotifyChannel, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= max(s.config.Retries, 0); attempt++ {
+		if attempt > 0 {
+			utils.DebugLog(1, "[通知] 渠道 %s 第 %d 次重试: %v", ch.Name, attempt, err)
+			time.Sleep(time.Duration(s.config.RetryDelay) * time.Second)
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// channelMatches 判断渠道是否接收该告警
+func channelMatches(ch models.NotifyChannel, alert models.Alert) bool {
+	if len(ch.Severities) > 0 && !containsString(ch.Severities, alert.Severity) {
+		return false
+	}
+	if len(ch.Clusters) > 0 &&
+		!containsString(ch.Clusters, alert.Cluster) &&
+		!containsString(ch.Clusters, alert.ClusterName) {
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTemplate 使用模板渲染告警内容
+func renderTemplate(text, fallback string, alert models.Alert) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New("notify").Funcs(template.FuncMap{"t": utils.T}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf(utils.T("notify.parse_template_failed"), err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf(utils.T("notify.render_template_failed"), err)
+	}
+	return buf.String(), nil
+}
+
+// messageLimit 渠道单条消息的长度上限，size 返回一个字符计入上限的长度
+type messageLimit struct {
+	max  int
+	size func(r rune) int
+}
+
+// messageLimits 有长度限制的渠道，超过时拆分为多条消息。
+// Telegram 按 UTF-16 编码单元计算，Discord 按字符计算，企业微信按 UTF-8 字节计算
+var messageLimits = map[string]messageLimit{
+	"telegram": {4096, func(r rune) int { return max(utf16.RuneLen(r), 1) }},
+	"discord":  {2000, func(r rune) int { return 1 }},
+	"wecom":    {2048, func(r rune) int { return max(utf8.RuneLen(r), 1) }},
+}
+
+// splitMessage 把消息拆分为不超过上限的若干段，优先在换行处拆分，单行超过上限时按字符拆分
+func splitMessage(text string, limit messageLimit) []string {
+	measure := func(s string) int {
+		n := 0
+		for _, r := range s {
+			n += limit.size(r)
+		}
+		return n
+	}
+	if limit.max <= 0 || measure(text) <= limit.max {
+		return []string{text}
+	}
+
+	var parts []string
+	var part strings.Builder
+	size := 0
+	flush := func() {
+		// 拆分处的换行不再需要，只剩换行的段不发送
+		if text := strings.TrimSuffix(part.String(), "\n"); text != "" {
+			parts = append(parts, text)
+		}
+		part.Reset()
+		size = 0
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineSize := measure(line)
+		if size+lineSize > limit.max {
+			flush()
+		}
+		if lineSize <= limit.max {
+			part.WriteString(line)
+			size += lineSize
+			continue
+		}
+		for _, r := range line {
+			n := limit.size(r)
+			if size+n > limit.max {
+				flush()
+			}
+			part.WriteRune(r)
+			size += n
+		}
+	}
+	flush()
+	return parts
+}
+
+// deliver 按渠道类型投递已渲染的消息
+func deliver(ch models.NotifyChannel, alert models.Alert, text string) error {
+	switch ch.Type {
+	case "webhook":
+		return postJSON(ch.URL, struct {
+			models.Alert
+			Text string `json:"text"`
+		}{alert, text})
+	case "discord":
+		return postJSON(ch.URL, map[string]string{"content": text})
+	case "slack":
+		return postJSON(ch.URL, map[string]string{"text": text})
+	case "telegram":
+		return sendTelegram(ch, text)
+	case "dingtalk":
+		return sendDingTalk(ch, text)
+	case "feishu", "lark":
+		return sendFeishu(ch, text)
+	case "wecom":
+		return postJSON(ch.URL, map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]string{"content": text},
+		})
+	default:
+		return fmt.Errorf(utils.T("notify.unsupported_type"), ch.Type)
+	}
+}
+
+// postJSON 发送 JSON 请求并检查机器人接口返回的错误码
+func postJSON(target string, body interface{}) error {
+	if target == "" {
+		return errors.New(utils.T("notify.no_url"))
+	}
+
+	client := utils.NewHTTPClient()
+	resp, err := client.DoPost(target, body, nil)
+	if err != nil {
+		// 连接错误中包含完整地址，webhook 地址本身就是密钥
+		return redactError(err, target, utils.RedactURL(target))
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf(utils.T("notify.bad_status"), resp.StatusCode, strings.TrimSpace(string(resp.Body)))
+	}
+
+	// 钉钉/企业微信返回 errcode，飞书返回 code，Telegram 返回 ok
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+		OK      *bool  `json:"ok"`
+		Desc    string `json:"description"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil
+	}
+	switch {
+	case result.ErrCode != nil && *result.ErrCode != 0:
+		return fmt.Errorf(utils.T("notify.api_error_code"), *result.ErrCode, result.ErrMsg)
+	case result.Code != nil && *result.Code != 0:
+		return fmt.Errorf(utils.T("notify.api_error_code"), *result.Code, result.Msg)
+	case result.OK != nil && !*result.OK:
+		return fmt.Errorf(utils.T("notify.api_error"), result.Desc)
+	}
+	return nil
+}
+
+func sendTelegram(ch models.NotifyChannel, text string) error {
+	if ch.Token == "" || ch.ChatID == "" {
+		return errors.New(utils.T("notify.no_telegram"))
+	}
+	base := ch.URL
+	if base == "" {
+		base = defaultTelegramAPI
+	}
+	target := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(base, "/"), ch.Token)
+	err := postJSON(target, map[string]string{
+		"chat_id": ch.ChatID,
+		"text":    text,
+	})
+	// 错误信息中的地址可能经过转义，与 target 不完全相同，再按 token 替换一次
+	return redactError(err, ch.Token, "***")
+}
+
+// redactError 把错误信息中的密钥替换掉，避免重试日志和命令输出泄露密钥
+func redactError(err error, secret, replacement string) error {
+	if err == nil || secret == "" || !strings.Contains(err.Error(), secret) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), secret, replacement))
+}
+
+func sendDingTalk(ch models.NotifyChannel, text string) error {
+	target := ch.URL
+	if ch.Secret != "" {
+		// 钉钉加签: HmacSHA256(timestamp + "\n" + secret)
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(ch.Secret))
+		mac.Write([]byte(timestamp + "\n" + ch.Secret))
+		sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target = fmt.Sprintf("%s%stimestamp=%s&sign=%s", target, sep, timestamp, url.QueryEscape(sign))
+	}
+	return postJSON(target, map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": text},
+	})
+}
+
+func sendFeishu(ch models.NotifyChannel, text string) error {
+	body := map[string]interface{}{
+		"msg_type": "text",
+		"content":  map[string]string{"text": text},
+	}
+	if ch.Secret != "" {
+		// 飞书加签: 以 timestamp + "\n" + secret 为密钥对空串做 HmacSHA256
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(timestamp+"\n"+ch.Secret))
+		body["timestamp"] = timestamp
+		body["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	return postJSON(ch.URL, body)
+}
+
+func sendEmail(ch models.NotifyChannel, subject, text, contentType string) error {
+	if ch.Host == "" || ch.From == "" || len(ch.To) == 0 {
+		return errors.New(utils.T("notify.no_smtp"))
+	}
+
+	port := ch.Port
+	if port == 0 {
+		port = 25
+		if ch.TLS {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(ch.Host, strconv.Itoa(port))
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", ch.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(ch.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if ch.Username != "" {
+		auth = smtp.PlainAuth("", ch.Username, ch.Password, ch.Host)
+	}
+
+	if !ch.TLS {
+		// smtp.SendMail 会在服务器支持时自动升级 STARTTLS
+		return smtp.SendMail(addr, auth, ch.From, ch.To, msg.Bytes())
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: ch.Host})
+	if err != nil {
+		return fmt.Errorf(utils.T("notify.smtp_connect_failed"), err)
+	}
+	client, err := smtp.NewClient(conn, ch.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf(utils.T("notify.smtp_connect_failed"), err)
+	}
+	defer client.Close()
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf(utils.T("notify.smtp_auth_failed"), err)
+		}
+	}
+	if err := client.Mail(ch.From); err != nil {
+		return err
+	}
+	for _, to := range ch.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+var testAlert = models.Alert{
+	Rule:     "test",
+	Severity: models.SeverityWarning,
+	Title:    "节点离线",
+	Message:  "test message",
+	Time:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+// capturedRequest 本地 HTTP 替身收到的请求
+type capturedRequest struct {
+	Path  string
+	Query string
+	Body  map[string]interface{}
+}
+
+// newBotServer 启动一个本地 HTTP 替身，记录收到的请求并返回 reply
+func newBotServer(t *testing.T, reply string) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("请求体不是 JSON: %v", err)
+		}
+		requests <- capturedRequest{Path: r.URL.Path, Query: r.URL.RawQuery, Body: body}
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestNotifyHTTPChannels(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel models.NotifyChannel
+		reply   string
+		check   func(t *testing.T, req capturedRequest)
+	}{
+		{
+			name:    "webhook",
+			channel: models.NotifyChannel{Type: "webhook"},
+			check: func(t *testing.T, req capturedRequest) {
+				if req.Body["rule"] != "test" || req.Body["severity"] != models.SeverityWarning {
+					t.Errorf("webhook 请求体缺少告警字段: %v", req.Body)
+				}
+				if !strings.Contains(req.Body["text"].(string), "节点离线") {
+					t.Errorf("webhook 请求体缺少消息: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "discord",
+			channel: models.NotifyChannel{Type: "discord"},
+			check: func(t *testing.T, req capturedRequest) {
+				if !strings.Contains(req.Body["content"].(string), "节点离线") {
+					t.Errorf("discord 请求体错误: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "slack",
+			channel: models.NotifyChannel{Type: "slack"},
+			check: func(t *testing.T, req capturedRequest) {
+				if !strings.Contains(req.Body["text"].(string), "节点离线") {
+					t.Errorf("slack 请求体错误: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "telegram",
+			channel: models.NotifyChannel{Type: "telegram", Token: "123:abc", ChatID: "42"},
+			reply:   `{"ok":true}`,
+			check: func(t *testing.T, req capturedRequest) {
+				if req.Path != "/bot123:abc/sendMessage" {
+					t.Errorf("telegram 路径错误: %s", req.Path)
+				}
+				if req.Body["chat_id"] != "42" {
+					t.Errorf("telegram chat_id 错误: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "dingtalk",
+			channel: models.NotifyChannel{Type: "dingtalk", Secret: "secret"},
+			reply:   `{"errcode":0,"errmsg":"ok"}`,
+			check: func(t *testing.T, req capturedRequest) {
+				if !strings.Contains(req.Query, "timestamp=") || !strings.Contains(req.Query, "sign=") {
+					t.Errorf("dingtalk 缺少加签参数: %s", req.Query)
+				}
+				if req.Body["msgtype"] != "text" {
+					t.Errorf("dingtalk 请求体错误: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "feishu",
+			channel: models.NotifyChannel{Type: "feishu", Secret: "secret"},
+			reply:   `{"code":0,"msg":"success"}`,
+			check: func(t *testing.T, req capturedRequest) {
+				if req.Body["msg_type"] != "text" || req.Body["sign"] == nil || req.Body["timestamp"] == nil {
+					t.Errorf("feishu 请求体错误: %v", req.Body)
+				}
+			},
+		},
+		{
+			name:    "wecom",
+			channel: models.NotifyChannel{Type: "wecom"},
+			reply:   `{"errcode":0,"errmsg":"ok"}`,
+			check: func(t *testing.T, req capturedRequest) {
+				text, _ := req.Body["text"].(map[string]interface{})
+				if req.Body["msgtype"] != "text" || !strings.Contains(text["content"].(string), "节点离线") {
+					t.Errorf("wecom 请求体错误: %v", req.Body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newBotServer(t, tt.reply)
+			ch := tt.channel
+			ch.Name = tt.name
+			ch.URL = server.URL
+			if err := NewNotify(models.NotifyConfig{}).SendTo(ch, testAlert); err != nil {
+				t.Fatalf("发送失败: %v", err)
+			}
+			tt.check(t, <-requests)
+		})
+	}
+}
+
+func TestNotifyAPIError(t *testing.T) {
+	server, _ := newBotServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	ch := models.NotifyChannel{Name: "ding", Type: "dingtalk", URL: server.URL}
+	err := NewNotify(models.NotifyConfig{}).SendTo(ch, testAlert)
+	if err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Fatalf("应返回接口错误码，实际为 %v", err)
+	}
+}
+
+func TestNotifyRedactsTelegramToken(t *testing.T) {
+	// 取一个已关闭的端口，让请求在连接阶段失败
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "http://" + listener.Addr().String()
+	listener.Close()
+
+	ch := models.NotifyChannel{Name: "tg", Type: "telegram", URL: base, Token: "123456:SECRET-TOKEN", ChatID: "1"}
+	err = NewNotify(models.NotifyConfig{}).SendTo(ch, testAlert)
+	if err == nil {
+		t.Fatal("连接已关闭的端口应失败")
+	}
+	if strings.Contains(err.Error(), "SECRET-TOKEN") {
+		t.Fatalf("错误信息泄露了 bot token: %v", err)
+	}
+}
+
+func TestChannelsSkipsDisabled(t *testing.T) {
+	notify := NewNotify(models.NotifyConfig{Channels: []models.NotifyChannel{
+		{Name: "on", Type: "webhook"},
+		{Name: "off", Type: "webhook", Disabled: true},
+	}})
+	channels := notify.Channels()
+	if len(channels) != 1 || channels[0].Name != "on" {
+		t.Fatalf("Channels 应跳过已禁用的渠道，实际为 %v", channels)
+	}
+	if _, ok := notify.FindChannel("off"); !ok {
+		t.Fatal("FindChannel 应能按名称找到已禁用的渠道")
+	}
+}
+
+// smtpStub 最小的 SMTP 替身，不支持 STARTTLS 和认证，记录收到的邮件内容
+func smtpStub(t *testing.T) (host string, port int, mail <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				messages <- data.String()
+				reply("250 ok")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+func TestNotifyEmail(t *testing.T) {
+	host, port, mail := smtpStub(t)
+	ch := models.NotifyChannel{
+		Name: "mail",
+		Type: "email",
+		Host: host,
+		Port: port,
+		From: "bot@example.com",
+		To:   []string{"ops@example.com"},
+	}
+	if err := NewNotify(models.NotifyConfig{}).SendTo(ch, testAlert); err != nil {
+		t.Fatalf("发送邮件失败: %v", err)
+	}
+
+	select {
+	case msg := <-mail:
+		for _, want := range []string{"From: bot@example.com", "To: ops@example.com", "Subject: =?utf-8?q?", "test message"} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("邮件缺少 %q:\n%s", want, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP 替身没有收到邮件")
+	}
+}
+
+func TestChannelMatches(t *testing.T) {
+	alert := models.Alert{Severity: models.SeverityCritical, Cluster: "5f0c1e2d3a4b5c6d7e8f9012", ClusterName: "Node-1"}
+	tests := []struct {
+		name    string
+		channel models.NotifyChannel
+		want    bool
+	}{
+		{"不限级别和节点", models.NotifyChannel{}, true},
+		{"级别匹配 (不区分大小写)", models.NotifyChannel{Severities: []string{"CRITICAL"}}, true},
+		{"级别不匹配", models.NotifyChannel{Severities: []string{models.SeverityInfo, models.SeverityWarning}}, false},
+		{"按节点 ID 匹配", models.NotifyChannel{Clusters: []string{"5f0c1e2d3a4b5c6d7e8f9012"}}, true},
+		{"按节点名称匹配", models.NotifyChannel{Clusters: []string{"node-1"}}, true},
+		{"节点不匹配", models.NotifyChannel{Clusters: []string{"node-2"}}, false},
+		{"级别匹配但节点不匹配", models.NotifyChannel{Severities: []string{models.SeverityCritical}, Clusters: []string{"node-2"}}, false},
+	}
+	for _, tt := range tests {
+		if got := channelMatches(tt.channel, alert); got != tt.want {
+			t.Errorf("%s: channelMatches = %v，应为 %v", tt.name, got, tt.want)
+		}
+	}
+
+	// 不属于任何节点的告警 (如周期报告) 不会发到限定了节点的渠道
+	if channelMatches(models.NotifyChannel{Clusters: []string{"node-1"}}, models.Alert{Severity: models.SeverityInfo}) {
+		t.Error("没有节点的告警不应匹配限定了节点的渠道")
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	telegram := messageLimits["telegram"]
+	measure := func(limit messageLimit, s string) int {
+		n := 0
+		for _, r := range s {
+			n += limit.size(r)
+		}
+		return n
+	}
+
+	// 未超过上限时原样发送
+	if parts := splitMessage("标题\n正文\n", telegram); len(parts) != 1 || parts[0] != "标题\n正文\n" {
+		t.Errorf("短消息应原样发送，实际为 %q", parts)
+	}
+
+	// 在换行处拆分，拼接后与原文相同
+	line := strings.Repeat("节", 1500)
+	text := line + "\n" + line + "\n" + line
+	parts := splitMessage(text, telegram)
+	if len(parts) != 2 || strings.Join(parts, "\n") != text {
+		t.Errorf("应在换行处拆分为 2 段，实际为 %d 段", len(parts))
+	}
+
+	// 单行超过上限时按字符拆分，emoji 在 Telegram 中占两个单位
+	text = strings.Repeat("🚀", 3000)
+	parts = splitMessage(text, telegram)
+	if strings.Join(parts, "") != text || len(parts) != 2 {
+		t.Errorf("超长的行应按字符拆分为 2 段，实际为 %d 段", len(parts))
+	}
+	for _, part := range parts {
+		if n := measure(telegram, part); n > telegram.max {
+			t.Errorf("每段不应超过 %d，实际为 %d", telegram.max, n)
+		}
+	}
+
+	// 企业微信按 UTF-8 字节计算
+	wecom := messageLimits["wecom"]
+	for _, part := range splitMessage(strings.Repeat("中文报告\n", 600), wecom) {
+		if len(part) > wecom.max {
+			t.Errorf("企业微信每段不应超过 %d 字节，实际为 %d", wecom.max, len(part))
+		}
+	}
+
+	// 没有限制的渠道不拆分
+	if parts := splitMessage(text, messageLimits["webhook"]); len(parts) != 1 {
+		t.Errorf("没有长度限制的渠道不应拆分，实际为 %d 段", len(parts))
+	}
+}
+
+// newCountingServer 记录每个请求的 JSON 请求体，按顺序返回
+func newCountingServer(t *testing.T) (*httptest.Server, func() []map[string]interface{}) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		io.WriteString(w, `{"ok": true}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}(nil), bodies...)
+	}
+}
+
+func TestNegativeRetriesStillSend(t *testing.T) {
+	server, bodies := newCountingServer(t)
+	ch := models.NotifyChannel{Name: "hook", Type: "webhook", URL: server.URL}
+	if err := NewNotify(models.NotifyConfig{Retries: -1}).SendTo(ch, testAlert); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(bodies()); n != 1 {
+		t.Fatalf("重试次数为负数时仍应发送一次，实际发送 %d 次", n)
+	}
+}
+
+func TestSendDocumentSplitsLongTelegramMessages(t *testing.T) {
+	server, bodies := newCountingServer(t)
+	ch := models.NotifyChannel{Name: "tg", Type: "telegram", URL: server.URL, Token: "123:abc", ChatID: "1"}
+	lines := []string{strings.Repeat("a", 3000), strings.Repeat("b", 3000), strings.Repeat("c", 3000)}
+	if err := NewNotify(models.NotifyConfig{}).SendDocument(ch, "report", strings.Join(lines, "\n"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := bodies()
+	if len(got) != len(lines) {
+		t.Fatalf("应拆分为 %d 条消息，实际为 %d 条", len(lines), len(got))
+	}
+	for i, body := range got {
+		if body["text"] != lines[i] {
+			t.Errorf("第 %d 条消息内容不正确", i+1)
+		}
+	}
+}
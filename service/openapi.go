@@ -0,0 +1,83 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// openAPISpec 是手写的 API 文档，新增或修改路由时需要同步更新
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+//go:embed apidocs.html
+var apiDocsPage []byte
+
+func handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func handleAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(apiDocsPage)
+}
+
+// CheckOpenAPI 比较已注册的路由和 OpenAPI 文档，返回所有不一致的地方
+// 包括文档缺少的路由、文档中多余的接口，以及权限标注与路由不符的接口
+func CheckOpenAPI() ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return nil, fmt.Errorf(utils.T("openapi.parse_failed"), err)
+	}
+
+	var problems []string
+	registered := make(map[string]bool)
+	for _, route := range (&WebService{}).apiRoutes() {
+		method, path, ok := strings.Cut(route.Pattern, " ")
+		if !ok {
+			problems = append(problems, utils.T("openapi.no_method", route.Pattern))
+			continue
+		}
+		method = strings.ToLower(method)
+		registered[method+" "+path] = true
+
+		raw, ok := spec.Paths[path][method]
+		if !ok {
+			problems = append(problems, utils.T("openapi.missing", strings.ToUpper(method), path))
+			continue
+		}
+		var op struct {
+			Capability string `json:"x-required-capability"`
+		}
+		if err := json.Unmarshal(raw, &op); err != nil {
+			return nil, fmt.Errorf(utils.T("openapi.parse_operation_failed"), strings.ToUpper(method), path, err)
+		}
+		if op.Capability != route.Capability {
+			problems = append(problems, utils.T("openapi.permission_mismatch",
+				strings.ToUpper(method), path, route.Capability, op.Capability))
+		}
+	}
+
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			if !registered[method+" "+path] {
+				problems = append(problems, utils.T("openapi.extra", strings.ToUpper(method), path))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import "testing"
+
+// TestOpenAPIMatchesRoutes 修改路由后需要同步更新 openapi.json
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	problems, err := CheckOpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
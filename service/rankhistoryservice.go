@@ -0,0 +1,406 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+const snapshotDateLayout = "2006-01-02"
+
+// rankLockTimeout 等待其他进程释放排行榜快照锁文件的最长时间
+const rankLockTimeout = 10 * time.Second
+
+// rankMu 串行化同一进程内的快照写入，锁文件只能区分不同进程
+var rankMu sync.Mutex
+
+type RankHistoryService struct {
+	dataDir string
+}
+
+func NewRankHistory(dataDir string) *RankHistoryService {
+	return &RankHistoryService{
+		dataDir: dataDir,
+	}
+}
+
+func (s *RankHistoryService) dir() string {
+	return filepath.Join(s.dataDir, "rank")
+}
+
+// NewRankSnapshot 将排行榜数据转换为快照
+func NewRankSnapshot(ranks []NodeMetricRank, at time.Time) models.RankSnapshot {
+	snapshot := models.RankSnapshot{
+		Date:    at.Local().Format(snapshotDateLayout),
+		Time:    at,
+		Entries: make([]models.RankEntry, 0, len(ranks)),
+	}
+	for i, rank := range ranks {
+		snapshot.Entries = append(snapshot.Entries, models.RankEntry{
+			ID:        rank.ID,
+			Name:      rank.Name,
+			Rank:      i + 1,
+			Hits:      rank.Metric.Hits,
+			Bytes:     rank.Metric.Bytes,
+			IsEnabled: rank.IsEnabled,
+		})
+	}
+	return snapshot
+}
+
+// Save 保存快照。排行榜数据是当日累计值，每天保留最晚的一份快照，越接近一天结束越完整：
+// 同一天较早的快照 (例如白天手动保存的) 会被之后的快照替换；当天已有更晚的快照时不覆盖并返回 false
+func (s *RankHistoryService) Save(snapshot models.RankSnapshot) (bool, error) {
+	rankMu.Lock()
+	defer rankMu.Unlock()
+
+	if err := os.MkdirAll(s.dir(), 0755); err != nil {
+		return false, fmt.Errorf(utils.T("common.mkdir_data_failed"), err)
+	}
+	// daemon 的定时快照和手动运行的 rank snapshot 可能同时比较并替换同一天的快照
+	unlock, err := utils.LockFile(filepath.Join(s.dir(), ".lock"), rankLockTimeout)
+	if err != nil {
+		return false, fmt.Errorf(utils.T("rank.lock_failed"), err)
+	}
+	defer unlock()
+
+	existing, err := s.Load(snapshot.Date)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && existing.Time.After(snapshot.Time) {
+		return false, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return false, fmt.Errorf(utils.T("rank.marshal_failed"), err)
+	}
+
+	// 先写临时文件再替换，写入失败时不会破坏已有的快照
+	path := filepath.Join(s.dir(), snapshot.Date+".json")
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return false, fmt.Errorf(utils.T("rank.save_failed"), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return false, fmt.Errorf(utils.T("rank.save_failed"), err)
+	}
+	return true, nil
+}
+
+// Load 读取指定日期的快照，不存在时返回 nil
+func (s *RankHistoryService) Load(date string) (*models.RankSnapshot, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir(), date+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(utils.T("rank.read_failed"), err)
+	}
+
+	var snapshot models.RankSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf(utils.T("rank.parse_failed"), err)
+	}
+	return &snapshot, nil
+}
+
+// Dates 返回所有快照日期，按时间升序
+func (s *RankHistoryService) Dates() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(utils.T("rank.read_dir_failed"), err)
+	}
+
+	var dates []string
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".json")
+		if _, err := time.Parse(snapshotDateLayout, name); err == nil && !file.IsDir() {
+			dates = append(dates, name)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// NodeHistory 返回节点在最近 days 份快照中的排名与流量，按日期升序
+func (s *RankHistoryService) NodeHistory(id string, days int) ([]models.RankHistoryPoint, error) {
+	dates, err := s.Dates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) > days {
+		dates = dates[len(dates)-days:]
+	}
+
+	points := make([]models.RankHistoryPoint, 0, len(dates))
+	for _, date := range dates {
+		snapshot, err := s.Load(date)
+		if err != nil {
+			return nil, err
+		}
+		point := models.RankHistoryPoint{Date: date}
+		if entry, ok := indexEntries(snapshot)[id]; ok {
+			point.Rank, point.Hits, point.Bytes = entry.Rank, entry.Hits, entry.Bytes
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// Latest 读取最近保存的一份快照，没有快照时返回 nil
+func (s *RankHistoryService) Latest() (*models.RankSnapshot, error) {
+	dates, err := s.Dates()
+	if err != nil || len(dates) == 0 {
+		return nil, err
+	}
+	return s.Load(dates[len(dates)-1])
+}
+
+// loadOnOrBefore 读取不晚于指定日期的最近一份快照
+func (s *RankHistoryService) loadOnOrBefore(dates []string, date string) (*models.RankSnapshot, error) {
+	for i := len(dates) - 1; i >= 0; i-- {
+		if dates[i] <= date {
+			return s.Load(dates[i])
+		}
+	}
+	return nil, nil
+}
+
+func indexEntries(snapshot *models.RankSnapshot) map[string]models.RankEntry {
+	index := make(map[string]models.RankEntry)
+	if snapshot != nil {
+		for _, entry := range snapshot.Entries {
+			index[entry.ID] = entry
+		}
+	}
+	return index
+}
+
+func compareEntry(current models.RankEntry, previous map[string]models.RankEntry) models.RankMovement {
+	movement := models.RankMovement{
+		ID:    current.ID,
+		Name:  current.Name,
+		Rank:  current.Rank,
+		Hits:  current.Hits,
+		Bytes: current.Bytes,
+	}
+	if prev, ok := previous[current.ID]; ok {
+		movement.PrevRank = prev.Rank
+		movement.RankChange = prev.Rank - current.Rank
+		movement.HitsDelta = current.Hits - prev.Hits
+		movement.BytesDelta = current.Bytes - prev.Bytes
+	}
+	return movement
+}
+
+// Report 生成排名变化报告，current 应为已保存的快照 (见 Latest)，与它之前保存的快照比较，
+// 双方都是接近当天结束时的累计值。own 为自己的节点 ID，watch 为配置中额外关注的节点 ID 或名称，top 为涨跌榜长度
+func (s *RankHistoryService) Report(current models.RankSnapshot, own, watch []string, top int) (*models.RankReport, error) {
+	dates, err := s.Dates()
+	if err != nil {
+		return nil, err
+	}
+
+	day, err := time.ParseInLocation(snapshotDateLayout, current.Date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf(utils.T("rank.invalid_date"), current.Date)
+	}
+	yesterday, err := s.loadOnOrBefore(dates, day.AddDate(0, 0, -1).Format(snapshotDateLayout))
+	if err != nil {
+		return nil, err
+	}
+	lastWeek, err := s.loadOnOrBefore(dates, day.AddDate(0, 0, -7).Format(snapshotDateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RankReport{Date: current.Date, Time: current.Time}
+	if yesterday != nil {
+		report.Yesterday = yesterday.Date
+	}
+	if lastWeek != nil {
+		report.LastWeek = lastWeek.Date
+	}
+	yesterdayIndex := indexEntries(yesterday)
+	lastWeekIndex := indexEntries(lastWeek)
+
+	// 关注的节点
+	ownSet := make(map[string]bool)
+	for _, id := range own {
+		ownSet[id] = true
+	}
+	found := make(map[string]bool)
+	for _, entry := range current.Entries {
+		isOwn := ownSet[entry.ID]
+		watched := containsString(watch, entry.ID) || containsString(watch, entry.Name)
+		if !isOwn && !watched {
+			continue
+		}
+		found[entry.ID] = true
+		found[strings.ToLower(entry.Name)] = true
+
+		tracked := models.RankTracked{Own: isOwn, Current: entry}
+		if yesterday != nil {
+			movement := compareEntry(entry, yesterdayIndex)
+			tracked.Yesterday = &movement
+		}
+		if lastWeek != nil {
+			movement := compareEntry(entry, lastWeekIndex)
+			tracked.LastWeek = &movement
+		}
+		report.Tracked = append(report.Tracked, tracked)
+	}
+	for _, key := range append(append([]string{}, own...), watch...) {
+		if !found[key] && !found[strings.ToLower(key)] {
+			report.Missing = append(report.Missing, key)
+		}
+	}
+
+	// 全榜涨跌幅
+	if yesterday != nil {
+		var movements []models.RankMovement
+		for _, entry := range current.Entries {
+			if _, ok := yesterdayIndex[entry.ID]; ok {
+				movements = append(movements, compareEntry(entry, yesterdayIndex))
+			}
+		}
+		sort.SliceStable(movements, func(i, j int) bool {
+			return movements[i].RankChange > movements[j].RankChange
+		})
+		for i := 0; i < len(movements) && len(report.Risers) < top && movements[i].RankChange > 0; i++ {
+			report.Risers = append(report.Risers, movements[i])
+		}
+		for i := len(movements) - 1; i >= 0 && len(report.Fallers) < top && movements[i].RankChange < 0; i-- {
+			report.Fallers = append(report.Fallers, movements[i])
+		}
+	}
+
+	return report, nil
+}
+
+// formatRankChange 格式化排名变化
+func formatRankChange(movement *models.RankMovement) string {
+	switch {
+	case movement == nil:
+		return "-"
+	case movement.PrevRank == 0:
+		return utils.ColorText(utils.RoleValue, utils.T("rank.new_entry"))
+	case movement.RankChange > 0:
+		return utils.ColorText(utils.RoleOK, fmt.Sprintf("↑%d", movement.RankChange))
+	case movement.RankChange < 0:
+		return utils.ColorText(utils.RoleError, fmt.Sprintf("↓%d", -movement.RankChange))
+	default:
+		return "—"
+	}
+}
+
+func formatSignedBytes(delta int64) string {
+	if delta < 0 {
+		return "-" + utils.FormatBytes(-delta)
+	}
+	return "+" + utils.FormatBytes(delta)
+}
+
+func formatDeltas(movement *models.RankMovement) string {
+	if movement == nil || movement.PrevRank == 0 {
+		return "-"
+	}
+	return utils.T("rank.delta", movement.HitsDelta, formatSignedBytes(movement.BytesDelta))
+}
+
+// DisplayReport 显示排名变化报告
+func (s *RankHistoryService) DisplayReport(report *models.RankReport) {
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("rank.report_title", report.Date)))
+	fmt.Println(strings.Repeat("─", 100))
+	fmt.Println(utils.ColorText(utils.RoleMuted, utils.T("rank.captured_at", report.Time.Local().Format("2006-01-02 15:04"))))
+
+	compareWith := func(date string) string {
+		if date == "" {
+			return utils.T("rank.no_history")
+		}
+		return date
+	}
+	fmt.Printf("%s %s    %s %s\n",
+		utils.ColorText(utils.RoleLabel, utils.T("rank.vs_yesterday")), compareWith(report.Yesterday),
+		utils.ColorText(utils.RoleLabel, utils.T("rank.vs_last_week")), compareWith(report.LastWeek))
+
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleHeader, utils.T("rank.watched")))
+	if len(report.Tracked) == 0 {
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("rank.no_watched")))
+	} else {
+		table := utils.NewTable(
+			utils.TableColumn{Title: utils.T("common.rank"), Right: true},
+			utils.TableColumn{Title: utils.T("common.node_name"), Wrap: true, MinWidth: 12},
+			utils.TableColumn{Title: utils.T("common.hits"), Right: true},
+			utils.TableColumn{Title: utils.T("common.traffic"), Right: true},
+			utils.TableColumn{Title: utils.T("rank.col_vs_yesterday")},
+			utils.TableColumn{Title: utils.T("rank.col_delta_yesterday")},
+			utils.TableColumn{Title: utils.T("rank.col_vs_last_week")},
+			utils.TableColumn{Title: utils.T("rank.col_delta_last_week")},
+		)
+		for _, tracked := range report.Tracked {
+			name := tracked.Current.Name
+			if tracked.Own {
+				name = "★ " + name
+			}
+			table.AddRow(
+				fmt.Sprintf("%d", tracked.Current.Rank),
+				utils.ColorText(utils.RoleValue, name),
+				utils.FormatNumber(tracked.Current.Hits),
+				utils.FormatBytes(tracked.Current.Bytes),
+				formatRankChange(tracked.Yesterday),
+				formatDeltas(tracked.Yesterday),
+				formatRankChange(tracked.LastWeek),
+				formatDeltas(tracked.LastWeek))
+		}
+		table.Print()
+	}
+	if len(report.Missing) > 0 {
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("rank.not_ranked_list", strings.Join(report.Missing, ", "))))
+	}
+
+	displayMovers := func(title string, movements []models.RankMovement) {
+		fmt.Printf("\n%s\n", utils.ColorText(utils.RoleHeader, title))
+		if len(movements) == 0 {
+			fmt.Println("-")
+			return
+		}
+		table := utils.NewTable(
+			utils.TableColumn{Title: utils.T("common.rank"), Right: true},
+			utils.TableColumn{Title: utils.T("rank.col_yesterday_rank"), Right: true},
+			utils.TableColumn{Title: utils.T("rank.col_change")},
+			utils.TableColumn{Title: utils.T("common.node_name"), Wrap: true, MinWidth: 12},
+			utils.TableColumn{Title: utils.T("rank.col_hits_delta"), Right: true},
+			utils.TableColumn{Title: utils.T("rank.col_traffic_delta"), Right: true},
+		)
+		for i := range movements {
+			movement := movements[i]
+			table.AddRow(
+				fmt.Sprintf("%d", movement.Rank),
+				fmt.Sprintf("%d", movement.PrevRank),
+				formatRankChange(&movement),
+				utils.ColorText(utils.RoleValue, movement.Name),
+				fmt.Sprintf("%+d", movement.HitsDelta),
+				formatSignedBytes(movement.BytesDelta))
+		}
+		table.Print()
+	}
+	displayMovers(utils.T("rank.top_risers"), report.Risers)
+	displayMovers(utils.T("rank.top_fallers"), report.Fallers)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaveReplacesEarlierSnapshotOfDay(t *testing.T) {
+	s := NewRankHistory(t.TempDir())
+	morning := time.Date(2026, 3, 1, 8, 0, 0, 0, time.Local)
+	scheduled := time.Date(2026, 3, 1, 23, 55, 0, 0, time.Local)
+
+	// 白天手动保存，之后 daemon 按计划保存
+	manual := NewRankSnapshot([]NodeMetricRank{{ID: "a"}}, morning)
+	if saved, err := s.Save(manual); err != nil || !saved {
+		t.Fatalf("手动快照应保存成功: saved=%v err=%v", saved, err)
+	}
+	later := NewRankSnapshot([]NodeMetricRank{{ID: "a"}, {ID: "b"}}, scheduled)
+	if saved, err := s.Save(later); err != nil || !saved {
+		t.Fatalf("定时快照应替换较早的快照: saved=%v err=%v", saved, err)
+	}
+
+	snapshot, err := s.Load(manual.Date)
+	if err != nil || snapshot == nil {
+		t.Fatalf("读取快照失败: %v", err)
+	}
+	if !snapshot.Time.Equal(scheduled) || len(snapshot.Entries) != 2 {
+		t.Fatalf("应保留定时快照，实际为 %v (%d 条)", snapshot.Time, len(snapshot.Entries))
+	}
+
+	// 更早的快照不覆盖已有的更晚快照
+	if saved, err := s.Save(manual); err != nil || saved {
+		t.Fatalf("较早的快照不应覆盖: saved=%v err=%v", saved, err)
+	}
+	if snapshot, _ = s.Load(manual.Date); !snapshot.Time.Equal(scheduled) {
+		t.Fatalf("较早的快照覆盖了定时快照: %v", snapshot.Time)
+	}
+}
+
+func TestReportComparesStoredSnapshots(t *testing.T) {
+	s := NewRankHistory(t.TempDir())
+	rank := func(id string, hits int64) NodeMetricRank {
+		r := NodeMetricRank{ID: id, Name: id}
+		r.Metric.Hits = hits
+		return r
+	}
+	day1 := time.Date(2026, 3, 1, 23, 55, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, snapshot := range []struct {
+		at    time.Time
+		ranks []NodeMetricRank
+	}{
+		{day1, []NodeMetricRank{rank("a", 300), rank("b", 200)}},
+		{day2, []NodeMetricRank{rank("b", 500), rank("a", 400)}},
+	} {
+		if _, err := s.Save(NewRankSnapshot(snapshot.ranks, snapshot.at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := s.Latest()
+	if err != nil || latest == nil {
+		t.Fatalf("读取最近的快照失败: %v", err)
+	}
+	if !latest.Time.Equal(day2) {
+		t.Fatalf("最近的快照应为 %v，实际为 %v", day2, latest.Time)
+	}
+	report, err := s.Report(*latest, []string{"a"}, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Yesterday != "2026-03-01" || !report.Time.Equal(day2) {
+		t.Fatalf("应与前一天保存的快照比较: %+v", report)
+	}
+	if len(report.Tracked) != 1 {
+		t.Fatalf("应有一个关注节点，实际为 %d", len(report.Tracked))
+	}
+	movement := report.Tracked[0].Yesterday
+	if movement == nil || movement.RankChange != -1 || movement.HitsDelta != 100 {
+		t.Fatalf("排名和请求数变化不正确: %+v", movement)
+	}
+	if len(report.Risers) != 1 || report.Risers[0].ID != "b" {
+		t.Fatalf("上升榜应只有 b: %+v", report.Risers)
+	}
+}
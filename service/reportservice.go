@@ -0,0 +1,536 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 支持的报告格式及文件扩展名
+var reportFormats = map[string]string{
+	"markdown": "md",
+	"html":     "html",
+	"text":     "txt",
+}
+
+type ReportService struct {
+	config *models.Config
+}
+
+func NewReport(config *models.Config) *ReportService {
+	return &ReportService{
+		config: config,
+	}
+}
+
+// ReportRange 根据周期计算报告的起止日期 (含首尾)
+// daily 为昨天，weekly 为截至昨天的 7 天，当天数据尚未结束因此不计入
+func ReportRange(period string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today.AddDate(0, 0, -1)
+	switch period {
+	case "daily":
+		return to, to, nil
+	case "weekly":
+		return to.AddDate(0, 0, -6), to, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf(utils.T("report.unsupported_period"), period)
+	}
+}
+
+// CustomReportRange 按 -from、-to 给出的日期 (YYYY-MM-DD) 计算报告区间，两个都为空时使用 period 的默认区间。
+// 只给出一个日期时按 period 补全另一个：只有开始日期时结束于昨天，只有结束日期时向前取一个周期
+func CustomReportRange(period, fromText, toText string, now time.Time) (time.Time, time.Time, error) {
+	from, to, err := ReportRange(period, now)
+	if err != nil {
+		return from, to, err
+	}
+	days := int(to.Sub(from).Hours()/24 + 0.5)
+
+	if toText != "" {
+		if to, err = time.ParseInLocation(snapshotDateLayout, toText, now.Location()); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf(utils.T("cmd.report.invalid_to"), toText)
+		}
+		from = to.AddDate(0, 0, -days)
+	}
+	if fromText != "" {
+		if from, err = time.ParseInLocation(snapshotDateLayout, fromText, now.Location()); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf(utils.T("cmd.report.invalid_from"), fromText)
+		}
+	}
+	return from, to, nil
+}
+
+// Build 汇总指定日期区间 (含首尾) 的数据
+func (s *ReportService) Build(from, to time.Time) (*models.Report, error) {
+	if to.Before(from) {
+		return nil, errors.New(utils.T("report.end_before_start"))
+	}
+
+	report := &models.Report{
+		From:        from.Format(snapshotDateLayout),
+		To:          to.Format(snapshotDateLayout),
+		GeneratedAt: time.Now(),
+	}
+	report.Title = utils.T("report.title_range", report.From, report.To)
+	if report.From == report.To {
+		report.Title = utils.T("report.title_daily", report.From)
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		report.Dates = append(report.Dates, day.Format(snapshotDateLayout))
+	}
+
+	if dashboard, err := NewDashboard().GetDashboard(); err != nil {
+		report.Notes = append(report.Notes, utils.T("report.dashboard_failed", err))
+	} else {
+		report.Dashboard = dashboard
+	}
+
+	// 自己的节点与关注节点
+	own := make(map[string]string)
+	if nodes, err := NewNode().GetNodeList(); err != nil {
+		report.Notes = append(report.Notes, utils.T("report.own_nodes_failed", err))
+	} else {
+		for _, node := range nodes {
+			own[node.ID] = node.Name
+		}
+	}
+	return s.collect(report, from, to, own)
+}
+
+// collect 从排名快照和可用性记录汇总 own (自己的节点 ID 到名称) 与关注节点的数据
+func (s *ReportService) collect(report *models.Report, from, to time.Time, own map[string]string) (*models.Report, error) {
+	historyService := NewRankHistory(s.config.DataDir)
+	snapshots := make([]*models.RankSnapshot, len(report.Dates))
+	missing := 0
+	for i, date := range report.Dates {
+		snapshot, err := historyService.Load(date)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			missing++
+		}
+		snapshots[i] = snapshot
+	}
+	if missing > 0 {
+		report.Notes = append(report.Notes, utils.TN("report.missing_snapshots", missing))
+	}
+
+	clusters := make(map[string]*models.ReportCluster)
+	var order []string
+	addCluster := func(id, name string, isOwn bool) {
+		if _, ok := clusters[id]; !ok {
+			clusters[id] = &models.ReportCluster{ID: id, Name: name, Own: isOwn}
+			order = append(order, id)
+		}
+	}
+	// 按名称排列自己的节点，同一区间多次生成的报告内容一致，便于比较
+	ownIDs := make([]string, 0, len(own))
+	for id := range own {
+		ownIDs = append(ownIDs, id)
+	}
+	sort.Slice(ownIDs, func(i, j int) bool {
+		if own[ownIDs[i]] != own[ownIDs[j]] {
+			return own[ownIDs[i]] < own[ownIDs[j]]
+		}
+		return ownIDs[i] < ownIDs[j]
+	})
+	for _, id := range ownIDs {
+		addCluster(id, own[id], true)
+	}
+	for _, snapshot := range snapshots {
+		if snapshot == nil {
+			continue
+		}
+		for _, entry := range snapshot.Entries {
+			if containsString(s.config.WatchClusters, entry.ID) || containsString(s.config.WatchClusters, entry.Name) {
+				addCluster(entry.ID, entry.Name, false)
+			}
+		}
+	}
+
+	report.DailyBytes = make([]int64, len(report.Dates))
+	for _, id := range order {
+		cluster := clusters[id]
+		for i, snapshot := range snapshots {
+			day := models.ReportDay{Date: report.Dates[i]}
+			if snapshot != nil {
+				for _, entry := range snapshot.Entries {
+					if entry.ID == id {
+						day.Rank = entry.Rank
+						day.Hits = entry.Hits
+						day.Bytes = entry.Bytes
+						break
+					}
+				}
+			}
+			cluster.Days = append(cluster.Days, day)
+			cluster.TotalHits += day.Hits
+			cluster.TotalBytes += day.Bytes
+			if day.Rank > 0 {
+				if cluster.StartRank == 0 {
+					cluster.StartRank = day.Rank
+				}
+				cluster.EndRank = day.Rank
+			}
+			if cluster.Own || len(own) == 0 {
+				report.DailyBytes[i] += day.Bytes
+			}
+		}
+		if cluster.Own || len(own) == 0 {
+			report.TotalHits += cluster.TotalHits
+			report.TotalBytes += cluster.TotalBytes
+		}
+	}
+
+	// 离线记录
+	uptimeService := NewUptime(s.config.DataDir)
+	records, err := uptimeService.Load()
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+	for _, id := range order {
+		cluster := clusters[id]
+		cluster.Uptime = models.UptimeReport{Availability: -1}
+		cluster.Outages = []models.Outage{}
+		rec, ok := records[id]
+		if !ok {
+			continue
+		}
+		cluster.Uptime = uptimeService.ReportBetween(rec, "period", from, end)
+		for _, outage := range rec.Outages {
+			outageEnd := outage.End
+			if outageEnd.IsZero() {
+				outageEnd = rec.LastPoll
+			}
+			if outage.Start.Before(end) && outageEnd.After(from) {
+				cluster.Outages = append(cluster.Outages, outage)
+			}
+		}
+	}
+
+	for _, id := range order {
+		report.Clusters = append(report.Clusters, *clusters[id])
+	}
+	return report, nil
+}
+
+// reportCharts 生成报告中的 SVG 图表
+func reportCharts(report *models.Report) map[string]string {
+	charts := make(map[string]string)
+
+	labels := make([]string, len(report.Dates))
+	values := make([]float64, len(report.Dates))
+	for i, date := range report.Dates {
+		labels[i] = date[5:]
+		values[i] = float64(report.DailyBytes[i]) / (1024 * 1024 * 1024)
+	}
+	charts["traffic"] = utils.SVGBarChart(utils.T("report.chart_daily_traffic"), "", labels, values, "#1890ff")
+
+	if report.Dashboard != nil && len(report.Dashboard.Hourly) > 0 {
+		hourly := report.Dashboard.Hourly
+		labels := make([]string, len(hourly))
+		values := make([]float64, len(hourly))
+		// GetDashboard 已按时间先后排列，与其他界面的图表一致
+		for i, h := range hourly {
+			labels[i] = utils.T("common.hour", h.ID)
+			values[i] = h.Bandwidth / 1000
+		}
+		charts["bandwidth"] = utils.SVGLineChart(utils.T("report.chart_bandwidth"), "", labels, values, "#52c41a")
+	}
+	return charts
+}
+
+// textBars 生成纯文本柱状图
+func textBars(report *models.Report) string {
+	var maxBytes int64
+	for _, b := range report.DailyBytes {
+		if b > maxBytes {
+			maxBytes = b
+		}
+	}
+	var sb strings.Builder
+	for i, date := range report.Dates {
+		width := 0
+		if maxBytes > 0 {
+			width = int(report.DailyBytes[i] * 40 / maxBytes)
+		}
+		fmt.Fprintf(&sb, "%s %s %s\n", date[5:], utils.PadWidth(strings.Repeat("█", width), 40, true), utils.FormatBytes(report.DailyBytes[i]))
+	}
+	return sb.String()
+}
+
+var reportFuncs = map[string]interface{}{
+	"bytes":     utils.FormatBytes,
+	"bandwidth": utils.FormatBandwidth,
+	"rank": func(rank int) string {
+		if rank == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("#%d", rank)
+	},
+	"change": func(start, end int) string {
+		if start == 0 || end == 0 || start == end {
+			return "-"
+		}
+		if end < start {
+			return fmt.Sprintf("↑%d", start-end)
+		}
+		return fmt.Sprintf("↓%d", end-start)
+	},
+	"avail":    formatAvailability,
+	"duration": formatSeconds,
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return utils.T("report.ongoing")
+		}
+		return t.Local().Format("2006-01-02 15:04")
+	},
+	"percent": func(v float64) string {
+		return fmt.Sprintf("%.2f%%", v*100)
+	},
+	"t":    utils.T,
+	"lang": utils.Locale,
+}
+
+const markdownReportTemplate = `# {{.Report.Title}}
+
+{{t "report.generated_at"}}: {{time .Report.GeneratedAt}}
+{{with .Report.Dashboard}}
+## {{t "report.overview"}}
+
+| {{t "report.col_online_nodes"}} | {{t "report.col_bandwidth"}} | {{t "report.col_traffic"}} | {{t "report.col_hits"}} | {{t "common.load"}} |
+| --- | --- | --- | --- | --- |
+| {{.CurrentNodes}} | {{bandwidth .CurrentBandwidth}} | {{bytes .Bytes}} | {{.Hits}} | {{percent .Load}} |
+{{end}}
+## {{t "report.node_traffic"}}
+
+{{t "report.totals" .Report.TotalHits (bytes .Report.TotalBytes)}}
+
+{{.Charts.traffic}}
+
+| {{t "common.node"}} | {{t "common.hits"}} | {{t "common.traffic"}} | {{t "report.col_start_rank"}} | {{t "report.col_end_rank"}} | {{t "rank.col_change"}} | {{t "report.col_availability"}} | {{t "report.col_failures"}} |
+| --- | ---: | ---: | ---: | ---: | --- | ---: | ---: |
+{{range .Report.Clusters}}| {{if .Own}}★ {{end}}{{.Name}} | {{.TotalHits}} | {{bytes .TotalBytes}} | {{rank .StartRank}} | {{rank .EndRank}} | {{change .StartRank .EndRank}} | {{avail .Uptime}} | {{.Uptime.Failures}} |
+{{end}}
+## {{t "report.outages"}}
+{{if not .OutageCount}}
+{{t "report.no_outages"}}
+{{end}}{{range .Report.Clusters}}{{$name := .Name}}{{range .Outages}}
+- **{{$name}}** {{time .Start}} ~ {{time .End}}{{if .Reason}}: {{.Reason}}{{end}}{{end}}{{end}}
+{{if .Charts.bandwidth}}
+## {{t "report.bandwidth_trend"}}
+
+{{.Charts.bandwidth}}
+{{end}}{{if .Report.Notes}}
+## {{t "report.notes"}}
+{{range .Report.Notes}}
+- {{.}}{{end}}
+{{end}}`
+
+const textReportTemplate = `{{.Report.Title}}
+{{t "report.generated_at"}}: {{time .Report.GeneratedAt}}
+{{with .Report.Dashboard}}
+[{{t "report.overview"}}]
+{{t "report.col_online_nodes"}}: {{.CurrentNodes}}  {{t "report.col_bandwidth"}}: {{bandwidth .CurrentBandwidth}}  {{t "report.col_traffic"}}: {{bytes .Bytes}}  {{t "report.col_hits"}}: {{.Hits}}
+{{end}}
+[{{t "report.node_traffic"}}]
+{{t "report.totals" .Report.TotalHits (bytes .Report.TotalBytes)}}
+{{.Bars}}
+{{range .Report.Clusters}}{{if .Own}}★ {{end}}{{.Name}}: {{t "report.cluster_line" .TotalHits (bytes .TotalBytes) (rank .StartRank) (rank .EndRank) (change .StartRank .EndRank) (avail .Uptime) .Uptime.Failures}}
+{{end}}
+[{{t "report.outages"}}]
+{{if not .OutageCount}}{{t "report.no_outages"}}
+{{end}}{{range .Report.Clusters}}{{$name := .Name}}{{range .Outages}}{{$name}} {{time .Start}} ~ {{time .End}} {{.Reason}}
+{{end}}{{end}}{{if .Report.Notes}}
+[{{t "report.notes"}}]
+{{range .Report.Notes}}- {{.}}
+{{end}}{{end}}`
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Report.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; max-width: 960px; margin: 24px auto; color: #333; padding: 0 16px; }
+h1 { font-size: 22px; } h2 { font-size: 18px; margin-top: 32px; border-bottom: 1px solid #eee; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; margin: 12px 0; }
+th, td { border: 1px solid #e8e8e8; padding: 6px 10px; text-align: left; }
+th { background: #fafafa; }
+.num { text-align: right; }
+.muted { color: #888; font-size: 13px; }
+.up { color: #52c41a; } .down { color: #ff4d4f; }
+</style>
+</head>
+<body>
+<h1>{{.Report.Title}}</h1>
+<p class="muted">{{t "report.generated_at"}}: {{time .Report.GeneratedAt}}</p>
+{{with .Report.Dashboard}}
+<h2>{{t "report.overview"}}</h2>
+<table>
+<tr><th>{{t "report.col_online_nodes"}}</th><th>{{t "report.col_bandwidth"}}</th><th>{{t "report.col_traffic"}}</th><th>{{t "report.col_hits"}}</th><th>{{t "common.load"}}</th></tr>
+<tr><td>{{.CurrentNodes}}</td><td>{{bandwidth .CurrentBandwidth}}</td><td>{{bytes .Bytes}}</td><td>{{.Hits}}</td><td>{{percent .Load}}</td></tr>
+</table>
+{{end}}
+<h2>{{t "report.node_traffic"}}</h2>
+<p>{{t "report.totals" .Report.TotalHits (bytes .Report.TotalBytes)}}</p>
+{{.Charts.traffic}}
+<table>
+<tr><th>{{t "common.node"}}</th><th class="num">{{t "common.hits"}}</th><th class="num">{{t "common.traffic"}}</th><th class="num">{{t "report.col_start_rank"}}</th><th class="num">{{t "report.col_end_rank"}}</th><th>{{t "rank.col_change"}}</th><th class="num">{{t "report.col_availability"}}</th><th class="num">{{t "report.col_failures"}}</th></tr>
+{{range .Report.Clusters}}<tr><td>{{if .Own}}★ {{end}}{{.Name}}</td><td class="num">{{.TotalHits}}</td><td class="num">{{bytes .TotalBytes}}</td><td class="num">{{rank .StartRank}}</td><td class="num">{{rank .EndRank}}</td><td>{{change .StartRank .EndRank}}</td><td class="num">{{avail .Uptime}}</td><td class="num">{{.Uptime.Failures}}</td></tr>
+{{end}}</table>
+<h2>{{t "report.outages"}}</h2>
+{{if not .OutageCount}}<p>{{t "report.no_outages"}}</p>{{else}}<table>
+<tr><th>{{t "common.node"}}</th><th>{{t "report.col_start"}}</th><th>{{t "report.col_end"}}</th><th>{{t "uptime.col_reason"}}</th></tr>
+{{range .Report.Clusters}}{{$name := .Name}}{{range .Outages}}<tr><td>{{$name}}</td><td>{{time .Start}}</td><td>{{time .End}}</td><td>{{.Reason}}</td></tr>
+{{end}}{{end}}</table>{{end}}
+{{if .Charts.bandwidth}}<h2>{{t "report.bandwidth_trend"}}</h2>
+{{.Charts.bandwidth}}{{end}}
+{{if .Report.Notes}}<h2>{{t "report.notes"}}</h2>
+<ul>{{range .Report.Notes}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`
+
+// Render 将报告渲染为 markdown、html 或 text 格式
+func (s *ReportService) Render(report *models.Report, format string) (string, error) {
+	charts := reportCharts(report)
+	outageCount := 0
+	for _, cluster := range report.Clusters {
+		outageCount += len(cluster.Outages)
+	}
+	var buf bytes.Buffer
+
+	switch format {
+	case "html":
+		safeCharts := make(map[string]htmltemplate.HTML)
+		for name, svg := range charts {
+			safeCharts[name] = htmltemplate.HTML(svg)
+		}
+		tmpl, err := htmltemplate.New("report").Funcs(reportFuncs).Parse(htmlReportTemplate)
+		if err != nil {
+			return "", fmt.Errorf(utils.T("report.parse_template_failed"), err)
+		}
+		if err := tmpl.Execute(&buf, map[string]interface{}{"Report": report, "Charts": safeCharts, "OutageCount": outageCount}); err != nil {
+			return "", fmt.Errorf(utils.T("report.render_failed"), err)
+		}
+	case "markdown", "text":
+		text := markdownReportTemplate
+		if format == "text" {
+			text = textReportTemplate
+		}
+		tmpl, err := template.New("report").Funcs(reportFuncs).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf(utils.T("report.parse_template_failed"), err)
+		}
+		data := map[string]interface{}{"Report": report, "Charts": charts, "Bars": textBars(report), "OutageCount": outageCount}
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf(utils.T("report.render_failed"), err)
+		}
+	default:
+		return "", fmt.Errorf(utils.T("report.unsupported_format"), format)
+	}
+
+	return buf.String(), nil
+}
+
+// WriteFile 渲染报告并写入目录，返回文件路径
+func (s *ReportService) WriteFile(report *models.Report, format, dir, name string) (string, error) {
+	ext, ok := reportFormats[format]
+	if !ok {
+		return "", fmt.Errorf(utils.T("report.unsupported_format"), format)
+	}
+	content, err := s.Render(report, format)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf(utils.T("report.mkdir_failed"), err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s_%s.%s", name, report.From, report.To, ext))
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf(utils.T("report.save_failed"), err)
+	}
+	return path, nil
+}
+
+// Send 通过通知渠道发送报告，邮件渠道优先发送 HTML，其余渠道发送纯文本
+func (s *ReportService) Send(report *models.Report, channelName string) error {
+	notifyService := NewNotify(s.config.Notify)
+	ch, ok := notifyService.FindChannel(channelName)
+	if !ok {
+		return fmt.Errorf(utils.T("cmd.channel_not_found"), channelName)
+	}
+
+	format, contentType := "text", "text/plain"
+	if ch.Type == "email" {
+		format, contentType = "html", "text/html"
+	}
+	content, err := s.Render(report, format)
+	if err != nil {
+		return err
+	}
+	return notifyService.SendDocument(ch, report.Title, content, contentType)
+}
+
+// RunJob 执行一个报告任务：生成报告、写入文件并发送到指定渠道
+func (s *ReportService) RunJob(job models.ReportJob, now time.Time) ([]string, error) {
+	from, to, err := ReportRange(job.Period, now)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.Build(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	formats := job.Formats
+	if len(formats) == 0 {
+		formats = []string{"markdown", "html", "text"}
+	}
+	dir := job.OutputDir
+	if dir == "" {
+		dir = filepath.Join(s.config.DataDir, "reports")
+	}
+
+	var files []string
+	for _, format := range formats {
+		path, err := s.WriteFile(report, format, dir, job.Name)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+
+	for _, channel := range job.Channels {
+		if err := s.Send(report, channel); err != nil {
+			return files, fmt.Errorf(utils.T("report.send_failed"), channel, err)
+		}
+	}
+	return files, nil
+}
+
+// FindJob 按名称查找报告任务
+func (s *ReportService) FindJob(name string) (models.ReportJob, bool) {
+	for _, job := range s.config.Reports {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return models.ReportJob{}, false
+}
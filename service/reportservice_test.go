@@ -0,0 +1,96 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestCustomReportRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name             string
+		period, from, to string
+		wantFrom, wantTo time.Time
+	}{
+		{"default weekly", "weekly", "", "", day(3), day(9)},
+		{"both", "weekly", "2026-03-01", "2026-03-05", day(1), day(5)},
+		{"only from", "weekly", "2026-03-01", "", day(1), day(9)},
+		{"only to weekly", "weekly", "", "2026-03-08", day(2), day(8)},
+		{"only to daily", "daily", "", "2026-03-08", day(8), day(8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := CustomReportRange(tt.period, tt.from, tt.to, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("区间为 %s ~ %s，应为 %s ~ %s", from.Format(snapshotDateLayout), to.Format(snapshotDateLayout),
+					tt.wantFrom.Format(snapshotDateLayout), tt.wantTo.Format(snapshotDateLayout))
+			}
+		})
+	}
+
+	if _, _, err := CustomReportRange("weekly", "", "2026/03/08", now); err == nil {
+		t.Error("无效的结束日期应返回错误")
+	}
+}
+
+func TestReportIsStableAcrossRuns(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	s := NewReport(cfg)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1)
+
+	own := map[string]string{"c": "gamma", "a": "alpha", "d": "delta", "b": "beta", "e": "epsilon"}
+	var ranks []NodeMetricRank
+	for id, name := range own {
+		rank := NodeMetricRank{ID: id, Name: name}
+		rank.Metric.Hits, rank.Metric.Bytes = 100, 1<<30
+		ranks = append(ranks, rank)
+	}
+	history := NewRankHistory(cfg.DataDir)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if _, err := history.Save(NewRankSnapshot(ranks, day.Add(23*time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	build := func() (*models.Report, map[string]string) {
+		report := &models.Report{From: "2026-03-01", To: "2026-03-02", Dates: []string{"2026-03-01", "2026-03-02"}}
+		report, err := s.collect(report, from, to, own)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rendered := make(map[string]string)
+		for format := range reportFormats {
+			if rendered[format], err = s.Render(report, format); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return report, rendered
+	}
+
+	first, firstRendered := build()
+	var names []string
+	for _, cluster := range first.Clusters {
+		names = append(names, cluster.Name)
+	}
+	if got := strings.Join(names, ","); got != "alpha,beta,delta,epsilon,gamma" {
+		t.Fatalf("节点应按名称排列，实际为 %s", got)
+	}
+	for i := 0; i < 5; i++ {
+		_, rendered := build()
+		for format, content := range rendered {
+			if content != firstRendered[format] {
+				t.Fatalf("同一区间两次生成的 %s 报告不一致", format)
+			}
+		}
+	}
+}
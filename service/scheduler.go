@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// CronSchedule 定义一个 cron 计划
+// 支持标准 5 段格式 (分 时 日 月 周)，以及 @hourly、@daily、@weekly、@monthly 和 @every <时长>
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	every                         time.Duration
+}
+
+var cronShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron 解析 cron 表达式
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf(utils.T("schedule.invalid_interval"), expr)
+		}
+		return &CronSchedule{every: d}, nil
+	}
+	if shortcut, ok := cronShortcuts[expr]; ok {
+		expr = shortcut
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(utils.T("schedule.cron_fields"), expr)
+	}
+
+	schedule := &CronSchedule{}
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// 周日可以写成 0 或 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domStar = fields[2] == "*" || fields[2] == "?"
+	schedule.dowStar = fields[4] == "*" || fields[4] == "?"
+	return schedule, nil
+}
+
+// parseCronField 解析单个字段，支持 *、列表、范围和步长
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[idx+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf(utils.T("schedule.invalid_step"), part)
+			}
+			part = part[:idx]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf(utils.T("schedule.invalid_range"), part)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf(utils.T("schedule.invalid_value"), part)
+			}
+			lo = value
+			if step == 1 {
+				hi = value
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf(utils.T("schedule.out_of_range"), min, max, field)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	// 与 cron 一致: 日和周都有限定时满足其一即可
+	if !c.domStar && !c.dowStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next 返回晚于 after 的下一次执行时间，找不到时返回零值
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	if c.every > 0 {
+		return after.Add(c.every)
+	}
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			// 按计划所在时区的整点前进，Truncate 按绝对时间取整，在半小时时差的时区会错过整点。
+			// 夏令时开始时下一个整点可能不存在，time.Date 返回的时间不晚于 t 时按绝对时间前进
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			if !next.After(t) {
+				next = t.Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// jobShutdownTimeout 调度循环退出时等待正在执行的任务结束的最长时间，
+// 任务中的上游请求可能不响应取消，不能让退出一直挂起
+const jobShutdownTimeout = 10 * time.Second
+
+type scheduledJob struct {
+	schedule *CronSchedule
+	run      func(ctx context.Context) error
+	status   models.JobStatus
+}
+
+// Scheduler 按 cron 计划执行任务，同一任务不会并发执行
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs []*scheduledJob
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add 注册任务
+func (s *Scheduler) Add(name, spec string, run func(ctx context.Context) error) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return fmt.Errorf(utils.T("schedule.job_error"), name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &scheduledJob{
+		schedule: schedule,
+		run:      run,
+		status: models.JobStatus{
+			Name:     name,
+			Schedule: spec,
+			NextRun:  schedule.Next(time.Now()),
+		},
+	})
+	return nil
+}
+
+// Status 返回所有任务的运行状态
+func (s *Scheduler) Status() []models.JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]models.JobStatus, len(s.jobs))
+	for i, job := range s.jobs {
+		list[i] = job.status
+	}
+	return list
+}
+
+// Run 运行调度循环，ctx 取消后等待正在执行的任务结束再返回，最多等待 jobShutdownTimeout
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wait(jobShutdownTimeout)
+
+	for {
+		s.mu.Lock()
+		var next time.Time
+		for _, job := range s.jobs {
+			if !job.status.NextRun.IsZero() && (next.IsZero() || job.status.NextRun.Before(next)) {
+				next = job.status.NextRun
+			}
+		}
+		s.mu.Unlock()
+
+		if next.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			s.runDue(ctx, now)
+		}
+	}
+}
+
+// wait 等待正在执行的任务结束，超时后放弃等待
+func (s *Scheduler) wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		utils.DebugLog(0, "[Daemon] 等待任务结束超时 (%v)，不再等待仍在运行的任务", timeout)
+	}
+}
+
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.status.NextRun.IsZero() || job.status.NextRun.After(now) {
+			continue
+		}
+		job.status.NextRun = job.schedule.Next(now)
+		if job.status.Running {
+			utils.DebugLog(1, "[Daemon] 任务 %s 仍在运行，跳过本次执行", job.status.Name)
+			continue
+		}
+
+		job.status.Running = true
+		s.wg.Add(1)
+		go s.execute(ctx, job)
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, job *scheduledJob) {
+	defer s.wg.Done()
+
+	start := time.Now()
+	err := job.run(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.status.Running = false
+	job.status.LastRun = start
+	job.status.LastDuration = time.Since(start).Milliseconds()
+	job.status.LastError = ""
+	if err != nil {
+		job.status.LastError = err.Error()
+		utils.DebugLog(0, "[Daemon] 任务 %s 执行失败: %v", job.status.Name, err)
+	} else {
+		utils.DebugLog(1, "[Daemon] 任务 %s 执行完成，耗时 %v", job.status.Name, time.Since(start))
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestCronNext(t *testing.T) {
+	kolkata := mustLocation(t, "Asia/Kolkata")
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			// UTC+5:30，按绝对时间取整会落在 xx:30，永远匹配不到 9:00
+			name:  "half-hour offset",
+			spec:  "0 9 * * 1",
+			after: time.Date(2026, 3, 4, 10, 0, 0, 0, kolkata),
+			want:  time.Date(2026, 3, 9, 9, 0, 0, 0, kolkata),
+		},
+		{
+			name:  "half-hour offset hourly",
+			spec:  "0 * * * *",
+			after: time.Date(2026, 3, 4, 10, 15, 0, 0, kolkata),
+			want:  time.Date(2026, 3, 4, 11, 0, 0, 0, kolkata),
+		},
+		{
+			// 2026-03-08 02:00 EST 直接跳到 03:00 EDT
+			name:  "dst start hourly",
+			spec:  "0 * * * *",
+			after: time.Date(2026, 3, 8, 1, 30, 0, 0, newYork),
+			want:  time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			name:  "dst start daily",
+			spec:  "0 3 * * *",
+			after: time.Date(2026, 3, 7, 12, 0, 0, 0, newYork),
+			want:  time.Date(2026, 3, 8, 3, 0, 0, 0, newYork),
+		},
+		{
+			// 不存在的 02:30 当天跳过
+			name:  "dst start skipped time",
+			spec:  "30 2 * * *",
+			after: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			want:  time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			// 2026-11-01 01:00 出现两次，每小时任务按绝对时间每小时执行一次
+			name:  "dst end hourly",
+			spec:  "0 * * * *",
+			after: time.Date(2026, 11, 1, 1, 0, 0, 0, newYork),
+			want:  time.Date(2026, 11, 1, 1, 0, 0, 0, newYork).Add(time.Hour),
+		},
+		{
+			name:  "dst end daily",
+			spec:  "0 9 * * *",
+			after: time.Date(2026, 10, 31, 12, 0, 0, 0, newYork),
+			want:  time.Date(2026, 11, 1, 9, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v，应为 %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerWaitTimeout(t *testing.T) {
+	s := NewScheduler()
+	block := make(chan struct{})
+	defer close(block)
+
+	// 模拟不响应取消的任务
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-block
+	}()
+
+	start := time.Now()
+	s.wait(50 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("wait 应在超时后返回，实际等待了 %v", elapsed)
+	}
+}
+
+func TestSchedulerRunStopsOnCancel(t *testing.T) {
+	s := NewScheduler()
+	if err := s.Add("noop", "@every 1h", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ctx 取消后 Run 应立即返回")
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// SearchMatch 节点搜索的匹配结果，记录得分最高的字段
+type SearchMatch struct {
+	Field     string // 匹配字段名称的消息键：名称、ID、赞助商或用户
+	Text      string // 该字段的内容
+	Positions []int  // 匹配到的字符下标
+	Score     int
+}
+
+// Decorate 标出名称中匹配的字符；匹配的是其他字段时在名称后附上该字段
+func (m SearchMatch) Decorate(name string) string {
+	if m.Field == searchFieldName {
+		return utils.HighlightRunes(name, m.Positions, utils.RoleHighlight)
+	}
+	if m.Field == "" {
+		return name
+	}
+	return name + utils.ColorText(utils.RoleMuted, "  "+utils.T(m.Field)+": ") + utils.HighlightRunes(m.Text, m.Positions, utils.RoleHighlight)
+}
+
+// 搜索字段名称的消息键
+const (
+	searchFieldName    = "common.name"
+	searchFieldID      = "search.field_id"
+	searchFieldSponsor = "common.sponsor"
+	searchFieldUser    = "search.field_user"
+)
+
+// matchFields 在多个字段中模糊匹配，返回得分最高的一个；同分时取靠前的字段
+func matchFields(query string, fields [][2]string) (SearchMatch, bool) {
+	var best SearchMatch
+	found := false
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		score, positions, ok := utils.FuzzyMatch(query, f[1])
+		if ok && (!found || score > best.Score) {
+			best = SearchMatch{Field: f[0], Text: f[1], Positions: positions, Score: score}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// MatchRank 按名称、ID、赞助商和用户搜索排行榜条目
+func MatchRank(query string, rank NodeMetricRank) (SearchMatch, bool) {
+	user := ""
+	if rank.User != nil {
+		user = rank.User.Name
+	}
+	return matchFields(query, [][2]string{{searchFieldName, rank.Name}, {searchFieldID, rank.ID}, {searchFieldSponsor, rank.Sponsor.Name}, {searchFieldUser, user}})
+}
+
+// MatchNode 按名称、ID、赞助商和用户搜索自己的节点
+func MatchNode(query string, node models.Node) (SearchMatch, bool) {
+	return matchFields(query, [][2]string{{searchFieldName, node.Name}, {searchFieldID, node.ID}, {searchFieldSponsor, node.Sponsor.Name}, {searchFieldUser, node.User}})
+}
+
+// SearchResult 搜索结果中的一项，Index 为在原列表中的下标
+type SearchResult struct {
+	Index int
+	Match SearchMatch
+}
+
+// FilterRanks 返回匹配的排行榜条目，保持排名顺序；query 为空时返回全部
+func FilterRanks(query string, ranks []NodeMetricRank) []SearchResult {
+	results := make([]SearchResult, 0, len(ranks))
+	for i, rank := range ranks {
+		if query == "" {
+			results = append(results, SearchResult{Index: i})
+		} else if m, ok := MatchRank(query, rank); ok {
+			results = append(results, SearchResult{Index: i, Match: m})
+		}
+	}
+	return results
+}
+
+// FilterNodes 返回匹配的节点，按相关度排序；query 为空时按原顺序返回全部
+func FilterNodes(query string, nodes []models.Node) []SearchResult {
+	results := make([]SearchResult, 0, len(nodes))
+	for i, node := range nodes {
+		if query == "" {
+			results = append(results, SearchResult{Index: i})
+		} else if m, ok := MatchNode(query, node); ok {
+			results = append(results, SearchResult{Index: i, Match: m})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Match.Score > results[j].Match.Score
+	})
+	return results
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func TestFilterNodesOrdersByScore(t *testing.T) {
+	nodes := []models.Node{
+		{ID: "n1", Name: "shanghai-backup"},
+		{ID: "n2", Name: "hk"},
+		{ID: "n3", Name: "北京电信"},
+		{ID: "hk-0001", Name: "guangzhou"},
+		{ID: "n5", Name: "hk-node"},
+	}
+
+	results := FilterNodes("hk", nodes)
+	var got []string
+	for _, r := range results {
+		got = append(got, nodes[r.Index].ID)
+	}
+	// 完全相同、前缀 (同分时保持原顺序)、分散的子序列
+	if want := "[n2 hk-0001 n5 n1]"; fmt.Sprint(got) != want {
+		t.Fatalf("FilterNodes = %v，应为 %s", got, want)
+	}
+	if m := results[1].Match; m.Field != searchFieldID || fmt.Sprint(m.Positions) != "[0 1]" {
+		t.Errorf("应匹配 ID 字段: %+v", m)
+	}
+
+	if results := FilterNodes("", nodes); len(results) != len(nodes) || results[2].Index != 2 {
+		t.Errorf("空查询应按原顺序返回全部节点: %+v", results)
+	}
+	if results := FilterNodes("电信", nodes); len(results) != 1 || results[0].Index != 2 {
+		t.Errorf("中文查询结果不正确: %+v", results)
+	}
+}
+
+func TestFilterRanksKeepsRankOrder(t *testing.T) {
+	names := []string{"hk-edge", "tokyo", "hk", "nodehk"}
+	ranks := make([]NodeMetricRank, len(names))
+	for i, name := range names {
+		ranks[i].ID = fmt.Sprintf("r%d", i)
+		ranks[i].Name = name
+	}
+	ranks[1].Sponsor.Name = "HKBN"
+
+	var got []int
+	for _, r := range FilterRanks("hk", ranks) {
+		got = append(got, r.Index)
+	}
+	if fmt.Sprint(got) != "[0 1 2 3]" {
+		t.Fatalf("排行榜搜索应保持排名顺序，实际为 %v", got)
+	}
+
+	m, ok := MatchRank("hkbn", ranks[1])
+	if !ok || m.Field != searchFieldSponsor {
+		t.Fatalf("应匹配赞助商字段: %+v", m)
+	}
+	defer utils.SetColorMode(utils.ColorAuto)
+	if err := utils.SetColorMode(utils.ColorNever); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Decorate("tokyo"); got != "tokyo  "+utils.T(searchFieldSponsor)+": [HKBN]" {
+		t.Errorf("Decorate = %q", got)
+	}
+}
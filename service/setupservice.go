@@ -0,0 +1,581 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// setupSteps 向导的步骤数，用于显示进度
+const setupSteps = 6
+
+// notifyTypes 向导中可以添加的通知渠道类型
+var notifyTypes = []string{"telegram", "dingtalk", "feishu", "wecom", "discord", "slack", "webhook", "email"}
+
+// SetupService 首次运行向导：检查配置文件和登录凭据，引导完成登录和基本设置后写入配置文件
+type SetupService struct {
+	config   *models.Config
+	loggedIn string // 验证通过的账号名称，未登录时为空
+}
+
+func NewSetup() *SetupService {
+	return &SetupService{}
+}
+
+// Missing 返回配置文件和登录凭据 (cookie.json) 是否缺失；凭据文件为空、没有任何 cookie 或无法解析时视为缺失。
+// 文件存在但无法读取时返回错误
+func (s *SetupService) Missing() (config, credentials bool, err error) {
+	if _, err := os.Stat(ConfigFile); err != nil {
+		if !os.IsNotExist(err) {
+			return false, false, fmt.Errorf(utils.T("config.read_failed"), err)
+		}
+		config = true
+	}
+
+	data, err := ioutil.ReadFile("cookie.json")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return config, false, fmt.Errorf(utils.T("common.read_cookie_failed"), err)
+		}
+		return config, true, nil
+	}
+	var cookies []models.Cookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return config, true, nil
+	}
+	for _, cookie := range cookies {
+		if cookie.Value != "" {
+			return config, false, nil
+		}
+	}
+	return config, true, nil
+}
+
+// Run 依次完成语言、登录、主题、Web 端口、数据目录和告警渠道的设置，确认后写入配置文件。
+// 已有配置文件时以其中的值作为默认值，直接回车保留
+func (s *SetupService) Run() error {
+	configMissing, credentialsMissing, err := s.Missing()
+	if err != nil {
+		return err
+	}
+	cfg, err := NewConfig().Load()
+	if err != nil {
+		return err
+	}
+	s.config = cfg
+
+	fmt.Println(utils.ColorText(utils.RoleTitle, utils.T("setup.title")))
+	fmt.Println(strings.Repeat("─", 50))
+	if configMissing {
+		fmt.Println(utils.T("setup.intro_first"))
+		start, err := s.confirm(utils.T("setup.start"), true)
+		if err != nil {
+			return err
+		}
+		if !start {
+			// 写入默认配置，之后启动时不再进入向导
+			if err := NewConfig().Save(cfg); err != nil {
+				return err
+			}
+			fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("setup.skipped", ConfigFile)))
+			return nil
+		}
+	} else {
+		fmt.Println(utils.T("setup.intro_rerun", ConfigFile))
+	}
+
+	steps := []func() error{
+		s.chooseLanguage,
+		func() error { return s.login(credentialsMissing) },
+		s.chooseTheme,
+		s.choosePort,
+		s.chooseDataDir,
+		s.addChannels,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return s.save()
+}
+
+// step 输出步骤标题
+func (s *SetupService) step(n int, title string) {
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleHeader, utils.T("setup.step", n, setupSteps, title)))
+}
+
+// ask 读取一行输入，直接回车时返回默认值
+func (s *SetupService) ask(label, def string) (string, error) {
+	prompt := label + ": "
+	if def != "" {
+		prompt = fmt.Sprintf("%s [%s]: ", label, def)
+	}
+	line, err := utils.ReadLine(utils.ColorText(utils.RolePrompt, prompt))
+	if err != nil {
+		return "", errors.New(utils.T("setup.input_closed"))
+	}
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// askRequired 读取不能为空的输入
+func (s *SetupService) askRequired(label, def string) (string, error) {
+	for {
+		value, err := s.ask(label, def)
+		if err != nil || value != "" {
+			return value, err
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.required")))
+	}
+}
+
+// confirm 询问是或否，直接回车时返回默认值
+func (s *SetupService) confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		line, err := utils.ReadLine(utils.ColorText(utils.RolePrompt, fmt.Sprintf("%s [%s]: ", label, hint)))
+		if err != nil {
+			return false, errors.New(utils.T("setup.input_closed"))
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.answer_yes_no")))
+	}
+}
+
+// choose 列出选项并读取序号或选项值，直接回车时返回默认项
+func (s *SetupService) choose(labels, values []string, def int) (int, error) {
+	for i, label := range labels {
+		marker := "  "
+		if i == def {
+			marker = utils.ColorText(utils.RoleOK, "➜ ")
+		}
+		fmt.Printf("%s%d. %s\n", marker, i+1, label)
+	}
+	for {
+		line, err := s.ask(utils.T("setup.choose", len(labels)), strconv.Itoa(def+1))
+		if err != nil {
+			return 0, err
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(labels) {
+			return n - 1, nil
+		}
+		for i, value := range values {
+			if strings.EqualFold(line, value) {
+				return i, nil
+			}
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.invalid_choice", line)))
+	}
+}
+
+func (s *SetupService) chooseLanguage() error {
+	s.step(1, utils.T("setup.step_language"))
+	tags := utils.Locales()
+	labels := make([]string, len(tags))
+	def := 0
+	for i, tag := range tags {
+		labels[i] = fmt.Sprintf("%s (%s)", utils.LocaleName(tag), tag)
+		// 当前语言已按 --lang、配置文件和环境变量选出
+		if tag == utils.Locale() {
+			def = i
+		}
+	}
+	i, err := s.choose(labels, tags, def)
+	if err != nil {
+		return err
+	}
+	s.config.UI.Language = tags[i]
+	// 后续步骤立即使用所选语言
+	return utils.SetLocale(tags[i])
+}
+
+// Login 只进行登录这一步，用于配置文件已存在但登录凭据缺失时，跳过后不会进入完整向导
+func (s *SetupService) Login() error {
+	fmt.Println(utils.ColorText(utils.RoleTitle, utils.T("setup.title")))
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleHeader, utils.T("setup.step_login")))
+	return s.authenticate(true)
+}
+
+// login 向导中的登录步骤
+func (s *SetupService) login(credentialsMissing bool) error {
+	s.step(2, utils.T("setup.step_login"))
+	return s.authenticate(credentialsMissing)
+}
+
+// authenticate 登录并验证会话。已有凭据时先验证，有效则可以直接保留
+func (s *SetupService) authenticate(credentialsMissing bool) error {
+	if !credentialsMissing {
+		fmt.Println(utils.T("setup.checking_login"))
+		if s.validate() {
+			relogin, err := s.confirm(utils.T("setup.relogin"), false)
+			if err != nil || !relogin {
+				return err
+			}
+		}
+	} else {
+		fmt.Println(utils.T("setup.login_intro"))
+	}
+
+	auth := NewAuth()
+	labels := []string{utils.T("setup.login_browser"), utils.T("setup.login_cookie"), utils.T("setup.login_skip")}
+	for {
+		method, err := s.choose(labels, []string{"browser", "cookie", "skip"}, 0)
+		if err != nil {
+			return err
+		}
+
+		switch method {
+		case 0:
+			// 无法打开浏览器时 OpenBrowser 会输出授权链接
+			auth.OpenBrowser(GithubAuthorizeURL)
+			callbackURL, err := s.askRequired(utils.T("setup.paste_callback"), "")
+			if err != nil {
+				return err
+			}
+			if auth.ExtractCode(callbackURL) == "" {
+				err = errors.New(utils.T("setup.no_auth_code"))
+			} else {
+				err = auth.VerifyCallback(callbackURL)
+			}
+			if err != nil {
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.login_failed", err)))
+				continue
+			}
+		case 1:
+			fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("main.cookie_hint")))
+			cookie, err := s.askRequired(utils.T("setup.paste_cookie"), "")
+			if err != nil {
+				return err
+			}
+			if err := auth.SaveBrowserCookies(cookie); err != nil {
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.login_failed", err)))
+				continue
+			}
+		default:
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.login_skipped")))
+			return nil
+		}
+
+		if path, err := filepath.Abs("cookie.json"); err == nil {
+			fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("setup.cookie_location", path)))
+		}
+		if s.validate() {
+			return nil
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.login_retry")))
+	}
+}
+
+// validate 调用 GetUserProfile 和 GetNodeList 确认登录有效
+func (s *SetupService) validate() bool {
+	profile, err := NewAuth().GetUserProfile()
+	if err != nil {
+		fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.profile_failed", err)))
+		return false
+	}
+	nodes, err := NewNode().GetNodeList()
+	if err != nil {
+		fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.nodes_failed", err)))
+		return false
+	}
+	s.loggedIn = profile.Name
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("setup.logged_in", profile.Name, profile.Username)))
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.TN("setup.nodes_found", len(nodes))))
+	return true
+}
+
+// chooseTheme 用每个主题显示一行示例，选择后立即生效
+func (s *SetupService) chooseTheme() error {
+	s.step(3, utils.T("setup.step_theme"))
+	ui := &s.config.UI
+	names := utils.ThemeNames(ui.Themes)
+	current := ui.Theme
+	if current == "" {
+		current = utils.DefaultTheme
+	}
+
+	labels := make([]string, len(names))
+	def := 0
+	for i, name := range names {
+		if name == current {
+			def = i
+		}
+		if err := utils.SetTheme(name, ui.Themes); err != nil {
+			labels[i] = name
+			continue
+		}
+		labels[i] = fmt.Sprintf("%-16s %s %s %s %s", name,
+			utils.ColorText(utils.RoleOK, "✓ "+utils.T("setup.preview_ok")),
+			utils.ColorText(utils.RoleWarn, "⚠ "+utils.T("setup.preview_warn")),
+			utils.ColorText(utils.RoleError, "❌ "+utils.T("setup.preview_error")),
+			utils.ColorText(utils.RoleValue, "123.45 GB"))
+	}
+	utils.SetTheme(current, ui.Themes)
+	if !utils.ColorEnabled() {
+		fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("setup.colors_disabled")))
+	}
+
+	i, err := s.choose(labels, names, def)
+	if err != nil {
+		return err
+	}
+	ui.Theme = names[i]
+	return utils.SetTheme(ui.Theme, ui.Themes)
+}
+
+func (s *SetupService) choosePort() error {
+	s.step(4, utils.T("setup.step_port"))
+	fmt.Println(utils.T("setup.port_intro"))
+	for {
+		value, err := s.ask(utils.T("setup.port"), strconv.Itoa(s.config.Daemon.WebPort))
+		if err != nil {
+			return err
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 0 || port > 65535 {
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.invalid_port", value)))
+			continue
+		}
+		s.config.Daemon.WebPort = port
+		if port == 0 {
+			return nil
+		}
+		// 端口被占用时只提示，打开面板时会自动换用其他端口
+		ln, err := net.Listen("tcp", net.JoinHostPort(s.config.Web.Listen, value))
+		if err != nil {
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.port_busy", port)))
+			return nil
+		}
+		ln.Close()
+		return nil
+	}
+}
+
+func (s *SetupService) chooseDataDir() error {
+	s.step(5, utils.T("setup.step_data_dir"))
+	fmt.Println(utils.T("setup.data_dir_intro"))
+	for {
+		dir, err := s.askRequired(utils.T("setup.data_dir"), s.config.DataDir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.data_dir_failed", err)))
+			continue
+		}
+		s.config.DataDir = dir
+		if path, err := filepath.Abs(dir); err == nil {
+			fmt.Println(utils.ColorText(utils.RoleInfo, utils.T("setup.data_dir_location", path)))
+		}
+		return nil
+	}
+}
+
+// addChannels 可选地添加告警通知渠道，每个渠道可以立即发送测试消息
+func (s *SetupService) addChannels() error {
+	s.step(6, utils.T("setup.step_alerts"))
+	notify := &s.config.Notify
+	if n := len(notify.Channels); n > 0 {
+		fmt.Println(utils.TN("setup.channels_existing", n))
+	} else {
+		fmt.Println(utils.T("setup.alerts_intro"))
+	}
+
+	add, err := s.confirm(utils.T("setup.add_channel"), false)
+	for err == nil && add {
+		var ch *models.NotifyChannel
+		if ch, err = s.askChannel(); err != nil {
+			break
+		}
+		notify.Channels = append(notify.Channels, *ch)
+
+		var test bool
+		if test, err = s.confirm(utils.T("setup.test_channel"), true); err != nil {
+			break
+		}
+		if test {
+			alert := models.Alert{
+				Rule:     "test-notify",
+				Severity: models.SeverityInfo,
+				Title:    utils.T("cmd.notify.test_title"),
+				Message:  utils.T("cmd.notify.test_message"),
+				Time:     time.Now(),
+			}
+			// 测试消息只发送一次，不按配置重试
+			if err := NewNotify(models.NotifyConfig{}).SendTo(*ch, alert); err != nil {
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("setup.test_failed", err)))
+			} else {
+				fmt.Println(utils.ColorText(utils.RoleOK, utils.T("setup.test_ok")))
+			}
+		}
+		add, err = s.confirm(utils.T("setup.add_another"), false)
+	}
+	return err
+}
+
+// askChannel 按渠道类型询问需要的字段
+func (s *SetupService) askChannel() (*models.NotifyChannel, error) {
+	i, err := s.choose(notifyTypes, notifyTypes, 0)
+	if err != nil {
+		return nil, err
+	}
+	ch := &models.NotifyChannel{Type: notifyTypes[i]}
+
+	name := ch.Type
+	for n := 2; s.channelExists(name); n++ {
+		name = fmt.Sprintf("%s-%d", ch.Type, n)
+	}
+	for {
+		if ch.Name, err = s.askRequired(utils.T("setup.channel_name"), name); err != nil {
+			return nil, err
+		}
+		if !s.channelExists(ch.Name) {
+			break
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.channel_exists", ch.Name)))
+	}
+
+	switch ch.Type {
+	case "telegram":
+		if ch.Token, err = s.askRequired(utils.T("setup.telegram_token"), ""); err != nil {
+			return nil, err
+		}
+		ch.ChatID, err = s.askRequired(utils.T("setup.telegram_chat"), "")
+	case "email":
+		err = s.askEmail(ch)
+	default:
+		if ch.URL, err = s.askRequired(utils.T("setup.webhook_url"), ""); err != nil {
+			return nil, err
+		}
+		if ch.Type == "dingtalk" || ch.Type == "feishu" {
+			ch.Secret, err = s.ask(utils.T("setup.webhook_secret"), "")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *SetupService) askEmail(ch *models.NotifyChannel) error {
+	var err error
+	if ch.Host, err = s.askRequired(utils.T("setup.smtp_host"), ""); err != nil {
+		return err
+	}
+	if ch.TLS, err = s.confirm(utils.T("setup.smtp_tls"), true); err != nil {
+		return err
+	}
+	def := "25"
+	if ch.TLS {
+		def = "465"
+	}
+	for {
+		value, err := s.ask(utils.T("setup.smtp_port"), def)
+		if err != nil {
+			return err
+		}
+		if ch.Port, err = strconv.Atoi(value); err == nil && ch.Port > 0 && ch.Port <= 65535 {
+			break
+		}
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.invalid_port", value)))
+	}
+	if ch.Username, err = s.ask(utils.T("setup.smtp_username"), ""); err != nil {
+		return err
+	}
+	if ch.Username != "" {
+		if ch.Password, err = utils.ReadPassword(utils.ColorText(utils.RolePrompt, utils.T("setup.smtp_password"))); err != nil {
+			return err
+		}
+	}
+	if ch.From, err = s.askRequired(utils.T("setup.smtp_from"), ch.Username); err != nil {
+		return err
+	}
+	to, err := s.askRequired(utils.T("setup.smtp_to"), "")
+	if err != nil {
+		return err
+	}
+	for _, addr := range strings.Split(to, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			ch.To = append(ch.To, addr)
+		}
+	}
+	return nil
+}
+
+func (s *SetupService) channelExists(name string) bool {
+	for _, ch := range s.config.Notify.Channels {
+		if ch.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// save 显示设置摘要，确认后写入配置文件
+func (s *SetupService) save() error {
+	cfg := s.config
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleHeader, utils.T("setup.summary")))
+
+	account := utils.ColorText(utils.RoleWarn, utils.T("setup.not_logged_in"))
+	if s.loggedIn != "" {
+		account = s.loggedIn
+	}
+	port := strconv.Itoa(cfg.Daemon.WebPort)
+	if cfg.Daemon.WebPort == 0 {
+		port = utils.T("setup.port_disabled")
+	}
+	table := utils.NewTable(utils.TableColumn{Title: utils.T("setup.col_item")}, utils.TableColumn{Title: utils.T("setup.col_value"), Wrap: true})
+	table.AddRow(utils.T("setup.summary_language"), fmt.Sprintf("%s (%s)", utils.LocaleName(cfg.UI.Language), cfg.UI.Language))
+	table.AddRow(utils.T("setup.summary_account"), account)
+	table.AddRow(utils.T("setup.summary_theme"), cfg.UI.Theme)
+	table.AddRow(utils.T("setup.summary_port"), port)
+	table.AddRow(utils.T("setup.summary_data_dir"), cfg.DataDir)
+	table.AddRow(utils.T("setup.summary_channels"), utils.FormatNumber(int64(len(cfg.Notify.Channels))))
+	if err := table.Fprint(os.Stdout); err != nil {
+		return err
+	}
+
+	ok, err := s.confirm(utils.T("setup.confirm_save", ConfigFile), true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("setup.not_saved")))
+		return nil
+	}
+	if err := NewConfig().Save(cfg); err != nil {
+		return err
+	}
+
+	path, err := filepath.Abs(ConfigFile)
+	if err != nil {
+		path = ConfigFile
+	}
+	fmt.Println(utils.ColorText(utils.RoleOK, utils.T("setup.saved", path)))
+	fmt.Println(utils.T("setup.next_steps"))
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupTempDir 切换到临时目录，配置文件和 cookie.json 都在其中
+func setupTempDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	oldConfig := ConfigFile
+	ConfigFile = filepath.Join(dir, "config.json")
+	t.Cleanup(func() {
+		ConfigFile = oldConfig
+		os.Chdir(wd)
+	})
+}
+
+func TestSetupMissing(t *testing.T) {
+	setupTempDir(t)
+	if err := ioutil.WriteFile(ConfigFile, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		cookie      string // 为空时不创建 cookie.json
+		credentials bool
+	}{
+		{"no file", "", true},
+		{"empty file", " ", true},
+		{"empty list", "[]", true},
+		{"empty values", `[{"name": "connect.sid", "value": ""}]`, true},
+		{"invalid json", "{", true},
+		{"logged in", `[{"name": "connect.sid", "value": "s%3Aabc"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove("cookie.json")
+			if tt.cookie != "" {
+				if err := ioutil.WriteFile("cookie.json", []byte(tt.cookie), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			config, credentials, err := NewSetup().Missing()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config {
+				t.Error("配置文件存在时不应视为缺失")
+			}
+			if credentials != tt.credentials {
+				t.Errorf("credentials = %v，应为 %v", credentials, tt.credentials)
+			}
+		})
+	}
+}
+
+func TestSetupMissingReadError(t *testing.T) {
+	setupTempDir(t)
+
+	// cookie.json 是目录时无法读取，应返回错误而不是当作已登录
+	if err := os.Mkdir("cookie.json", 0755); err != nil {
+		t.Fatal(err)
+	}
+	config, _, err := NewSetup().Missing()
+	if err == nil {
+		t.Fatal("凭据文件无法读取时应返回错误")
+	}
+	if !config {
+		t.Error("没有配置文件时应视为缺失")
+	}
+}
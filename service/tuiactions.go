@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// tuiHistoryDays 节点详情中显示的每日流量天数
+const tuiHistoryDays = 30
+
+// tuiActionResult 节点操作完成后交给界面循环的结果
+type tuiActionResult struct {
+	id      string
+	message string
+	node    *models.Node // 操作后重新获取的节点，获取失败时为 nil
+	secret  string       // 重置后的新密钥
+}
+
+// openDetail 打开节点详情并读取该节点的历史快照
+func (s *TUIService) openDetail(id string) {
+	s.detailID = id
+	s.loadHistory()
+	s.switchPane(paneDetail)
+}
+
+// loadHistory 从排行榜快照中读取详情节点最近的每日流量
+func (s *TUIService) loadHistory() {
+	s.history = nil
+	if s.detailID == "" {
+		return
+	}
+	cfg, err := NewConfig().Load()
+	if err != nil {
+		return
+	}
+	history, err := NewRankHistory(cfg.DataDir).NodeHistory(s.detailID, tuiHistoryDays)
+	if err != nil {
+		utils.DebugLog(1, "[Rank] %v", err)
+		return
+	}
+	s.history = history
+}
+
+// detailAction 处理详情面板中的单键操作
+func (s *TUIService) detailAction(key rune) {
+	if s.pane != paneDetail {
+		return
+	}
+	found := s.findNode(s.detailID)
+	if found == nil {
+		s.setStatus(utils.T("tui.select_node_first"), true)
+		return
+	}
+	node := *found // 刷新会替换节点列表，这里保留一份副本
+
+	switch key {
+	case 'e':
+		s.editNode(node)
+	case 's':
+		s.editSponsor(node)
+	case 'R':
+		s.resetSecret(node)
+	case 'c':
+		s.copyEndpoint(node)
+	case 'u':
+		s.openSponsor(node)
+	}
+}
+
+// editNode 依次输入节点名称和带宽，输入框中预填当前值
+func (s *TUIService) editNode(node models.Node) {
+	s.prompt = &tuiPrompt{
+		label: utils.T("tui.prompt_name"),
+		input: []rune(node.Name),
+		submit: func(name string) {
+			if name == "" {
+				name = node.Name
+			}
+			s.prompt = &tuiPrompt{
+				label: utils.T("tui.prompt_bandwidth"),
+				input: []rune(strconv.Itoa(node.Bandwidth)),
+				submit: func(text string) {
+					bandwidth := node.Bandwidth
+					if text != "" {
+						bw, err := strconv.Atoi(text)
+						if err != nil || bw <= 0 {
+							s.setStatus(utils.T("tui.bandwidth_invalid"), true)
+							return
+						}
+						bandwidth = bw
+					}
+					info := NodeUpdateInfo{Name: name, Bandwidth: bandwidth}
+					s.runAction(node.ID, utils.T("tui.node_updated"), func(n *NodeService) (string, error) {
+						return "", n.UpdateNode(node.ID, info)
+					})
+				},
+			}
+		},
+	}
+}
+
+// editSponsor 依次输入赞助商名称、网址和图片
+func (s *TUIService) editSponsor(node models.Node) {
+	sponsor := node.Sponsor
+	s.prompt = &tuiPrompt{
+		label: utils.T("tui.prompt_sponsor_name"),
+		input: []rune(sponsor.Name),
+		submit: func(text string) {
+			sponsor.Name = text
+			s.prompt = &tuiPrompt{
+				label: utils.T("tui.prompt_sponsor_url"),
+				input: []rune(sponsor.URL),
+				submit: func(text string) {
+					sponsor.URL = text
+					s.prompt = &tuiPrompt{
+						label: utils.T("tui.prompt_sponsor_banner"),
+						input: []rune(sponsor.Banner),
+						submit: func(text string) {
+							sponsor.Banner = text
+							s.runAction(node.ID, utils.T("tui.sponsor_submitted"), func(n *NodeService) (string, error) {
+								return "", n.UpdateNodeSponsor(node.ID, sponsor)
+							})
+						},
+					}
+				},
+			}
+		},
+	}
+}
+
+// resetSecret 输入 RESET 确认后重置节点密钥
+func (s *TUIService) resetSecret(node models.Node) {
+	s.setStatus(utils.T("tui.reset_warning", node.Name), true)
+	s.prompt = &tuiPrompt{
+		label: utils.T("tui.reset_prompt"),
+		submit: func(text string) {
+			if text != "RESET" {
+				s.setStatus(utils.T("node.reset_cancelled"), false)
+				return
+			}
+			s.runAction(node.ID, utils.T("tui.reset_ok"), func(n *NodeService) (string, error) {
+				return n.ResetNodeSecret(node.ID)
+			})
+		},
+	}
+}
+
+// runAction 在后台执行节点操作，成功后重新获取节点详情
+func (s *TUIService) runAction(id, message string, action func(*NodeService) (string, error)) {
+	s.setStatus(utils.T("tui.submitting"), false)
+	go func() {
+		nodeService := NewNode()
+		secret, err := action(nodeService)
+		if err != nil {
+			s.results <- tuiResult{kind: tuiAction, err: err}
+			return
+		}
+		node, err := nodeService.GetNodeDetail(id)
+		if err != nil {
+			utils.DebugLog(1, "[TUI] 刷新节点 %s 失败: %v", id, err)
+			node = nil
+		}
+		s.results <- tuiResult{kind: tuiAction, data: tuiActionResult{id: id, message: message, node: node, secret: secret}}
+	}()
+}
+
+// applyAction 更新节点列表中被修改的节点
+func (s *TUIService) applyAction(res tuiResult) {
+	if res.err != nil {
+		s.setStatus(utils.T("tui.action_failed", res.err), true)
+		return
+	}
+	result := res.data.(tuiActionResult)
+	if result.node != nil {
+		if node := s.findNode(result.id); node != nil {
+			*node = *result.node
+		}
+	}
+	if result.secret != "" {
+		s.secrets[result.id] = result.secret
+	}
+	s.setStatus(result.message, false)
+}
+
+// copyEndpoint 复制节点地址，没有剪贴板工具时通过终端复制
+func (s *TUIService) copyEndpoint(node models.Node) {
+	if node.Endpoint.Host == "" {
+		s.setStatus(utils.T("tui.no_endpoint"), true)
+		return
+	}
+	endpoint := nodeEndpoint(node)
+	if err := utils.CopyToClipboard(endpoint); err != nil {
+		s.screen.SetClipboard(endpoint)
+		s.setStatus(utils.T("tui.copy_osc52", endpoint, err), false)
+		return
+	}
+	s.setStatus(utils.T("tui.copied")+endpoint, false)
+}
+
+// openSponsor 在浏览器中打开赞助商网站，只允许 http 和 https 链接
+func (s *TUIService) openSponsor(node models.Node) {
+	link, err := url.Parse(node.Sponsor.URL)
+	if node.Sponsor.URL == "" || err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		s.setStatus(utils.T("tui.no_sponsor_url"), true)
+		return
+	}
+	if err := utils.OpenURL(link.String()); err != nil {
+		s.setStatus(utils.T("tui.open_browser_failed", err), true)
+		return
+	}
+	s.setStatus(utils.T("tui.opened")+link.String(), false)
+}
+
+// nodeEndpoint 节点的访问地址
+func nodeEndpoint(node models.Node) string {
+	return fmt.Sprintf("%s://%s:%d", node.Endpoint.Proto, node.Endpoint.Host, node.Endpoint.Port)
+}
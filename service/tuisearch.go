@@ -0,0 +1,135 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// nodeResults 节点列表中当前显示的条目，搜索时只保留匹配的节点
+func (s *TUIService) nodeResults() []SearchResult {
+	return FilterNodes(s.nodeList.query, s.nodes)
+}
+
+// rankResults 排行榜中当前显示的条目，搜索时只保留匹配的节点
+func (s *TUIService) rankResults() []SearchResult {
+	return FilterRanks(s.rankList.query, s.ranks)
+}
+
+// currentList 当前面板的列表和条目数，不是列表面板时返回 nil
+func (s *TUIService) currentList() (*tuiList, int) {
+	switch s.pane {
+	case paneNodes:
+		return &s.nodeList, len(s.nodeResults())
+	case paneRank:
+		return &s.rankList, len(s.rankResults())
+	}
+	return nil, 0
+}
+
+// ownIDs 当前账号的节点 ID
+func (s *TUIService) ownIDs() map[string]bool {
+	own := make(map[string]bool, len(s.nodes))
+	for _, node := range s.nodes {
+		own[node.ID] = true
+	}
+	return own
+}
+
+// search 打开搜索框，输入时即时过滤当前列表；Esc 恢复原来的搜索条件
+func (s *TUIService) search() {
+	list, _ := s.currentList()
+	if list == nil {
+		s.setStatus(utils.T("tui.search_hint"), true)
+		return
+	}
+	previous := list.query
+	apply := func(text string) {
+		list.query = strings.TrimSpace(text)
+		list.cursor, list.offset = 0, 0
+	}
+	s.prompt = &tuiPrompt{
+		label:  utils.T("tui.search_prompt"),
+		input:  []rune(previous),
+		change: apply,
+		cancel: func() { apply(previous) },
+		submit: func(text string) {
+			apply(text)
+			if list.query == "" {
+				s.setStatus(utils.T("tui.search_cleared"), false)
+				return
+			}
+			_, total := s.currentList()
+			s.setStatus(utils.TN("tui.search_found", total, list.query), total == 0)
+		},
+	}
+}
+
+// clearSearch 清除当前列表的搜索条件，返回是否有条件被清除
+func (s *TUIService) clearSearch() bool {
+	list, _ := s.currentList()
+	if list == nil || list.query == "" {
+		return false
+	}
+	list.query = ""
+	list.cursor, list.offset = 0, 0
+	s.setStatus(utils.T("tui.search_cleared"), false)
+	return true
+}
+
+// gotoPage 输入页码后跳到该页的第一行，页的大小与 PgUp/PgDn 一致
+func (s *TUIService) gotoPage() {
+	list, total := s.currentList()
+	if list == nil || total == 0 {
+		return
+	}
+	size := s.pageSize()
+	pages := (total + size - 1) / size
+	s.prompt = &tuiPrompt{
+		label: utils.T("tui.goto_prompt", pages),
+		submit: func(text string) {
+			page, err := strconv.Atoi(text)
+			if err != nil || page < 1 || page > pages {
+				s.setStatus(utils.T("tui.goto_invalid", pages), true)
+				return
+			}
+			list.cursor = (page - 1) * size
+			list.offset = list.cursor
+		},
+	}
+}
+
+// jumpToOwn 在排行榜中跳到光标之后的下一个自己的节点，到末尾后从头开始
+func (s *TUIService) jumpToOwn() {
+	if s.pane != paneRank {
+		s.switchPane(paneRank)
+	}
+	own := s.ownIDs()
+	results := s.rankResults()
+	if len(own) == 0 || len(results) == 0 {
+		s.setStatus(utils.T("tui.not_loaded"), true)
+		return
+	}
+
+	var positions []int
+	for i, r := range results {
+		if own[s.ranks[r.Index].ID] {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) == 0 {
+		s.setStatus(utils.T("tui.no_own_in_rank"), true)
+		return
+	}
+	next := positions[0]
+	for _, p := range positions {
+		if p > s.rankList.cursor {
+			next = p
+			break
+		}
+	}
+	s.rankList.cursor = next
+	rank := s.ranks[results[next].Index]
+	s.setStatus(utils.T("tui.own_rank", rank.Name, results[next].Index+1, len(positions)), false)
+}
@@ -0,0 +1,490 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 全屏界面的面板
+const (
+	paneDashboard = iota
+	paneNodes
+	paneDetail
+	paneRank
+)
+
+// paneTitles 面板标题的消息键
+var paneTitles = []string{"tui.pane_dashboard", "tui.pane_nodes", "tui.pane_detail", "tui.pane_rank"}
+
+// 后台加载的数据种类
+const (
+	tuiProfile   = "profile"
+	tuiDashboard = "dashboard"
+	tuiNodes     = "nodes"
+	tuiRank      = "rank"
+	tuiLogin     = "login"
+	tuiAction    = "action"
+)
+
+const (
+	tuiStatusTTL  = 8 * time.Second // 状态栏消息的显示时长
+	tuiOutputKeep = 200             // 保留的截获输出行数
+)
+
+// tuiResult 后台请求完成后交给界面循环处理的结果
+type tuiResult struct {
+	kind string
+	data interface{}
+	err  error
+}
+
+// tuiPrompt 底部的单行输入框
+type tuiPrompt struct {
+	label  string
+	input  []rune
+	submit func(text string)
+	change func(text string) // 输入内容变化时调用，可以为空
+	cancel func()            // 按 Esc 取消时调用，可以为空
+}
+
+// tuiList 可滚动列表的光标、首行位置和搜索条件，光标是过滤后的下标
+type tuiList struct {
+	cursor int
+	offset int
+	query  string
+}
+
+// move 移动光标并限制在列表范围内
+func (l *tuiList) move(delta, total int) {
+	l.cursor += delta
+	if l.cursor >= total {
+		l.cursor = total - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+}
+
+// window 调整首行使光标可见，返回可见部分的起止下标
+func (l *tuiList) window(height, total int) (int, int) {
+	if height <= 0 || total == 0 {
+		return 0, 0
+	}
+	l.move(0, total)
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if l.cursor >= l.offset+height {
+		l.offset = l.cursor - height + 1
+	}
+	if l.offset > total-height {
+		l.offset = max(total-height, 0)
+	}
+	return l.offset, min(l.offset+height, total)
+}
+
+// TUIService 全屏终端界面，数据来自已有的各个服务
+type TUIService struct {
+	screen  *utils.Screen
+	pane    int
+	back    int    // 从详情返回的面板
+	overlay string // 覆盖显示的帮助或输出记录，空表示不显示
+
+	profile   *models.UserProfile
+	dashboard *models.Dashboard
+	nodes     []models.Node
+	ranks     []NodeMetricRank
+	errs      map[string]error
+	pending   int
+	updatedAt time.Time
+
+	nodeList tuiList
+	rankList tuiList
+	detailID string
+	history  []models.RankHistoryPoint // 详情节点最近的每日排行榜数据
+	secrets  map[string]string         // 本次运行中重置过的节点密钥
+	drawnAt  time.Time
+
+	prompt    *tuiPrompt
+	status    string
+	statusErr bool
+	statusAt  time.Time
+	output    []string
+
+	web     *WebService
+	results chan tuiResult
+}
+
+func NewTUI() *TUIService {
+	return &TUIService{
+		errs:    make(map[string]error),
+		secrets: make(map[string]string),
+		results: make(chan tuiResult, 16),
+	}
+}
+
+// Run 进入全屏界面，按 q 退出；标准输入输出不是终端时返回错误
+func (s *TUIService) Run() error {
+	screen, err := utils.OpenScreen()
+	if err != nil {
+		return err
+	}
+	s.screen = screen
+	defer screen.Close()
+	defer s.stopWeb()
+
+	s.refresh()
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	width, height := screen.Size()
+	s.draw()
+	for {
+		select {
+		case key, ok := <-screen.Keys():
+			if !ok {
+				return nil
+			}
+			if s.handleKey(key) {
+				return nil
+			}
+		case res := <-s.results:
+			s.apply(res)
+		case line := <-screen.Output():
+			s.output = append(s.output, line)
+			if len(s.output) > tuiOutputKeep {
+				s.output = s.output[len(s.output)-tuiOutputKeep:]
+			}
+			s.setStatus(line, false)
+		case <-ticker.C:
+			// 终端大小变化、状态消息过期时重绘，详情面板每秒更新时长
+			w, h := screen.Size()
+			expired := s.status != "" && time.Since(s.statusAt) > tuiStatusTTL
+			live := s.pane == paneDetail && time.Since(s.drawnAt) >= time.Second
+			if w == width && h == height && !expired && !live {
+				continue
+			}
+			width, height = w, h
+			if expired {
+				s.status = ""
+			}
+		}
+		s.draw()
+	}
+}
+
+func (s *TUIService) setStatus(msg string, isErr bool) {
+	s.status = msg
+	s.statusErr = isErr
+	s.statusAt = time.Now()
+}
+
+// refresh 在后台重新加载所有数据，上一轮未完成时忽略
+func (s *TUIService) refresh() {
+	if s.pending > 0 {
+		return
+	}
+	s.pending = 4
+	s.setStatus(utils.T("tui.refreshing"), false)
+
+	go func() {
+		profile, err := NewAuth().GetUserProfile()
+		if err == nil && profile.ID == "" {
+			err = errors.New(utils.T("tui.not_logged_in"))
+		}
+		s.results <- tuiResult{kind: tuiProfile, data: profile, err: err}
+	}()
+	go func() {
+		dashboard, err := NewDashboard().GetDashboard()
+		s.results <- tuiResult{kind: tuiDashboard, data: dashboard, err: err}
+	}()
+	go func() {
+		nodes, err := NewNode().GetNodeList()
+		if err == nil {
+			SaveNodeCache(nodes)
+		}
+		s.results <- tuiResult{kind: tuiNodes, data: nodes, err: err}
+	}()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		ranks, err := NewNode().GetNodeMetricRank(ctx)
+		s.results <- tuiResult{kind: tuiRank, data: ranks, err: err}
+	}()
+}
+
+// apply 在界面循环中处理后台请求的结果
+func (s *TUIService) apply(res tuiResult) {
+	if res.kind == tuiLogin {
+		if res.err != nil {
+			s.setStatus(utils.T("tui.login_failed", res.err), true)
+			return
+		}
+		s.setStatus(utils.T("tui.login_ok"), false)
+		s.refresh()
+		return
+	}
+	if res.kind == tuiAction {
+		s.applyAction(res)
+		return
+	}
+
+	s.pending--
+	s.errs[res.kind] = res.err
+	if res.err == nil {
+		switch data := res.data.(type) {
+		case *models.UserProfile:
+			s.profile = data
+		case *models.Dashboard:
+			s.dashboard = data
+		case []models.Node:
+			s.nodes = data
+		case []NodeMetricRank:
+			s.ranks = data
+		}
+	}
+	if s.pending > 0 {
+		return
+	}
+
+	s.updatedAt = time.Now()
+	var failed []string
+	for _, kind := range []string{tuiProfile, tuiDashboard, tuiNodes, tuiRank} {
+		if err := s.errs[kind]; err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		s.setStatus(strings.Join(failed, utils.T("common.error_sep")), true)
+	} else {
+		s.setStatus(utils.T("tui.refreshed"), false)
+	}
+}
+
+// handleKey 处理一次按键，返回 true 表示退出界面
+func (s *TUIService) handleKey(key utils.Key) bool {
+	if key.Code == utils.KeyCtrlC {
+		return true
+	}
+	if s.prompt != nil {
+		s.handlePromptKey(key)
+		return false
+	}
+	if s.overlay != "" {
+		// 覆盖层打开时任意键关闭
+		s.overlay = ""
+		return false
+	}
+
+	switch key.Code {
+	case utils.KeyTab, utils.KeyRight:
+		s.switchPane((s.pane + 1) % len(paneTitles))
+	case utils.KeyBackTab, utils.KeyLeft:
+		s.switchPane((s.pane + len(paneTitles) - 1) % len(paneTitles))
+	case utils.KeyUp:
+		s.moveCursor(-1)
+	case utils.KeyDown:
+		s.moveCursor(1)
+	case utils.KeyPgUp:
+		s.moveCursor(-s.pageSize())
+	case utils.KeyPgDn:
+		s.moveCursor(s.pageSize())
+	case utils.KeyHome:
+		s.moveCursor(-1 << 30)
+	case utils.KeyEnd:
+		s.moveCursor(1 << 30)
+	case utils.KeyEnter:
+		s.openSelected()
+	case utils.KeyEsc:
+		if s.pane == paneDetail {
+			s.pane = s.back
+		} else {
+			s.clearSearch()
+		}
+	case utils.KeyRune:
+		switch key.Rune {
+		case 'q', 'Q':
+			return true
+		case '1', '2', '3', '4':
+			s.switchPane(int(key.Rune - '1'))
+		case 'k':
+			s.moveCursor(-1)
+		case 'j':
+			s.moveCursor(1)
+		case 'g':
+			s.moveCursor(-1 << 30)
+		case 'G':
+			s.moveCursor(1 << 30)
+		case 'r':
+			s.refresh()
+		case 'l':
+			s.login()
+		case 'w':
+			s.toggleWeb()
+		case 'o':
+			s.overlay = "output"
+		case '?':
+			s.overlay = "help"
+		case '/':
+			s.search()
+		case ':':
+			s.gotoPage()
+		case 'm':
+			s.jumpToOwn()
+		case 'e', 's', 'R', 'c', 'u':
+			s.detailAction(key.Rune)
+		}
+	}
+	return false
+}
+
+func (s *TUIService) switchPane(pane int) {
+	if pane == paneDetail && s.pane != paneDetail {
+		s.back = s.pane
+	}
+	s.pane = pane
+}
+
+// tuiBodyHeight 正文区域的行数：去掉标签栏、消息行和状态栏
+func tuiBodyHeight(height int) int {
+	return max(height-3, 0)
+}
+
+// pageSize 列表一页的行数，与列表实际显示的行数相同：正文区域去掉表头
+func (s *TUIService) pageSize() int {
+	_, height := s.screen.Size()
+	return max(tuiBodyHeight(height)-1, 1)
+}
+
+func (s *TUIService) moveCursor(delta int) {
+	switch s.pane {
+	case paneNodes:
+		s.nodeList.move(delta, len(s.nodeResults()))
+	case paneRank:
+		s.rankList.move(delta, len(s.rankResults()))
+	}
+}
+
+// openSelected 打开光标所在节点的详情；排行榜中只能打开自己的节点
+func (s *TUIService) openSelected() {
+	switch s.pane {
+	case paneNodes:
+		if results := s.nodeResults(); s.nodeList.cursor < len(results) {
+			s.openDetail(s.nodes[results[s.nodeList.cursor].Index].ID)
+		}
+	case paneRank:
+		results := s.rankResults()
+		if s.rankList.cursor >= len(results) {
+			return
+		}
+		rank := s.ranks[results[s.rankList.cursor].Index]
+		if s.findNode(rank.ID) == nil {
+			s.setStatus(utils.T("tui.not_own_node", rank.Name), true)
+			return
+		}
+		s.openDetail(rank.ID)
+	}
+}
+
+func (s *TUIService) findNode(id string) *models.Node {
+	for i := range s.nodes {
+		if s.nodes[i].ID == id {
+			return &s.nodes[i]
+		}
+	}
+	return nil
+}
+
+func (s *TUIService) handlePromptKey(key utils.Key) {
+	p := s.prompt
+	switch key.Code {
+	case utils.KeyEsc:
+		s.prompt = nil
+		if p.cancel != nil {
+			p.cancel()
+		}
+		s.setStatus(utils.T("tui.cancelled"), false)
+	case utils.KeyEnter:
+		s.prompt = nil
+		p.submit(strings.TrimSpace(string(p.input)))
+	case utils.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case utils.KeyRune:
+		p.input = append(p.input, key.Rune)
+	default:
+		return
+	}
+	if s.prompt == p && p.change != nil {
+		p.change(string(p.input))
+	}
+}
+
+// login 打开 GitHub 授权页，然后在输入框中接收回调 URL 或浏览器 Cookie
+func (s *TUIService) login() {
+	if err := NewAuth().OpenBrowser(GithubAuthorizeURL); err != nil {
+		s.setStatus(utils.T("tui.open_manually")+GithubAuthorizeURL, true)
+	} else {
+		s.setStatus(utils.T("tui.login_opened"), false)
+	}
+	s.prompt = &tuiPrompt{
+		label: utils.T("tui.login_prompt"),
+		submit: func(text string) {
+			if text == "" {
+				s.setStatus(utils.T("tui.login_empty"), true)
+				return
+			}
+			s.setStatus(utils.T("tui.verifying"), false)
+			go func() {
+				auth := NewAuth()
+				var err error
+				if auth.ExtractCode(text) != "" {
+					err = auth.VerifyCallback(text)
+				} else {
+					err = auth.SaveBrowserCookies(text)
+				}
+				s.results <- tuiResult{kind: tuiLogin, err: err}
+			}()
+		},
+	}
+}
+
+// toggleWeb 启动或关闭管理面板
+func (s *TUIService) toggleWeb() {
+	if s.web != nil && s.web.Running() {
+		s.stopWeb()
+		s.setStatus(utils.T("common.panel_closed"), false)
+		return
+	}
+
+	cfg, err := NewConfig().Load()
+	if err != nil {
+		s.setStatus(err.Error(), true)
+		return
+	}
+	s.web = NewWeb(cfg.PanelPort(), cfg)
+	s.web.SetPortFallback(true)
+	if err := s.web.StartServer(); err != nil {
+		s.setStatus(utils.T("tui.web_start_failed", err), true)
+		return
+	}
+	s.setStatus(utils.T("tui.panel_started"), false)
+}
+
+// stopWeb 关闭管理面板，最多等待 5 秒
+func (s *TUIService) stopWeb() {
+	if s.web == nil || !s.web.Running() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.web.Shutdown(ctx); err != nil {
+		s.setStatus(utils.T("tui.web_stop_failed", err), true)
+	}
+}
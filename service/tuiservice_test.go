@@ -0,0 +1,23 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func TestPageSizeMatchesVisibleRows(t *testing.T) {
+	s := NewTUI()
+	// 不是终端时 Size 按 80x24 处理
+	s.screen = &utils.Screen{}
+	for i := 0; i < 100; i++ {
+		s.nodes = append(s.nodes, models.Node{ID: fmt.Sprintf("n%d", i), Name: fmt.Sprintf("node-%d", i)})
+	}
+	width, height := s.screen.Size()
+	lines := s.renderNodes(width, tuiBodyHeight(height))
+	if rows := len(lines) - 1; rows != s.pageSize() {
+		t.Fatalf("列表显示 %d 行，pageSize 为 %d", rows, s.pageSize())
+	}
+}
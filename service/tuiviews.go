@@ -0,0 +1,440 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// draw 按当前状态重绘整屏：标签栏、面板内容、消息行和状态栏
+func (s *TUIService) draw() {
+	width, height := s.screen.Size()
+	bodyHeight := tuiBodyHeight(height)
+
+	var body []string
+	switch {
+	case s.overlay == "help":
+		body = s.renderHelp()
+	case s.overlay == "output":
+		body = s.renderOutput(bodyHeight)
+	case s.pane == paneDashboard:
+		body = s.renderDashboard(width, bodyHeight)
+	case s.pane == paneNodes:
+		body = s.renderNodes(width, bodyHeight)
+	case s.pane == paneDetail:
+		body = s.renderDetail()
+	case s.pane == paneRank:
+		body = s.renderRank(width, bodyHeight)
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, s.renderTabs(width))
+	for i := 0; i < bodyHeight; i++ {
+		if i < len(body) {
+			lines = append(lines, body[i])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	lines = append(lines, s.renderMessage(), s.renderStatusBar(width))
+	s.screen.Render(lines)
+	s.drawnAt = time.Now()
+}
+
+func (s *TUIService) renderTabs(width int) string {
+	var b strings.Builder
+	b.WriteString(utils.ColorText(utils.RoleTitle, " OpenBMCLAPI "))
+	for i, title := range paneTitles {
+		tab := fmt.Sprintf("%d %s", i+1, utils.T(title))
+		switch {
+		case i != s.pane:
+			b.WriteString(utils.ColorText(utils.RoleMuted, " "+tab+" "))
+		case utils.Style(utils.RoleSelected) == "":
+			// 颜色关闭时用 [] 标出当前面板，宽度不变
+			b.WriteString("[" + tab + "]")
+		default:
+			b.WriteString(utils.ColorText(utils.RoleSelected, " "+tab+" "))
+		}
+	}
+	left := b.String()
+
+	right := ""
+	if s.profile != nil && s.profile.ID != "" {
+		right = utils.ColorText(utils.RoleOK, "● "+s.profile.Name+" ")
+	} else if s.errs[tuiProfile] != nil {
+		right = utils.ColorText(utils.RoleWarn, utils.T("tui.not_logged_in_badge"))
+	}
+	gap := width - utils.StringWidth(left) - utils.StringWidth(right)
+	if gap < 1 {
+		return left
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+func (s *TUIService) renderMessage() string {
+	if s.status == "" {
+		return ""
+	}
+	if s.statusErr {
+		return utils.ColorText(utils.RoleError, " "+s.status)
+	}
+	return utils.ColorText(utils.RoleWarn, " "+s.status)
+}
+
+func (s *TUIService) renderStatusBar(width int) string {
+	if p := s.prompt; p != nil {
+		return utils.ColorText(utils.RolePrompt, " "+p.label) + string(p.input) + utils.MarkText(utils.RoleSelected, " ", "_", "") +
+			utils.ColorText(utils.RoleMuted, utils.T("tui.prompt_hint"))
+	}
+
+	parts := []string{utils.T(paneTitles[s.pane])}
+	switch s.pane {
+	case paneNodes, paneRank:
+		if list, total := s.currentList(); total > 0 {
+			size := s.pageSize()
+			parts = append(parts, utils.T("tui.position", list.cursor+1, total, list.cursor/size+1, (total+size-1)/size))
+		}
+		if list, _ := s.currentList(); list.query != "" {
+			parts = append(parts, utils.T("tui.search_label")+list.query)
+		}
+	}
+	if s.pending > 0 {
+		parts = append(parts, utils.T("tui.loading_badge"))
+	} else if !s.updatedAt.IsZero() {
+		parts = append(parts, utils.T("common.updated_at")+s.updatedAt.Format("15:04:05"))
+	}
+	if s.web != nil && s.web.Running() {
+		parts = append(parts, utils.T("tui.panel_badge")+s.web.URL())
+	}
+	left := " " + strings.Join(parts, " │ ")
+
+	hints := utils.T("tui.hints")
+	switch s.pane {
+	case paneNodes:
+		hints = utils.T("tui.hints_nodes")
+	case paneRank:
+		hints = utils.T("tui.hints_rank")
+	case paneDetail:
+		hints = utils.T("tui.hints_detail")
+	}
+	gap := width - utils.StringWidth(left) - utils.StringWidth(hints)
+	if gap < 1 {
+		return utils.ColorText(utils.RoleSelected, utils.PadWidth(left, width, true))
+	}
+	return utils.ColorText(utils.RoleSelected, left+strings.Repeat(" ", gap)+hints)
+}
+
+func (s *TUIService) renderHelp() []string {
+	keys := [][2]string{
+		{"1-4 / Tab / ←→", utils.T("tui.help_switch")},
+		{"↑↓ / j k", utils.T("tui.help_move")},
+		{"PgUp PgDn", utils.T("tui.help_page")},
+		{"Home End / g G", utils.T("tui.help_home_end")},
+		{"Enter", utils.T("tui.help_detail")},
+		{"/", utils.T("tui.help_search")},
+		{":", utils.T("tui.help_goto")},
+		{"m", utils.T("tui.help_own")},
+		{"Esc", utils.T("tui.help_esc")},
+		{"e / s", utils.T("tui.help_edit")},
+		{"R", utils.T("tui.help_reset")},
+		{"c", utils.T("tui.help_copy")},
+		{"u", utils.T("tui.help_sponsor")},
+		{"r", utils.T("tui.help_refresh")},
+		{"l", utils.T("tui.help_login")},
+		{"w", utils.T("tui.help_web")},
+		{"o", utils.T("tui.help_output")},
+		{"q / Ctrl+C", utils.T("tui.help_quit")},
+	}
+	lines := []string{"", utils.ColorText(utils.RoleTitle, utils.T("tui.help_title")), ""}
+	for _, k := range keys {
+		lines = append(lines, "   "+utils.ColorText(utils.RoleOK, utils.PadWidth(k[0], 18, true))+k[1])
+	}
+	return append(lines, "", utils.ColorText(utils.RoleMuted, utils.T("tui.help_close")))
+}
+
+// renderOutput 显示最近截获的程序输出，只保留能放下的最后几行
+func (s *TUIService) renderOutput(height int) []string {
+	lines := []string{utils.ColorText(utils.RoleTitle, utils.T("tui.output_title")) + utils.ColorText(utils.RoleMuted, utils.T("tui.output_close"))}
+	if len(s.output) == 0 {
+		return append(lines, "", utils.T("tui.output_empty"))
+	}
+	output := s.output
+	if n := height - 1; len(output) > n {
+		output = output[len(output)-n:]
+	}
+	for _, line := range output {
+		lines = append(lines, "   "+line)
+	}
+	return lines
+}
+
+// renderLoading 数据未加载时的占位内容
+func (s *TUIService) renderLoading(kind string) []string {
+	if err := s.errs[kind]; err != nil {
+		return []string{"", utils.ColorText(utils.RoleError, "   ❌ "+err.Error()), "", utils.T("tui.retry_hint")}
+	}
+	return []string{"", utils.T("common.loading")}
+}
+
+func (s *TUIService) renderDashboard(width, height int) []string {
+	d := s.dashboard
+	if d == nil {
+		return s.renderLoading(tuiDashboard)
+	}
+
+	cell := max((width-3)/3, 20)
+	metric := func(label, value string) string {
+		return utils.PadWidth(utils.ColorText(utils.RoleLabel, label)+"  "+utils.ColorText(utils.RoleValue, value), cell, true)
+	}
+	lines := []string{
+		"",
+		" " + utils.ColorText(utils.RoleTitle, utils.T("common.key_metrics")),
+		"   " + metric(utils.T("common.metric_online_nodes"), utils.TN("common.count_nodes", d.CurrentNodes)) +
+			metric(utils.T("common.metric_bandwidth"), utils.FormatBandwidth(d.CurrentBandwidth)) +
+			metric(utils.T("common.load"), fmt.Sprintf("%.2f%%", d.Load*100)),
+		"   " + metric(utils.T("common.metric_traffic"), utils.FormatBytes(d.Bytes)) +
+			metric(utils.T("common.metric_hits"), utils.TN("common.count_times", d.Hits)) +
+			metric(utils.T("common.bandwidth_limit"), utils.FormatBandwidth(d.Bandwidth)),
+		"",
+		" " + utils.ColorText(utils.RoleTitle, utils.T("tui.hourly_bandwidth")),
+	}
+
+	// 图表占满剩余高度，留出 x 轴和时间标签两行
+	if chartHeight := height - len(lines) - 2; chartHeight >= 3 && len(d.Hourly) > 0 {
+		chart := utils.Chart{
+			Series: []utils.ChartSeries{{Values: hourlyValues(d.Hourly, func(h models.HourlyMetric) float64 { return h.Bandwidth }), Color: utils.RoleValue}},
+			Labels: hourLabels(d.Hourly),
+			Height: chartHeight,
+			Width:  width - 2,
+			Mode:   utils.ChartBar,
+			Format: utils.FormatBandwidth,
+		}
+		for _, line := range chart.Render() {
+			lines = append(lines, " "+line)
+		}
+	}
+	return lines
+}
+
+// renderTable 渲染表头和 [start, end) 范围内的行，selected 行反色显示，颜色关闭时用 > 标出；
+// 列宽按所有行计算，滚动时不会跳动
+func renderTable(width int, columns []utils.TableColumn, rows [][]string, start, end, selected int) []string {
+	table := utils.NewTable(columns...)
+	table.Rows = rows
+	table.Width = width - 1
+	table.HeaderColor = utils.RoleHeader
+	rendered := table.Render()
+
+	lines := []string{" " + rendered[0]}
+	for i := start; i < end; i++ {
+		line := " " + rendered[i+1]
+		if i == selected {
+			if utils.Style(utils.RoleSelected) == "" {
+				line = ">" + rendered[i+1]
+			} else {
+				line = utils.ColorText(utils.RoleSelected, utils.PadWidth(utils.StripANSI(line), width, true))
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// nodeStatusText 节点状态的彩色文字
+func nodeStatusText(isEnabled, isBanned bool) string {
+	switch {
+	case isBanned:
+		return utils.ColorText(utils.RoleError, utils.T("common.banned"))
+	case isEnabled:
+		return utils.ColorText(utils.RoleOK, utils.T("common.online"))
+	default:
+		return utils.ColorText(utils.RoleError, utils.T("common.offline"))
+	}
+}
+
+// liveDuration 精确到秒的时长，详情面板每秒重绘时使用
+func liveDuration(d time.Duration) string {
+	d = max(d.Truncate(time.Second), 0)
+	clock := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60, int(d.Seconds())%60)
+	if days := int(d.Hours()) / 24; days > 0 {
+		return utils.T("tui.days_clock", days, clock)
+	}
+	return clock
+}
+
+// trustBadge 信任度标记，负数表示节点因异常被降低了信任度
+func trustBadge(trust int) string {
+	if trust < 0 {
+		return utils.ColorText(utils.RoleError, utils.T("tui.trust_low", trust))
+	}
+	return utils.ColorText(utils.RoleOK, utils.T("tui.trust_ok", trust))
+}
+
+// sinceText 距今时长，时间为空时显示 "-"
+func sinceText(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return utils.FormatDuration(time.Since(t)) + utils.T("common.ago")
+}
+
+func (s *TUIService) renderNodes(width, height int) []string {
+	if s.nodes == nil {
+		return s.renderLoading(tuiNodes)
+	}
+	if len(s.nodes) == 0 {
+		return []string{"", utils.T("tui.no_nodes")}
+	}
+
+	columns := []utils.TableColumn{
+		{Title: utils.T("common.status")},
+		{Title: utils.T("common.name"), MinWidth: 12},
+		{Title: utils.T("tui.col_bandwidth"), Right: true},
+		{Title: utils.T("common.trust"), Right: true},
+		{Title: utils.T("common.version")},
+		{Title: utils.T("common.last_active"), Right: true},
+	}
+	results := s.nodeResults()
+	if len(results) == 0 {
+		return []string{"", utils.T("tui.no_match", s.nodeList.query)}
+	}
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		node := s.nodes[r.Index]
+		name := utils.ColorText(utils.RoleValue, node.Name)
+		if s.nodeList.query != "" {
+			name = r.Match.Decorate(node.Name)
+		}
+		rows[i] = []string{
+			nodeStatusText(node.IsEnabled, node.IsBanned),
+			name,
+			fmt.Sprintf("%d/%d Mbps", node.MeasureBandwidth, node.Bandwidth),
+			fmt.Sprintf("%d", node.Trust),
+			node.Version,
+			sinceText(node.LastActivity),
+		}
+	}
+	start, end := s.nodeList.window(height-1, len(rows))
+	return renderTable(width, columns, rows, start, end, s.nodeList.cursor)
+}
+
+func (s *TUIService) renderDetail() []string {
+	if s.detailID == "" {
+		return []string{"", utils.T("tui.detail_hint")}
+	}
+	node := s.findNode(s.detailID)
+	if node == nil {
+		if s.nodes == nil {
+			return s.renderLoading(tuiNodes)
+		}
+		return []string{"", utils.ColorText(utils.RoleError, utils.T("tui.node_gone"))}
+	}
+	now := time.Now()
+	detail := NewNode().BuildNodeDetail(node, s.ranks, now)
+
+	lines := []string{"", " " + utils.ColorText(utils.RoleTitle, "🖥  "+node.Name) + "  " + trustBadge(node.Trust), ""}
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		lines = append(lines, "   "+utils.ColorText(utils.RoleLabel, utils.PadWidth(label, 12, true))+value)
+	}
+
+	field("ID", node.ID)
+	field(utils.T("common.status"), nodeStatusText(node.IsEnabled, node.IsBanned))
+	if node.Endpoint.Host != "" {
+		field(utils.T("tui.field_endpoint"), nodeEndpoint(*node))
+	}
+	field(utils.T("common.bandwidth"), utils.T("tui.bandwidth_value", node.Bandwidth, node.MeasureBandwidth))
+	if detail.BandwidthUtilization != nil {
+		field(utils.T("tui.field_utilization"), fmt.Sprintf("%.1f%%", *detail.BandwidthUtilization*100))
+	}
+	field(utils.T("common.version"), node.Version)
+	field(utils.T("tui.field_runtime"), strings.Trim(node.Flavor.Runtime+" / "+node.Flavor.Storage, " /"))
+	if detail.OnlineSeconds > 0 {
+		field(utils.T("tui.field_uptime"), utils.ColorText(utils.RoleValue, liveDuration(now.Sub(node.Uptime))))
+	}
+	if !node.LastActivity.IsZero() {
+		field(utils.T("common.last_active"), liveDuration(now.Sub(node.LastActivity))+utils.T("common.ago"))
+	}
+	if r := detail.TodayRank; r != nil {
+		field(utils.T("tui.field_today_rank"), utils.T("tui.rank_value", r.Rank, r.Total))
+		field(utils.T("tui.field_today_traffic"), utils.ColorText(utils.RoleValue, utils.FormatBytes(r.Bytes)))
+		field(utils.T("tui.field_today_hits"), utils.ColorText(utils.RoleValue, utils.TN("common.count_times", int(r.Hits))))
+	} else if s.ranks != nil {
+		field(utils.T("tui.field_today_rank"), utils.ColorText(utils.RoleMuted, utils.T("common.not_ranked")))
+	}
+	if len(s.history) > 0 {
+		values := make([]float64, len(s.history))
+		var peak int64
+		for i, p := range s.history {
+			values[i] = float64(p.Bytes)
+			peak = max(peak, p.Bytes)
+		}
+		first, last := s.history[0], s.history[len(s.history)-1]
+		field(utils.T("tui.field_history", len(s.history)), utils.ColorText(utils.RoleValue, utils.Sparkline(values, 0))+
+			utils.ColorText(utils.RoleMuted, utils.T("tui.history_range", first.Date[5:], last.Date[5:], utils.FormatBytes(peak))))
+	}
+	if !node.CreatedAt.IsZero() {
+		field(utils.T("tui.field_created"), node.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	field(utils.T("common.sponsor"), node.Sponsor.Name)
+	field(utils.T("tui.field_sponsor_url"), node.Sponsor.URL)
+	if node.DownReason != "" {
+		field(utils.T("tui.field_down_reason"), utils.ColorText(utils.RoleError, node.DownReason))
+	}
+	if node.BanReason != "" {
+		field(utils.T("tui.field_ban_reason"), utils.ColorText(utils.RoleError, node.BanReason))
+	}
+	if secret := s.secrets[node.ID]; secret != "" {
+		field(utils.T("tui.field_new_secret"), utils.ColorText(utils.RoleWarn, secret))
+	}
+	return append(lines, "", utils.ColorText(utils.RoleMuted, utils.T("tui.detail_actions")))
+}
+
+func (s *TUIService) renderRank(width, height int) []string {
+	if s.ranks == nil {
+		return s.renderLoading(tuiRank)
+	}
+
+	columns := []utils.TableColumn{
+		{Title: utils.T("common.rank"), Right: true},
+		{Title: utils.T("common.name"), MinWidth: 12},
+		{Title: utils.T("common.hits"), Right: true},
+		{Title: utils.T("common.traffic"), Right: true},
+		{Title: utils.T("common.status")},
+		{Title: utils.T("common.sponsor")},
+	}
+	results := s.rankResults()
+	if len(results) == 0 {
+		return []string{"", utils.T("tui.no_match", s.rankList.query)}
+	}
+	own := s.ownIDs()
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		rank := s.ranks[r.Index]
+		name := utils.ColorText(utils.RoleValue, rank.Name)
+		if s.rankList.query != "" {
+			name = r.Match.Decorate(rank.Name)
+		}
+		// 自己的节点加星标
+		if own[rank.ID] && s.rankList.query == "" {
+			name = utils.ColorText(utils.RoleOK, "★ "+rank.Name)
+		} else if own[rank.ID] {
+			name = utils.ColorText(utils.RoleOK, "★ ") + name
+		}
+		rows[i] = []string{
+			fmt.Sprintf("%d", r.Index+1),
+			name,
+			utils.ColorText(utils.RoleValue, utils.FormatNumber(rank.Metric.Hits)),
+			utils.ColorText(utils.RolePrompt, utils.FormatBytes(rank.Metric.Bytes)),
+			nodeStatusText(rank.IsEnabled, false),
+			rank.Sponsor.Name,
+		}
+	}
+	start, end := s.rankList.window(height-1, len(rows))
+	return renderTable(width, columns, rows, start, end, s.rankList.cursor)
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// UptimeWindow 定义可用性统计的时间窗口
+type UptimeWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// UptimeWindows 默认统计窗口
+var UptimeWindows = []UptimeWindow{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// uptimeLockTimeout 等待其他进程释放在线历史锁文件的最长时间
+const uptimeLockTimeout = 10 * time.Second
+
+// uptimeMu 串行化同一进程内的写入，锁文件只能区分不同进程
+var uptimeMu sync.Mutex
+
+type UptimeService struct {
+	dataDir string
+}
+
+func NewUptime(dataDir string) *UptimeService {
+	return &UptimeService{
+		dataDir: dataDir,
+	}
+}
+
+func (s *UptimeService) path() string {
+	return filepath.Join(s.dataDir, "uptime.json")
+}
+
+// Load 读取所有节点的在线历史
+func (s *UptimeService) Load() (map[string]*models.ClusterUptime, error) {
+	records := make(map[string]*models.ClusterUptime)
+
+	data, err := ioutil.ReadFile(s.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf(utils.T("uptime.read_failed"), err)
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf(utils.T("uptime.parse_failed"), err)
+	}
+	return records, nil
+}
+
+func (s *UptimeService) save(records map[string]*models.ClusterUptime) error {
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return fmt.Errorf(utils.T("common.mkdir_data_failed"), err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf(utils.T("uptime.marshal_failed"), err)
+	}
+
+	// 先写临时文件再替换，避免中断时损坏历史
+	tmp := s.path() + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf(utils.T("uptime.save_failed"), err)
+	}
+	return os.Rename(tmp, s.path())
+}
+
+// Record 根据一次轮询得到的节点状态更新离线区间。interval 为预期的轮询间隔，
+// 距上次轮询超过两个间隔时 (如 daemon 未运行)，中间的时段记为状态未知
+func (s *UptimeService) Record(nodes []models.Node, at time.Time, interval time.Duration) error {
+	uptimeMu.Lock()
+	defer uptimeMu.Unlock()
+
+	// daemon 和手动运行的 uptime poll 可能同时读改写同一个文件
+	unlock, err := utils.LockFile(s.path()+".lock", uptimeLockTimeout)
+	if err != nil {
+		return fmt.Errorf(utils.T("uptime.lock_failed"), err)
+	}
+	defer unlock()
+
+	records, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		rec, ok := records[node.ID]
+		if !ok {
+			rec = &models.ClusterUptime{
+				ID:        node.ID,
+				FirstSeen: at,
+				LastPoll:  at,
+				IsEnabled: true,
+			}
+			records[node.ID] = rec
+		}
+		rec.Name = node.Name
+		if interval > 0 && at.Sub(rec.LastPoll) > 2*interval {
+			rec.Gaps = append(rec.Gaps, models.Gap{Start: rec.LastPoll, End: at})
+		}
+
+		ongoing := len(rec.Outages) > 0 && rec.Outages[len(rec.Outages)-1].End.IsZero()
+		switch {
+		case !node.IsEnabled && !ongoing:
+			// 上游给出的下线时间落在两次轮询之间时更精确
+			start := at
+			if !node.Downtime.IsZero() && node.Downtime.After(rec.LastPoll) && node.Downtime.Before(at) {
+				start = node.Downtime
+			}
+			rec.Outages = append(rec.Outages, models.Outage{
+				Start:  start,
+				Reason: node.DownReason,
+			})
+		case !node.IsEnabled && ongoing:
+			if reason := node.DownReason; reason != "" {
+				rec.Outages[len(rec.Outages)-1].Reason = reason
+			}
+		case node.IsEnabled && ongoing:
+			outage := &rec.Outages[len(rec.Outages)-1]
+			end := at
+			if !node.Uptime.IsZero() && node.Uptime.After(outage.Start) && node.Uptime.Before(at) {
+				end = node.Uptime
+			}
+			outage.End = end
+		}
+
+		rec.IsEnabled = node.IsEnabled
+		rec.LastPoll = at
+	}
+
+	return s.save(records)
+}
+
+// Report 计算节点在指定窗口内的可用性、MTBF 与 MTTR
+func (s *UptimeService) Report(rec *models.ClusterUptime, window UptimeWindow, now time.Time) models.UptimeReport {
+	return s.ReportBetween(rec, window.Name, now.Add(-window.Duration), now)
+}
+
+// ReportBetween 计算节点在 [from, to) 区间内的可用性统计，轮询中断的时段不计入观测时长和离线时长
+func (s *UptimeService) ReportBetween(rec *models.ClusterUptime, name string, from, to time.Time) models.UptimeReport {
+	report := models.UptimeReport{
+		Window:       name,
+		Availability: -1,
+	}
+
+	if rec.FirstSeen.After(from) {
+		from = rec.FirstSeen
+	}
+	if rec.LastPoll.Before(to) {
+		to = rec.LastPoll
+	}
+	if !to.After(from) {
+		return report
+	}
+	unknown := gapOverlap(rec.Gaps, from, to)
+	observed := to.Sub(from) - unknown
+	report.Unknown = int64(unknown.Seconds())
+	if observed <= 0 {
+		return report
+	}
+
+	var downtime, repairTime time.Duration
+	repaired := 0
+	for _, outage := range rec.Outages {
+		end := outage.End
+		if end.IsZero() {
+			end = rec.LastPoll
+		}
+		if overlap := overlapDuration(outage.Start, end, from, to); overlap > 0 {
+			// 离线区间跨过轮询中断时，中断的部分同样是未知状态
+			start := outage.Start
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			downtime += overlap - gapOverlap(rec.Gaps, start, end)
+		}
+		if !outage.Start.Before(from) && outage.Start.Before(to) {
+			report.Failures++
+		}
+		if !outage.End.IsZero() && !outage.End.Before(from) && !outage.End.After(to) {
+			repairTime += outage.End.Sub(outage.Start)
+			repaired++
+		}
+	}
+
+	report.Observed = int64(observed.Seconds())
+	report.Downtime = int64(downtime.Seconds())
+	report.Availability = float64(observed-downtime) / float64(observed) * 100
+	if report.Failures > 0 {
+		report.MTBF = int64((observed - downtime).Seconds()) / int64(report.Failures)
+	}
+	if repaired > 0 {
+		report.MTTR = int64(repairTime.Seconds()) / int64(repaired)
+	}
+	return report
+}
+
+func overlapDuration(aStart, aEnd, bStart, bEnd time.Time) time.Duration {
+	if aStart.Before(bStart) {
+		aStart = bStart
+	}
+	if aEnd.After(bEnd) {
+		aEnd = bEnd
+	}
+	return aEnd.Sub(aStart)
+}
+
+// gapOverlap 返回 [from, to) 中落在轮询中断区间内的总时长
+func gapOverlap(gaps []models.Gap, from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, gap := range gaps {
+		if overlap := overlapDuration(gap.Start, gap.End, from, to); overlap > 0 {
+			total += overlap
+		}
+	}
+	return total
+}
+
+// FindRecord 按节点 ID 或名称查找在线历史
+func FindRecord(records map[string]*models.ClusterUptime, key string) *models.ClusterUptime {
+	if rec, ok := records[key]; ok {
+		return rec
+	}
+	for _, rec := range records {
+		if strings.EqualFold(rec.Name, key) {
+			return rec
+		}
+	}
+	return nil
+}
+
+// sortedRecords 按节点名称排序
+func sortedRecords(records map[string]*models.ClusterUptime) []*models.ClusterUptime {
+	list := make([]*models.ClusterUptime, 0, len(records))
+	for _, rec := range records {
+		list = append(list, rec)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func formatAvailability(report models.UptimeReport) string {
+	if report.Availability < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f%%", report.Availability)
+}
+
+func formatSeconds(seconds int64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return utils.FormatDuration(time.Duration(seconds) * time.Second)
+}
+
+// DisplayReports 显示所有节点的可用性汇总
+func (s *UptimeService) DisplayReports(records map[string]*models.ClusterUptime) {
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("uptime.title")))
+	fmt.Println(strings.Repeat("─", 100))
+
+	if len(records) == 0 {
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("uptime.no_records")))
+		return
+	}
+
+	now := time.Now()
+	table := utils.NewTable(
+		utils.TableColumn{Title: utils.T("common.node_name"), Wrap: true, MinWidth: 12},
+		utils.TableColumn{Title: utils.T("common.status")},
+		utils.TableColumn{Title: "24h", Right: true},
+		utils.TableColumn{Title: "7d", Right: true},
+		utils.TableColumn{Title: "30d", Right: true},
+		utils.TableColumn{Title: utils.T("uptime.col_failures"), Right: true},
+		utils.TableColumn{Title: "MTBF(30d)", Right: true},
+		utils.TableColumn{Title: "MTTR(30d)", Right: true},
+	)
+	for _, rec := range sortedRecords(records) {
+		status := utils.ColorText(utils.RoleOK, utils.T("common.online"))
+		if !rec.IsEnabled {
+			status = utils.ColorText(utils.RoleError, utils.T("common.offline"))
+		}
+
+		reports := make([]models.UptimeReport, len(UptimeWindows))
+		for i, window := range UptimeWindows {
+			reports[i] = s.Report(rec, window, now)
+		}
+		month := reports[len(reports)-1]
+
+		table.AddRow(
+			utils.ColorText(utils.RoleValue, rec.Name),
+			status,
+			formatAvailability(reports[0]),
+			formatAvailability(reports[1]),
+			formatAvailability(reports[2]),
+			fmt.Sprintf("%d", month.Failures),
+			formatSeconds(month.MTBF),
+			formatSeconds(month.MTTR))
+	}
+	table.Print()
+}
+
+// DisplayOutageLog 显示单个节点的离线记录，最新的在前
+func (s *UptimeService) DisplayOutageLog(rec *models.ClusterUptime) {
+	fmt.Printf("\n%s\n", utils.ColorText(utils.RoleTitle, utils.T("uptime.outages_title", rec.Name)))
+	fmt.Println(strings.Repeat("─", 100))
+
+	now := time.Now()
+	for _, window := range UptimeWindows {
+		report := s.Report(rec, window, now)
+		fmt.Printf(utils.T("uptime.summary"),
+			utils.ColorText(utils.RoleLabel, window.Name),
+			utils.ColorText(utils.RoleValue, formatAvailability(report)),
+			report.Failures,
+			formatSeconds(report.MTBF),
+			formatSeconds(report.MTTR))
+	}
+	fmt.Println()
+
+	if len(rec.Outages) == 0 {
+		fmt.Println(utils.ColorText(utils.RoleOK, utils.T("uptime.no_outages")))
+		return
+	}
+
+	table := utils.NewTable(
+		utils.TableColumn{Title: utils.T("uptime.col_start")},
+		utils.TableColumn{Title: utils.T("uptime.col_end")},
+		utils.TableColumn{Title: utils.T("uptime.col_duration")},
+		utils.TableColumn{Title: utils.T("uptime.col_reason"), Wrap: true},
+	)
+	for i := len(rec.Outages) - 1; i >= 0; i-- {
+		outage := rec.Outages[i]
+		end := utils.ColorText(utils.RoleError, utils.T("uptime.still_offline"))
+		duration := now.Sub(outage.Start)
+		if !outage.End.IsZero() {
+			end = outage.End.Local().Format("2006-01-02 15:04:05")
+			duration = outage.End.Sub(outage.Start)
+		}
+		reason := outage.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		table.AddRow(
+			outage.Start.Local().Format("2006-01-02 15:04:05"),
+			end,
+			utils.FormatDuration(duration),
+			reason)
+	}
+	table.Print()
+}
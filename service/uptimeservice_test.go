@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestReportBetweenExcludesGaps(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	s := NewUptime(t.TempDir())
+	online := []models.Node{{ID: "a", Name: "A", IsEnabled: true}}
+	offline := []models.Node{{ID: "a", Name: "A"}}
+
+	// 0-2h 每分钟轮询一次，之后中断 10 小时，恢复时节点离线 1 小时
+	at := start
+	for ; at.Before(start.Add(2 * time.Hour)); at = at.Add(time.Minute) {
+		if err := s.Record(online, at, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for at = start.Add(12 * time.Hour); at.Before(start.Add(13 * time.Hour)); at = at.Add(time.Minute) {
+		if err := s.Record(offline, at, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Record(online, at, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := records["a"]
+	if len(rec.Gaps) != 1 {
+		t.Fatalf("应记录一段轮询中断，实际为 %v", rec.Gaps)
+	}
+
+	report := s.ReportBetween(rec, "test", start, start.Add(13*time.Hour))
+	gap := start.Add(12 * time.Hour).Sub(start.Add(2*time.Hour - time.Minute))
+	if report.Unknown != int64(gap.Seconds()) {
+		t.Errorf("Unknown = %d，应为 %d", report.Unknown, int64(gap.Seconds()))
+	}
+	if report.Observed != int64((13*time.Hour - gap).Seconds()) {
+		t.Errorf("Observed = %d，不应包含中断时段", report.Observed)
+	}
+	if report.Downtime != int64(time.Hour.Seconds()) {
+		t.Errorf("Downtime = %d，应为 3600", report.Downtime)
+	}
+	want := float64(report.Observed-report.Downtime) / float64(report.Observed) * 100
+	if report.Availability != want || report.Availability > 70 {
+		t.Errorf("Availability = %.3f，中断时段不应算作在线", report.Availability)
+	}
+}
+
+func TestRecordConcurrentWriters(t *testing.T) {
+	s := NewUptime(t.TempDir())
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// 每个写入者记录不同的节点，任何一次读改写丢失都会少一个节点
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			node := models.Node{ID: fmt.Sprintf("n%d", i), IsEnabled: true}
+			if err := NewUptime(s.dataDir).Record([]models.Node{node}, at, time.Minute); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 20 {
+		t.Fatalf("应有 20 个节点的记录，实际为 %d", len(records))
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// watchData 一次刷新得到的数据
+type watchData struct {
+	dashboard *models.Dashboard
+	nodes     []models.Node
+	err       error
+}
+
+// WatchService 定时刷新仪表盘和自己节点状态的实时监控，变化的数值高亮显示，
+// 并在内存中保留每次刷新的出网带宽和在线节点数，补充上游每小时一个点的数据
+type WatchService struct {
+	interval time.Duration
+	samples  int
+	screen   *utils.Screen
+
+	dashboard *models.Dashboard
+	prev      *models.Dashboard
+	nodes     []models.Node
+	prevNodes map[string]models.Node
+	bandwidth []float64
+	online    []float64
+	err       error
+
+	loading   bool
+	paused    bool
+	updatedAt time.Time
+	nextAt    time.Time
+	results   chan watchData
+}
+
+// NewWatch 创建实时监控，interval 为刷新间隔，samples 为保留的采样点数
+func NewWatch(interval time.Duration, samples int) *WatchService {
+	return &WatchService{
+		interval: interval,
+		samples:  samples,
+		results:  make(chan watchData, 1),
+	}
+}
+
+// fetch 在后台请求仪表盘和节点列表，未登录时只显示仪表盘
+func (s *WatchService) fetch() {
+	s.loading = true
+	go func() {
+		var data watchData
+		data.dashboard, data.err = NewDashboard().GetDashboard()
+		if data.err == nil {
+			nodes, err := NewNode().GetNodeList()
+			switch {
+			case err == nil:
+				data.nodes = nodes
+				SaveNodeCache(nodes)
+			case errors.Is(err, ErrNotLoggedIn):
+				data.nodes = []models.Node{}
+			default:
+				data.err = err
+			}
+		}
+		s.results <- data
+	}()
+}
+
+// apply 记录上一次的数据用于比较，并追加采样点
+func (s *WatchService) apply(data watchData) {
+	s.loading = false
+	s.nextAt = time.Now().Add(s.interval)
+	s.err = data.err
+	if data.dashboard == nil {
+		return
+	}
+
+	s.prev = s.dashboard
+	s.dashboard = data.dashboard
+	s.prevNodes = make(map[string]models.Node, len(s.nodes))
+	for _, node := range s.nodes {
+		s.prevNodes[node.ID] = node
+	}
+	if data.nodes != nil {
+		s.nodes = data.nodes
+	}
+	s.updatedAt = time.Now()
+
+	s.bandwidth = appendSample(s.bandwidth, data.dashboard.CurrentBandwidth, s.samples)
+	s.online = appendSample(s.online, float64(data.dashboard.CurrentNodes), s.samples)
+}
+
+func appendSample(values []float64, v float64, keep int) []float64 {
+	values = append(values, v)
+	if len(values) > keep {
+		values = values[len(values)-keep:]
+	}
+	return values
+}
+
+// Run 运行实时监控直到 ctx 取消或按 q 退出；不是终端时每次刷新输出一行摘要
+func (s *WatchService) Run(ctx context.Context) error {
+	screen, err := utils.OpenScreen()
+	if err != nil {
+		return s.runPlain(ctx)
+	}
+	s.screen = screen
+	defer screen.Close()
+
+	s.fetch()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	s.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-screen.Keys():
+			if !ok {
+				return nil
+			}
+			switch {
+			case key.Code == utils.KeyCtrlC || key.Code == utils.KeyEsc || key.Rune == 'q':
+				return nil
+			case key.Rune == 'r' && !s.loading:
+				s.fetch()
+			case key.Rune == 'p' || key.Rune == ' ':
+				s.paused = !s.paused
+				if !s.paused {
+					s.nextAt = time.Now().Add(s.interval)
+				}
+			}
+		case data := <-s.results:
+			s.apply(data)
+		case <-screen.Output():
+		case <-ticker.C:
+			if !s.paused && !s.loading && !s.nextAt.IsZero() && time.Now().After(s.nextAt) {
+				s.fetch()
+			}
+		}
+		s.draw()
+	}
+}
+
+// runPlain 用于输出被重定向的情况，每次刷新打印一行
+func (s *WatchService) runPlain(ctx context.Context) error {
+	for {
+		s.fetch()
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-s.results:
+			s.apply(data)
+		}
+		fmt.Println(s.summary())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *WatchService) summary() string {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if s.err != nil && s.dashboard == nil {
+		return utils.T("watch.refresh_failed_at", now, s.err)
+	}
+	d := s.dashboard
+	line := utils.T("watch.summary",
+		now,
+		d.CurrentNodes, s.deltaText(func(d *models.Dashboard) float64 { return float64(d.CurrentNodes) }),
+		utils.FormatBandwidth(d.CurrentBandwidth), s.deltaText(func(d *models.Dashboard) float64 { return d.CurrentBandwidth }),
+		d.Load*100, utils.FormatBytes(d.Bytes), d.Hits)
+	if len(s.nodes) > 0 {
+		online := 0
+		for _, node := range s.nodes {
+			if node.IsEnabled {
+				online++
+			}
+		}
+		line += utils.T("watch.summary_own", online, len(s.nodes))
+	}
+	for _, change := range s.nodeChanges() {
+		line += " | " + change
+	}
+	if s.err != nil {
+		line += utils.T("watch.summary_partial", s.err)
+	}
+	return line
+}
+
+// deltaText 与上一次刷新相比的变化箭头
+func (s *WatchService) deltaText(value func(*models.Dashboard) float64) string {
+	if s.prev == nil {
+		return ""
+	}
+	switch prev, cur := value(s.prev), value(s.dashboard); {
+	case cur > prev:
+		return " ↑"
+	case cur < prev:
+		return " ↓"
+	}
+	return ""
+}
+
+// nodeChanges 描述上一次刷新以来状态变化的节点
+func (s *WatchService) nodeChanges() []string {
+	if len(s.prevNodes) == 0 {
+		return nil
+	}
+	var changes []string
+	for _, node := range s.nodes {
+		prev, ok := s.prevNodes[node.ID]
+		if !ok {
+			changes = append(changes, node.Name+utils.T("watch.node_added"))
+			continue
+		}
+		if prev.IsEnabled != node.IsEnabled || prev.IsBanned != node.IsBanned {
+			changes = append(changes, node.Name+" "+utils.StripANSI(nodeStatusText(node.IsEnabled, node.IsBanned)))
+		}
+	}
+	return changes
+}
+
+// highlight 数值与上一次不同时高亮，颜色关闭时在后面加 *
+func (s *WatchService) highlight(text string, changed bool) string {
+	if changed {
+		return utils.MarkText(utils.RoleHighlight, text, "", "*")
+	}
+	return utils.ColorText(utils.RoleValue, text)
+}
+
+func (s *WatchService) draw() {
+	width, height := s.screen.Size()
+
+	countdown := utils.T("watch.paused")
+	switch {
+	case s.loading:
+		countdown = utils.T("watch.refreshing")
+	case !s.paused && !s.nextAt.IsZero():
+		countdown = utils.TN("watch.countdown", max(int(time.Until(s.nextAt).Seconds()+0.999), 0))
+	}
+	title := utils.ColorText(utils.RoleTitle, utils.T("watch.title"))
+	right := utils.ColorText(utils.RoleWarn, utils.T("watch.interval", countdown, s.interval))
+	lines := []string{title + strings.Repeat(" ", max(width-utils.StringWidth(title)-utils.StringWidth(right), 1)) + right, ""}
+
+	if d := s.dashboard; d == nil {
+		if s.err != nil {
+			lines = append(lines, utils.ColorText(utils.RoleError, "   ❌ "+s.err.Error()))
+		} else {
+			lines = append(lines, utils.T("common.loading"))
+		}
+	} else {
+		lines = append(lines, s.renderMetrics(width)...)
+		lines = append(lines, "")
+		lines = append(lines, s.renderTrends(width)...)
+		lines = append(lines, "")
+		lines = append(lines, s.renderNodes(width, height-len(lines)-2)...)
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	lines = lines[:max(height-2, 0)]
+
+	message := ""
+	if s.err != nil && s.dashboard != nil {
+		message = utils.ColorText(utils.RoleError, utils.T("watch.refresh_failed")+s.err.Error())
+	} else if changes := s.nodeChanges(); len(changes) > 0 {
+		message = utils.ColorText(utils.RoleWarn, utils.T("watch.node_changes")+strings.Join(changes, utils.T("common.list_sep")))
+	}
+	status := " "
+	if !s.updatedAt.IsZero() {
+		status += utils.T("common.updated_at") + s.updatedAt.Format("15:04:05") + " │ "
+	}
+	status += utils.TN("watch.samples", len(s.bandwidth))
+	hints := utils.T("watch.hints")
+	status += strings.Repeat(" ", max(width-utils.StringWidth(status)-utils.StringWidth(hints), 1)) + hints
+	lines = append(lines, message, utils.ColorText(utils.RoleSelected, status))
+	s.screen.Render(lines)
+}
+
+func (s *WatchService) renderMetrics(width int) []string {
+	d := s.dashboard
+	cell := max((width-3)/3, 24)
+	metric := func(label, text string, value func(*models.Dashboard) float64) string {
+		changed := s.prev != nil && value(s.prev) != value(d)
+		return utils.PadWidth(utils.ColorText(utils.RoleLabel, label)+"  "+s.highlight(text, changed)+s.deltaText(value), cell, true)
+	}
+	return []string{
+		" " + utils.ColorText(utils.RoleTitle, utils.T("common.key_metrics")),
+		"   " + metric(utils.T("common.metric_online_nodes"), utils.TN("common.count_nodes", d.CurrentNodes), func(d *models.Dashboard) float64 { return float64(d.CurrentNodes) }) +
+			metric(utils.T("common.metric_bandwidth"), utils.FormatBandwidth(d.CurrentBandwidth), func(d *models.Dashboard) float64 { return d.CurrentBandwidth }) +
+			metric(utils.T("common.load"), fmt.Sprintf("%.2f%%", d.Load*100), func(d *models.Dashboard) float64 { return d.Load }),
+		"   " + metric(utils.T("common.metric_traffic"), utils.FormatBytes(d.Bytes), func(d *models.Dashboard) float64 { return float64(d.Bytes) }) +
+			metric(utils.T("common.metric_hits"), utils.TN("common.count_times", d.Hits), func(d *models.Dashboard) float64 { return float64(d.Hits) }) +
+			metric(utils.T("common.bandwidth_limit"), utils.FormatBandwidth(d.Bandwidth), func(d *models.Dashboard) float64 { return d.Bandwidth }),
+	}
+}
+
+// renderTrends 上游每小时数据和本次监控采样的迷你折线
+func (s *WatchService) renderTrends(width int) []string {
+	sparkWidth := max(width-44, 10)
+	line := func(label string, values []float64, format func(float64) string, note string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		low, high := values[0], values[0]
+		for _, v := range values {
+			low, high = min(low, v), max(high, v)
+		}
+		return "   " + utils.ColorText(utils.RoleLabel, utils.PadWidth(label, 14, true)) +
+			utils.ColorText(utils.RoleValue, utils.Sparkline(values, sparkWidth)) +
+			fmt.Sprintf("  %s ~ %s", format(low), format(high)) + utils.ColorText(utils.RoleMuted, "  "+note)
+	}
+
+	var hourlyBandwidth, hourlyNodes []float64
+	for _, h := range s.dashboard.Hourly {
+		hourlyBandwidth = append(hourlyBandwidth, h.Bandwidth)
+		hourlyNodes = append(hourlyNodes, float64(h.Nodes))
+	}
+	nodesText := func(v float64) string { return utils.T("watch.nodes_value", v) }
+	sampled := utils.TN("watch.recent_samples", len(s.bandwidth))
+	return []string{
+		" " + utils.ColorText(utils.RoleTitle, utils.T("watch.trends")),
+		line(utils.T("watch.line_bandwidth"), hourlyBandwidth, utils.FormatBandwidth, utils.T("watch.upstream_hourly")),
+		line(utils.T("watch.line_nodes"), hourlyNodes, nodesText, utils.T("watch.upstream_hourly")),
+		line(utils.T("watch.line_live_bandwidth"), s.bandwidth, utils.FormatBandwidth, sampled),
+		line(utils.T("watch.line_live_nodes"), s.online, nodesText, sampled),
+	}
+}
+
+// renderNodes 自己节点的状态表，与上一次相比有变化的单元格高亮
+func (s *WatchService) renderNodes(width, height int) []string {
+	if len(s.nodes) == 0 {
+		return nil
+	}
+	online := 0
+	for _, node := range s.nodes {
+		if node.IsEnabled {
+			online++
+		}
+	}
+	lines := []string{" " + utils.ColorText(utils.RoleTitle, utils.T("watch.own_title", online, len(s.nodes)))}
+
+	columns := []utils.TableColumn{
+		{Title: utils.T("common.status")},
+		{Title: utils.T("common.name"), MinWidth: 12},
+		{Title: utils.T("common.measured_bandwidth"), Right: true},
+		{Title: utils.T("common.trust"), Right: true},
+		{Title: utils.T("common.last_active"), Right: true},
+	}
+	rows := make([][]string, len(s.nodes))
+	for i, node := range s.nodes {
+		prev, seen := s.prevNodes[node.ID]
+		changed := func(diff bool) bool { return len(s.prevNodes) > 0 && (!seen || diff) }
+		status := nodeStatusText(node.IsEnabled, node.IsBanned)
+		if changed(prev.IsEnabled != node.IsEnabled || prev.IsBanned != node.IsBanned) {
+			status = utils.MarkText(utils.RoleHighlight, utils.StripANSI(status), "", "*")
+		}
+		rows[i] = []string{
+			status,
+			utils.ColorText(utils.RoleValue, node.Name),
+			s.highlight(fmt.Sprintf("%d Mbps", node.MeasureBandwidth), changed(prev.MeasureBandwidth != node.MeasureBandwidth)),
+			s.highlight(fmt.Sprintf("%d", node.Trust), changed(prev.Trust != node.Trust)),
+			sinceText(node.LastActivity),
+		}
+	}
+	return append(lines, renderTable(width, columns, rows, 0, min(len(rows), max(height-2, 0)), -1)...)
+}
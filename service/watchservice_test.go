@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+func TestAppendSampleKeepsLatest(t *testing.T) {
+	var values []float64
+	for i := 1; i <= 5; i++ {
+		values = appendSample(values, float64(i), 3)
+	}
+	if len(values) != 3 || values[0] != 3 || values[2] != 5 {
+		t.Errorf("应只保留最后 3 个采样点，实际为 %v", values)
+	}
+}
+
+func TestWatchApply(t *testing.T) {
+	s := NewWatch(time.Minute, 2)
+	node := models.Node{ID: "n1", Name: "node-1", IsEnabled: true}
+
+	s.apply(watchData{dashboard: &models.Dashboard{CurrentNodes: 10, CurrentBandwidth: 100}, nodes: []models.Node{node}})
+	if s.prev != nil || s.deltaText(func(d *models.Dashboard) float64 { return d.CurrentBandwidth }) != "" {
+		t.Error("第一次刷新没有可比较的数据")
+	}
+	if changes := s.nodeChanges(); len(changes) != 0 {
+		t.Errorf("第一次刷新不应有节点变化，实际为 %v", changes)
+	}
+
+	// 刷新失败时保留已有的数据，只记录错误
+	s.apply(watchData{err: errors.New("boom")})
+	if s.err == nil || s.dashboard.CurrentNodes != 10 || len(s.bandwidth) != 1 {
+		t.Fatalf("刷新失败时应保留已有数据，实际为 %+v", s.dashboard)
+	}
+
+	node.IsEnabled = false
+	added := models.Node{ID: "n2", Name: "node-2", IsEnabled: true}
+	s.apply(watchData{dashboard: &models.Dashboard{CurrentNodes: 8, CurrentBandwidth: 150}, nodes: []models.Node{node, added}})
+	if s.err != nil {
+		t.Errorf("刷新成功后应清除错误，实际为 %v", s.err)
+	}
+	if got := s.deltaText(func(d *models.Dashboard) float64 { return d.CurrentBandwidth }); got != " ↑" {
+		t.Errorf("带宽增加时应显示 ↑，实际为 %q", got)
+	}
+	if got := s.deltaText(func(d *models.Dashboard) float64 { return float64(d.CurrentNodes) }); got != " ↓" {
+		t.Errorf("在线节点减少时应显示 ↓，实际为 %q", got)
+	}
+	if got := s.deltaText(func(d *models.Dashboard) float64 { return d.Load }); got != "" {
+		t.Errorf("数值不变时不显示箭头，实际为 %q", got)
+	}
+	changes := strings.Join(s.nodeChanges(), "|")
+	if !strings.Contains(changes, "node-1 "+utils.T("common.offline")) || !strings.Contains(changes, "node-2"+utils.T("watch.node_added")) {
+		t.Errorf("应报告 node-1 离线和新增的 node-2，实际为 %q", changes)
+	}
+
+	// 采样点只保留最近的 2 个
+	s.apply(watchData{dashboard: &models.Dashboard{CurrentNodes: 9, CurrentBandwidth: 120}})
+	if len(s.bandwidth) != 2 || s.bandwidth[0] != 150 || s.online[1] != 9 {
+		t.Errorf("采样点应为最近的 2 个，实际为 %v %v", s.bandwidth, s.online)
+	}
+	// 没有节点列表的刷新保留上一次的节点
+	if len(s.nodes) != 2 {
+		t.Errorf("没有节点列表时应保留上一次的节点，实际为 %v", s.nodes)
+	}
+}
+
+func TestGetNodeListWithoutCookie(t *testing.T) {
+	setupTempDir(t)
+	if _, err := NewNode().GetNodeList(); !errors.Is(err, ErrNotLoggedIn) {
+		t.Fatalf("没有 cookie.json 时应返回 ErrNotLoggedIn，实际为 %v", err)
+	}
+}
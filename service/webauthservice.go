@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+const (
+	sessionCookieName = "oba_session"
+	csrfHeaderName    = "X-CSRF-Token"
+)
+
+// 登录失败后同一 IP 需要等待的时间，每次失败翻倍，最长 loginMaxDelay；
+// 超过 loginFailureReset 没有再失败时重新计数
+const (
+	loginBaseDelay    = time.Second
+	loginMaxDelay     = 5 * time.Minute
+	loginFailureReset = 15 * time.Minute
+)
+
+// dummyPasswordHash 账号不存在时用来比较密码的 bcrypt 哈希，cost 与 HashPassword 相同，
+// 使账号不存在和密码错误的耗时相近，无法通过响应时间判断账号是否存在
+const dummyPasswordHash = "$2a$10$1/J1phVuZoYwAm4jjjWe4eJ52zRMvHjyNlnGiwteVo1oVfOTfB54O"
+
+// ErrLoginThrottled 表示该 IP 登录失败次数过多，需要等待后再试
+var ErrLoginThrottled = utils.NewError("webauth.too_many_attempts")
+
+// 无需登录即可访问的接口
+var publicAPIPaths = map[string]bool{
+	"/api/auth/login":   true,
+	"/api/auth/session": true,
+	"/api/openapi.json": true,
+	"/api/health":       true,
+	"/api/ready":        true,
+}
+
+// WebAuthService 管理本地 Web 面板的登录、会话、权限和 CSRF 校验
+// 支持两种登录方式: config.json 中的本地账号 (bcrypt)，以及每次启动时生成的访问令牌
+type WebAuthService struct {
+	token string
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	users    []models.WebUser
+	sessions map[string]*sessionEntry
+	failures map[string]*loginFailure // 按客户端 IP 记录连续登录失败
+}
+
+// loginFailure 记录一个 IP 的连续登录失败次数，until 之前拒绝该 IP 的登录请求
+type loginFailure struct {
+	count int
+	last  time.Time
+	until time.Time
+}
+
+// sessionEntry 记录一次登录，权限在每次请求时根据账号的当前角色计算
+type sessionEntry struct {
+	username  string
+	viaToken  bool
+	csrfToken string
+	expiresAt time.Time
+}
+
+type sessionContextKey struct{}
+
+// SessionFromContext 返回认证中间件写入请求上下文的会话
+func SessionFromContext(ctx context.Context) (*models.WebSession, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(*models.WebSession)
+	return session, ok
+}
+
+func NewWebAuth(config models.WebConfig) (*WebAuthService, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(config.SessionTTL) * time.Hour
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return &WebAuthService{
+		users:    append([]models.WebUser(nil), config.Users...),
+		token:    token,
+		ttl:      ttl,
+		sessions: make(map[string]*sessionEntry),
+		failures: make(map[string]*loginFailure),
+	}, nil
+}
+
+// HashPassword 生成 bcrypt 密码哈希
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf(utils.T("webauth.hash_failed"), err)
+	}
+	return string(hash), nil
+}
+
+// CheckListenAddress 检查监听地址，未开启 expose 时只允许本机地址
+func CheckListenAddress(config models.WebConfig) (string, error) {
+	host := config.Listen
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if config.Expose || isLoopbackHost(host) {
+		return host, nil
+	}
+	return "", fmt.Errorf(utils.T("webauth.not_local"), host)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf(utils.T("webauth.token_failed"), err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Token 返回本次启动生成的访问令牌
+func (s *WebAuthService) Token() string {
+	return s.token
+}
+
+// Login 校验账号密码或访问令牌，成功时创建会话。ip 为客户端地址，
+// 同一 IP 连续失败后需要等待的时间逐次翻倍，等待期间直接返回 ErrLoginThrottled
+func (s *WebAuthService) Login(ip, username, password, token string) (string, *models.WebSession, error) {
+	if s.throttled(ip) {
+		return "", nil, ErrLoginThrottled
+	}
+
+	entry := &sessionEntry{}
+	switch {
+	case token != "":
+		if !secureEqual(token, s.token) {
+			s.recordFailure(ip)
+			return "", nil, errors.New(utils.T("webauth.invalid_token"))
+		}
+		entry.viaToken = true
+	case username != "":
+		s.mu.Lock()
+		user, ok := s.findUser(username)
+		s.mu.Unlock()
+		hash := dummyPasswordHash
+		if ok {
+			hash = user.PasswordHash
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); !ok || err != nil {
+			s.recordFailure(ip)
+			return "", nil, errors.New(utils.T("webauth.bad_credentials"))
+		}
+		entry.username = username
+	default:
+		return "", nil, errors.New(utils.T("webauth.missing_credentials"))
+	}
+
+	id, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+	if entry.csrfToken, err = randomToken(32); err != nil {
+		return "", nil, err
+	}
+	entry.expiresAt = time.Now().Add(s.ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, ip)
+	s.sessions[id] = entry
+	session, _ := s.describe(entry)
+	return id, session, nil
+}
+
+// throttled 返回该 IP 是否仍在登录失败后的等待时间内
+func (s *WebAuthService) throttled(ip string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failure, ok := s.failures[ip]
+	return ok && time.Now().Before(failure.until)
+}
+
+// recordFailure 记录一次登录失败并计算下次允许登录的时间
+func (s *WebAuthService) recordFailure(ip string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, failure := range s.failures {
+		if now.Sub(failure.last) > loginFailureReset && now.After(failure.until) {
+			delete(s.failures, key)
+		}
+	}
+	failure, ok := s.failures[ip]
+	if !ok {
+		failure = &loginFailure{}
+		s.failures[ip] = failure
+	}
+	failure.count++
+	failure.last = now
+	delay := loginMaxDelay
+	if failure.count <= 10 {
+		delay = loginBaseDelay << (failure.count - 1)
+		if delay > loginMaxDelay {
+			delay = loginMaxDelay
+		}
+	}
+	failure.until = now.Add(delay)
+}
+
+// findUser 查找账号，调用方需持有锁
+func (s *WebAuthService) findUser(username string) (models.WebUser, bool) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, true
+		}
+	}
+	return models.WebUser{}, false
+}
+
+// describe 根据当前账号配置生成会话信息，账号已被删除时返回 false，调用方需持有锁
+// 角色在每次请求时重新读取，修改角色后立即生效
+func (s *WebAuthService) describe(entry *sessionEntry) (*models.WebSession, bool) {
+	session := &models.WebSession{
+		Username:  entry.username,
+		Role:      models.RoleAdmin,
+		CSRFToken: entry.csrfToken,
+		ExpiresAt: entry.expiresAt,
+	}
+	if entry.viaToken {
+		session.Username = utils.T("webauth.token_user")
+	} else {
+		user, ok := s.findUser(entry.username)
+		if !ok {
+			return nil, false
+		}
+		session.Role = user.EffectiveRole()
+	}
+	session.Capabilities = models.RoleCapabilities[session.Role]
+	return session, true
+}
+
+// Logout 删除会话
+func (s *WebAuthService) Logout(id string) {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+}
+
+// Session 根据请求中的会话 Cookie 返回会话，过期的会话会被清除
+func (s *WebAuthService) Session(r *http.Request) (string, *models.WebSession, bool) {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return "", nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	entry, ok := s.sessions[cookie.Value]
+	if !ok {
+		return "", nil, false
+	}
+	session, ok := s.describe(entry)
+	if !ok {
+		delete(s.sessions, cookie.Value)
+		return "", nil, false
+	}
+	return cookie.Value, session, true
+}
+
+// Users 返回所有本地账号
+func (s *WebAuthService) Users() []models.WebUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.WebUser(nil), s.users...)
+}
+
+// SaveUser 创建或修改本地账号并写入配置文件，password 或 role 为空时保持不变
+func (s *WebAuthService) SaveUser(username, password, role string, create bool) error {
+	if role != "" {
+		if _, ok := models.RoleCapabilities[role]; !ok {
+			return fmt.Errorf(utils.T("webauth.invalid_role"), role)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := append([]models.WebUser(nil), s.users...)
+	index := -1
+	for i, user := range users {
+		if user.Username == username {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case create && index != -1:
+		return fmt.Errorf(utils.T("webauth.user_exists"), username)
+	case !create && index == -1:
+		return fmt.Errorf(utils.T("webauth.user_not_found"), username)
+	case create:
+		if username == "" || password == "" {
+			return errors.New(utils.T("webauth.empty_credentials"))
+		}
+		if role == "" {
+			role = models.RoleViewer
+		}
+		users = append(users, models.WebUser{Username: username, Role: role})
+		index = len(users) - 1
+	}
+
+	if password != "" {
+		if len(password) < 8 {
+			return errors.New(utils.T("webauth.password_short"))
+		}
+		hash, err := HashPassword(password)
+		if err != nil {
+			return err
+		}
+		users[index].PasswordHash = hash
+	}
+	if role != "" {
+		users[index].Role = role
+	}
+	return s.saveUsers(users)
+}
+
+// RemoveUser 删除本地账号，该账号的会话随之失效
+func (s *WebAuthService) RemoveUser(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]models.WebUser, 0, len(s.users))
+	for _, user := range s.users {
+		if user.Username != username {
+			users = append(users, user)
+		}
+	}
+	if len(users) == len(s.users) {
+		return fmt.Errorf(utils.T("webauth.user_not_found"), username)
+	}
+	return s.saveUsers(users)
+}
+
+// saveUsers 将账号写入配置文件，成功后更新内存中的账号，调用方需持有锁
+func (s *WebAuthService) saveUsers(users []models.WebUser) error {
+	configService := NewConfig()
+	cfg, err := configService.Load()
+	if err != nil {
+		return err
+	}
+	cfg.Web.Users = users
+	if err := configService.Save(cfg); err != nil {
+		return err
+	}
+	s.users = users
+	return nil
+}
+
+// SetSessionCookie 写入会话 Cookie
+func (s *WebAuthService) SetSessionCookie(w http.ResponseWriter, r *http.Request, id string, session *models.WebSession) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    id,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// ClearSessionCookie 清除会话 Cookie
+func (s *WebAuthService) ClearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// bearerToken 返回 Authorization 头中的令牌
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// Middleware 保护 /api/ 下的接口，静态页面不需要登录
+// 使用 Bearer 令牌的请求不依赖 Cookie，因此无需 CSRF 校验
+func (s *WebAuthService) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || publicAPIPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if token := bearerToken(r); token != "" {
+			if !secureEqual(token, s.token) {
+				wrapResponse(w, http.StatusUnauthorized, utils.T("webauth.invalid_token_http"), nil)
+				return
+			}
+			session := &models.WebSession{
+				Username:     utils.T("webauth.token_user"),
+				Role:         models.RoleAdmin,
+				Capabilities: models.RoleCapabilities[models.RoleAdmin],
+			}
+			setRequestUser(r.Context(), session.Username)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, session)))
+			return
+		}
+
+		_, session, ok := s.Session(r)
+		if !ok {
+			wrapResponse(w, http.StatusUnauthorized, utils.T("web.login_required"), nil)
+			return
+		}
+
+		setRequestUser(r.Context(), session.Username)
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if !secureEqual(r.Header.Get(csrfHeaderName), session.CSRFToken) {
+				wrapResponse(w, http.StatusForbidden, utils.T("webauth.csrf_failed"), nil)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, session)))
+	})
+}
+
+// Require 包装处理函数，要求会话拥有指定权限
+func (s *WebAuthService) Require(capability string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, ok := SessionFromContext(r.Context())
+		if !ok {
+			wrapResponse(w, http.StatusUnauthorized, utils.T("web.login_required"), nil)
+			return
+		}
+		if !session.Can(capability) {
+			utils.DebugLog(1, "[Web API] %s (%s) 缺少权限 %s: %s %s", session.Username, session.Role, capability, r.Method, r.URL.Path)
+			wrapResponse(w, http.StatusForbidden, utils.T("webauth.forbidden"), nil)
+			return
+		}
+		handler(w, r)
+	}
+}
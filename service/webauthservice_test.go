@@ -0,0 +1,266 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+const testPassword = "correct horse"
+
+// newAuthTestServer 返回带 viewer、operator、admin 三个账号的 Web 服务和完整的路由，
+// 就绪检查结果已缓存，不会请求上游
+func newAuthTestServer(t *testing.T) (*WebService, http.Handler) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := models.DefaultConfig()
+	for _, role := range []string{models.RoleViewer, models.RoleOperator, models.RoleAdmin} {
+		cfg.Web.Users = append(cfg.Web.Users, models.WebUser{Username: role, PasswordHash: string(hash), Role: role})
+	}
+
+	s := NewWeb(0, cfg)
+	if s.auth, err = NewWebAuth(cfg.Web); err != nil {
+		t.Fatal(err)
+	}
+	s.health.last = &models.HealthStatus{Ready: true, CheckedAt: time.Now()}
+	s.health.expiry = time.Now().Add(time.Hour)
+	handler, err := s.routes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, handler
+}
+
+// login 通过登录接口登录，返回会话 Cookie 和 CSRF 令牌
+func login(t *testing.T, handler http.Handler, username string) (*http.Cookie, string) {
+	t.Helper()
+	body := `{"username": "` + username + `", "password": "` + testPassword + `"}`
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(body))
+	req.RemoteAddr = "192.0.2.1:1234"
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s 登录失败: %d %s", username, rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data models.WebSession `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			return cookie, resp.Data.CSRFToken
+		}
+	}
+	t.Fatalf("%s 登录后没有会话 Cookie", username)
+	return nil, ""
+}
+
+// authRequest 描述一次请求使用的凭据
+type authRequest struct {
+	cookie *http.Cookie
+	csrf   string
+	bearer string
+}
+
+func (a authRequest) do(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if a.cookie != nil {
+		req.AddCookie(a.cookie)
+	}
+	if a.csrf != "" {
+		req.Header.Set(csrfHeaderName, a.csrf)
+	}
+	if a.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+a.bearer)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRoutesEnforceRoles(t *testing.T) {
+	s, handler := newAuthTestServer(t)
+
+	callers := map[string]authRequest{"token": {bearer: s.auth.Token()}}
+	for _, role := range []string{models.RoleViewer, models.RoleOperator, models.RoleAdmin} {
+		cookie, csrf := login(t, handler, role)
+		callers[role] = authRequest{cookie: cookie, csrf: csrf}
+	}
+
+	// 节点写入接口使用无效的节点 ID，通过鉴权后在请求上游之前返回 400
+	routes := []struct {
+		method, path, body string
+		allowed            []string
+	}{
+		{http.MethodGet, "/api/ready/details", "", []string{"viewer", "operator", "admin", "token"}},
+		{http.MethodPatch, "/api/nodes/abc%3Fx=1", `{"name": "x"}`, []string{"operator", "admin", "token"}},
+		{http.MethodPatch, "/api/nodes/abc%3Fx=1/sponsor", `{"sponsor": {"name": "x"}}`, []string{"operator", "admin", "token"}},
+		{http.MethodPost, "/api/nodes/..%2Fother/reset-secret", "", []string{"admin", "token"}},
+		{http.MethodGet, "/api/users", "", []string{"admin", "token"}},
+	}
+	for _, route := range routes {
+		for name, caller := range callers {
+			rec := caller.do(handler, route.method, route.path, route.body)
+			allowed := false
+			for _, a := range route.allowed {
+				allowed = allowed || a == name
+			}
+			switch {
+			case allowed && (rec.Code == http.StatusUnauthorized || rec.Code == http.StatusForbidden):
+				t.Errorf("%s %s (%s) 应允许访问，实际为 %d: %s", route.method, route.path, name, rec.Code, rec.Body.String())
+			case !allowed && rec.Code != http.StatusForbidden:
+				t.Errorf("%s %s (%s) 应返回 403，实际为 %d", route.method, route.path, name, rec.Code)
+			}
+		}
+
+		rec := authRequest{}.do(handler, route.method, route.path, route.body)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s 未登录时应返回 401，实际为 %d", route.method, route.path, rec.Code)
+		}
+		rec = authRequest{bearer: "wrong"}.do(handler, route.method, route.path, route.body)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s 使用错误的令牌时应返回 401，实际为 %d", route.method, route.path, rec.Code)
+		}
+	}
+
+	if rec := callers["viewer"].do(handler, http.MethodGet, "/api/ready/details", ""); rec.Code != http.StatusOK {
+		t.Errorf("viewer 查看就绪详情应返回 200，实际为 %d", rec.Code)
+	}
+	if rec := callers["admin"].do(handler, http.MethodGet, "/api/users", ""); rec.Code != http.StatusOK {
+		t.Errorf("admin 查看账号应返回 200，实际为 %d", rec.Code)
+	}
+}
+
+func TestSessionRequiresCSRFForWrites(t *testing.T) {
+	s, handler := newAuthTestServer(t)
+	cookie, csrf := login(t, handler, models.RoleAdmin)
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		for _, token := range []string{"", "wrong"} {
+			rec := authRequest{cookie: cookie, csrf: token}.do(handler, method, "/api/users/viewer", "{}")
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("%s 的 CSRF 令牌为 %q 时应返回 403，实际为 %d", method, token, rec.Code)
+			}
+		}
+	}
+
+	// CSRF 令牌正确时通过校验，到达处理函数
+	rec := authRequest{cookie: cookie, csrf: csrf}.do(handler, http.MethodPatch, "/api/nodes/abc%3Fx=1", `{"name": "x"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("CSRF 令牌正确时应到达处理函数，实际为 %d", rec.Code)
+	}
+
+	// Bearer 令牌不使用 Cookie，不需要 CSRF 令牌
+	rec = authRequest{bearer: s.auth.Token()}.do(handler, http.MethodPatch, "/api/nodes/abc%3Fx=1", `{"name": "x"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("使用 Bearer 令牌时不应要求 CSRF，实际为 %d", rec.Code)
+	}
+}
+
+func TestSessionExpiryAndDeletedUser(t *testing.T) {
+	s, handler := newAuthTestServer(t)
+	viewer, _ := login(t, handler, models.RoleViewer)
+	operator, _ := login(t, handler, models.RoleOperator)
+
+	s.auth.mu.Lock()
+	s.auth.sessions[viewer.Value].expiresAt = time.Now().Add(-time.Minute)
+	s.auth.mu.Unlock()
+	if rec := (authRequest{cookie: viewer}).do(handler, http.MethodGet, "/api/ready/details", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("过期的会话应返回 401，实际为 %d", rec.Code)
+	}
+
+	// 删除账号后该账号的会话失效
+	s.auth.mu.Lock()
+	var users []models.WebUser
+	for _, user := range s.auth.users {
+		if user.Username != models.RoleOperator {
+			users = append(users, user)
+		}
+	}
+	s.auth.users = users
+	s.auth.mu.Unlock()
+	if rec := (authRequest{cookie: operator}).do(handler, http.MethodGet, "/api/ready/details", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("已删除账号的会话应返回 401，实际为 %d", rec.Code)
+	}
+	s.auth.mu.Lock()
+	_, kept := s.auth.sessions[operator.Value]
+	s.auth.mu.Unlock()
+	if kept {
+		t.Error("已删除账号的会话应被清除")
+	}
+}
+
+func TestLoginBacksOffPerIP(t *testing.T) {
+	s, handler := newAuthTestServer(t)
+	const ip = "192.0.2.7"
+
+	if _, _, err := s.auth.Login(ip, models.RoleAdmin, "wrong", ""); err == nil || errors.Is(err, ErrLoginThrottled) {
+		t.Fatalf("密码错误时应返回凭据错误，实际为 %v", err)
+	}
+	// 等待期间即使密码正确也拒绝
+	if _, _, err := s.auth.Login(ip, models.RoleAdmin, testPassword, ""); !errors.Is(err, ErrLoginThrottled) {
+		t.Fatalf("失败后应要求等待，实际为 %v", err)
+	}
+	// 其他 IP 不受影响
+	if _, _, err := s.auth.Login("192.0.2.8", models.RoleAdmin, testPassword, ""); err != nil {
+		t.Fatalf("其他 IP 应可以登录: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"token": "wrong"}`))
+	req.RemoteAddr = ip + ":4321"
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("等待期间登录接口应返回 429，实际为 %d", rec.Code)
+	}
+
+	// 等待时间逐次翻倍，成功登录后清除记录
+	s.auth.mu.Lock()
+	failure := s.auth.failures[ip]
+	failure.until = time.Now()
+	s.auth.mu.Unlock()
+	s.auth.Login(ip, models.RoleAdmin, "wrong", "")
+	s.auth.mu.Lock()
+	if failure.count != 2 || time.Until(failure.until) < loginBaseDelay {
+		t.Errorf("第二次失败后应等待 %v，实际为 %v", 2*loginBaseDelay, time.Until(failure.until))
+	}
+	failure.until = time.Now()
+	s.auth.mu.Unlock()
+	if _, _, err := s.auth.Login(ip, models.RoleAdmin, testPassword, ""); err != nil {
+		t.Fatalf("等待结束后应可以登录: %v", err)
+	}
+	s.auth.mu.Lock()
+	_, ok := s.auth.failures[ip]
+	s.auth.mu.Unlock()
+	if ok {
+		t.Error("登录成功后应清除失败记录")
+	}
+}
+
+func TestLoginUnknownUserMatchesWrongPassword(t *testing.T) {
+	s, _ := newAuthTestServer(t)
+
+	// 不存在的账号也要做一次同样 cost 的 bcrypt 比较
+	if cost, err := bcrypt.Cost([]byte(dummyPasswordHash)); err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("占位哈希的 cost 应为 %d，实际为 %d (%v)", bcrypt.DefaultCost, cost, err)
+	}
+	_, _, unknown := s.auth.Login("192.0.2.20", "nobody", testPassword, "")
+	_, _, wrong := s.auth.Login("192.0.2.21", models.RoleAdmin, "wrong", "")
+	if unknown == nil || wrong == nil || unknown.Error() != wrong.Error() {
+		t.Errorf("账号不存在和密码错误应返回相同的错误，实际为 %v 和 %v", unknown, wrong)
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+// 推送给浏览器的事件类型
+const (
+	EventDashboard = "dashboard"
+	EventNodes     = "nodes"
+	EventRank      = "rank"
+)
+
+// 服务端轮询间隔，所有浏览器共享同一份数据
+var liveIntervals = map[string]time.Duration{
+	EventDashboard: 30 * time.Second,
+	EventNodes:     30 * time.Second,
+	EventRank:      60 * time.Second,
+}
+
+const (
+	sseHeartbeat     = 25 * time.Second
+	sseSubscriberBuf = 16
+)
+
+type liveEvent struct {
+	Type string
+	Data []byte
+}
+
+type liveSnapshot struct {
+	data []byte // REST 接口返回的数据
+	at   time.Time
+}
+
+// liveFeed 在服务端定时拉取仪表盘、节点列表和排行榜，数据变化时推送给所有订阅者
+// 没有浏览器连接时不请求上游
+type liveFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan liveEvent]struct{}
+	latest      map[string]liveEvent
+	snapshots   map[string]liveSnapshot
+	nodes       map[string]models.Node
+	nextPoll    map[string]time.Time
+	wake        chan struct{}
+	closed      bool
+}
+
+func newLiveFeed() *liveFeed {
+	return &liveFeed{
+		subscribers: make(map[chan liveEvent]struct{}),
+		latest:      make(map[string]liveEvent),
+		snapshots:   make(map[string]liveSnapshot),
+		nextPoll:    make(map[string]time.Time),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// subscribe 注册订阅者并立即发送已有的最新数据
+func (f *liveFeed) subscribe() (<-chan liveEvent, func()) {
+	ch := make(chan liveEvent, sseSubscriberBuf)
+
+	f.mu.Lock()
+	if f.closed {
+		close(ch)
+		f.mu.Unlock()
+		return ch, func() {}
+	}
+	for _, kind := range []string{EventDashboard, EventNodes, EventRank} {
+		if event, ok := f.latest[kind]; ok {
+			ch <- event
+		}
+	}
+	f.subscribers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subscribers[ch]; ok {
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publishLocked 把事件发给所有订阅者，缓冲区已满的慢速连接会被断开，由浏览器重连
+// 调用方需要持有 f.mu
+func (f *liveFeed) publishLocked(event liveEvent) {
+	for ch := range f.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// close 断开所有订阅者，使 SSE 连接在服务器关闭前结束
+func (f *liveFeed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for ch := range f.subscribers {
+		delete(f.subscribers, ch)
+		close(ch)
+	}
+}
+
+// cached 返回仍在轮询间隔内的数据，供 REST 接口复用
+func (f *liveFeed) cached(kind string) (json.RawMessage, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	snapshot, ok := f.snapshots[kind]
+	if !ok || time.Since(snapshot.at) > liveIntervals[kind] {
+		return nil, false
+	}
+	return json.RawMessage(snapshot.data), true
+}
+
+// invalidate 在数据被修改后使缓存失效，下一次 REST 请求会重新拉取
+func (f *liveFeed) invalidate(kind string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if snapshot, ok := f.snapshots[kind]; ok {
+		snapshot.at = time.Time{}
+		f.snapshots[kind] = snapshot
+	}
+}
+
+// run 定时轮询上游，直到 ctx 结束
+func (f *liveFeed) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-f.wake:
+		}
+
+		f.mu.Lock()
+		active := len(f.subscribers) > 0
+		f.mu.Unlock()
+		if !active {
+			continue
+		}
+
+		now := time.Now()
+		for _, kind := range []string{EventDashboard, EventNodes, EventRank} {
+			if now.Before(f.nextPoll[kind]) {
+				continue
+			}
+			f.nextPoll[kind] = now.Add(liveIntervals[kind])
+			if err := f.poll(ctx, kind); err != nil {
+				utils.DebugLog(1, "[Web] 轮询 %s 失败: %v", kind, err)
+			}
+		}
+	}
+}
+
+// poll 拉取一种数据，内容变化时推送事件
+func (f *liveFeed) poll(ctx context.Context, kind string) error {
+	var data, payload, latest []byte
+	var err error
+	switch kind {
+	case EventDashboard:
+		var dashboard *models.Dashboard
+		if dashboard, err = NewDashboard().GetDashboard(); err != nil {
+			return err
+		}
+		data, err = json.Marshal(dashboard)
+		payload, latest = data, data
+	case EventNodes:
+		var nodes []models.Node
+		if nodes, err = NewNode().GetNodeList(); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(nodes); err != nil {
+			return err
+		}
+		if payload, err = json.Marshal(models.NodesEvent{Nodes: nodes, Changes: f.diffNodes(nodes)}); err != nil {
+			return err
+		}
+		// 新连接只需要最新的列表，不重复发送已推送过的状态变化
+		latest, err = json.Marshal(models.NodesEvent{Nodes: nodes, Changes: []models.NodeStatusChange{}})
+	case EventRank:
+		var ranks []NodeMetricRank
+		if ranks, err = NewNode().GetNodeMetricRank(ctx); err != nil {
+			return err
+		}
+		data, err = json.Marshal(ranks)
+		payload, latest = data, data
+	default:
+		return fmt.Errorf(utils.T("web.unknown_event"), kind)
+	}
+	if err != nil {
+		return fmt.Errorf(utils.T("web.marshal_failed"), err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	previous, seen := f.snapshots[kind]
+	f.snapshots[kind] = liveSnapshot{data: data, at: time.Now()}
+	if seen && bytes.Equal(previous.data, data) {
+		return nil
+	}
+	f.latest[kind] = liveEvent{Type: kind, Data: latest}
+	f.publishLocked(liveEvent{Type: kind, Data: payload})
+	return nil
+}
+
+// diffNodes 比较节点的在线和封禁状态，第一次轮询只记录状态
+func (f *liveFeed) diffNodes(nodes []models.Node) []models.NodeStatusChange {
+	changes := []models.NodeStatusChange{}
+	current := make(map[string]models.Node, len(nodes))
+	for _, node := range nodes {
+		current[node.ID] = node
+	}
+	if f.nodes == nil {
+		f.nodes = current
+		return changes
+	}
+
+	change := func(node models.Node, kind, reason string) {
+		changes = append(changes, models.NodeStatusChange{
+			ID:        node.ID,
+			Name:      node.Name,
+			Type:      kind,
+			IsEnabled: node.IsEnabled,
+			IsBanned:  node.IsBanned,
+			Reason:    reason,
+		})
+	}
+	for _, node := range nodes {
+		prev, ok := f.nodes[node.ID]
+		switch {
+		case !ok:
+			change(node, "added", "")
+		case prev.IsEnabled && !node.IsEnabled:
+			change(node, "offline", node.DownReason)
+		case !prev.IsEnabled && node.IsEnabled:
+			change(node, "online", "")
+		}
+		if ok && !prev.IsBanned && node.IsBanned {
+			change(node, "banned", node.BanReason)
+		} else if ok && prev.IsBanned && !node.IsBanned {
+			change(node, "unbanned", "")
+		}
+	}
+	for id, prev := range f.nodes {
+		if _, ok := current[id]; !ok {
+			change(prev, "removed", "")
+		}
+	}
+	f.nodes = current
+	return changes
+}
+
+// 通过 Server-Sent Events 推送数据更新
+func (s *WebService) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events, cancel := s.live.subscribe()
+	defer cancel()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+		case <-heartbeat.C:
+			// 会话过期或账号被删除后断开连接
+			if bearerToken(r) == "" {
+				if _, _, ok := s.auth.Session(r); !ok {
+					return
+				}
+			}
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
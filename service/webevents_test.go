@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestDiffNodes(t *testing.T) {
+	f := newLiveFeed()
+	before := []models.Node{
+		{ID: "a", Name: "stay", IsEnabled: true},
+		{ID: "b", Name: "down", IsEnabled: true},
+		{ID: "c", Name: "up"},
+		{ID: "d", Name: "ban", IsEnabled: true},
+		{ID: "e", Name: "unban", IsEnabled: true, IsBanned: true},
+		{ID: "f", Name: "gone", IsEnabled: true},
+	}
+	if changes := f.diffNodes(before); len(changes) != 0 {
+		t.Fatalf("第一次轮询只记录状态，实际为 %v", changes)
+	}
+
+	after := []models.Node{
+		{ID: "a", Name: "stay", IsEnabled: true},
+		{ID: "b", Name: "down", DownReason: "timeout"},
+		{ID: "c", Name: "up", IsEnabled: true},
+		{ID: "d", Name: "ban", IsEnabled: true, IsBanned: true, BanReason: "abuse"},
+		{ID: "e", Name: "unban", IsEnabled: true},
+		{ID: "g", Name: "new", IsEnabled: true},
+	}
+	got := make(map[string]models.NodeStatusChange)
+	for _, change := range f.diffNodes(after) {
+		got[change.ID+":"+change.Type] = change
+	}
+	want := map[string]string{
+		"b:offline":  "timeout",
+		"c:online":   "",
+		"d:banned":   "abuse",
+		"e:unbanned": "",
+		"g:added":    "",
+		"f:removed":  "",
+	}
+	if len(got) != len(want) {
+		t.Errorf("状态变化应为 %v，实际为 %v", want, got)
+	}
+	for key, reason := range want {
+		change, ok := got[key]
+		if !ok {
+			t.Errorf("缺少状态变化 %s", key)
+			continue
+		}
+		if change.Reason != reason {
+			t.Errorf("%s 的原因应为 %q，实际为 %q", key, reason, change.Reason)
+		}
+	}
+
+	// 状态没有变化时不产生事件
+	if changes := f.diffNodes(after); len(changes) != 0 {
+		t.Errorf("状态没有变化时不应产生事件，实际为 %v", changes)
+	}
+}
+
+// readEvent 读取下一条 SSE 消息，返回事件类型和数据
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var kind, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("读取事件失败: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && (kind != "" || data != ""):
+			return kind, data
+		case strings.HasPrefix(line, "event: "):
+			kind = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case strings.HasPrefix(line, "retry: "):
+			kind = "retry"
+		}
+	}
+}
+
+// subscriberCount 返回当前的订阅者数量
+func (f *liveFeed) subscriberCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers)
+}
+
+func waitSubscribers(t *testing.T, f *liveFeed, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for f.subscriberCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("订阅者数量应为 %d，实际为 %d", n, f.subscriberCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEventsStreamAndClientDisconnect(t *testing.T) {
+	// 不启动轮询，由测试直接发布事件
+	s := NewWeb(0, models.DefaultConfig())
+	s.live = newLiveFeed()
+	s.live.latest[EventDashboard] = liveEvent{Type: EventDashboard, Data: []byte(`{"cached":true}`)}
+	server := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type 应为 text/event-stream，实际为 %q", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	if kind, _ := readEvent(t, r); kind != "retry" {
+		t.Fatalf("第一条消息应为重连间隔，实际为 %q", kind)
+	}
+	// 新连接立即收到已有的最新数据
+	if kind, data := readEvent(t, r); kind != EventDashboard || data != `{"cached":true}` {
+		t.Fatalf("应先收到缓存的仪表盘数据，实际为 %s %s", kind, data)
+	}
+
+	waitSubscribers(t, s.live, 1)
+	s.live.mu.Lock()
+	s.live.publishLocked(liveEvent{Type: EventNodes, Data: []byte(`{"nodes":[]}`)})
+	s.live.mu.Unlock()
+	if kind, data := readEvent(t, r); kind != EventNodes || data != `{"nodes":[]}` {
+		t.Fatalf("应收到推送的节点事件，实际为 %s %s", kind, data)
+	}
+
+	// 浏览器断开后取消订阅
+	cancel()
+	waitSubscribers(t, s.live, 0)
+}
+
+func TestShutdownEndsEventStreams(t *testing.T) {
+	s := NewWeb(0, models.DefaultConfig())
+	s.live = newLiveFeed()
+	server := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer server.Close()
+	liveCtx, liveCancel := context.WithCancel(context.Background())
+	s.server, s.liveCancel = server.Config, liveCancel
+	s.running = true
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	readEvent(t, r)
+	waitSubscribers(t, s.live, 1)
+
+	// SSE 长连接不会自行结束，Shutdown 需要先断开订阅，否则会等到超时
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown 应在断开 SSE 连接后正常返回: %v", err)
+	}
+	if liveCtx.Err() == nil {
+		t.Error("Shutdown 应停止后台轮询")
+	}
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Error("Shutdown 后 SSE 连接应结束")
+	}
+	events, _ := s.live.subscribe()
+	if _, ok := <-events; ok {
+		t.Error("关闭后的订阅应立即结束")
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/utils"
+)
+
+const requestIDHeader = utils.RequestIDHeader
+
+// Middleware 包装 http.Handler
+type Middleware func(http.Handler) http.Handler
+
+// chainMiddleware 按顺序组合中间件，第一个中间件位于最外层
+func chainMiddleware(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+type requestInfoContextKey struct{}
+
+// requestInfo 保存请求 ID 和认证后的用户名，供外层的访问日志读取
+type requestInfo struct {
+	id   string
+	user string
+}
+
+// RequestIDFromContext 返回当前请求的 ID
+func RequestIDFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// setRequestUser 记录当前请求的用户，由认证中间件调用
+func setRequestUser(ctx context.Context, user string) {
+	if info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo); ok {
+		info.user = user
+	}
+}
+
+func requestUserFromContext(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoContextKey{}).(*requestInfo); ok {
+		return info.user
+	}
+	return ""
+}
+
+// validRequestID 只接受长度合适且由字母数字和 -_ 组成的外部请求 ID
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDMiddleware 为每个请求分配 ID，沿用客户端传入的 X-Request-ID
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			var err error
+			if id, err = randomToken(8); err != nil {
+				id = fmt.Sprintf("%x", time.Now().UnixNano())
+			}
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestInfoContextKey{}, &requestInfo{id: id})))
+	})
+}
+
+// statusRecorder 记录响应状态码和大小
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(data)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush 支持流式响应
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// loggingMiddleware 在调试模式下记录每个请求
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		utils.DebugLog(1, "[Web] %s %s %d %dB %v (%s)",
+			r.Method, r.URL.Path, rec.status, rec.bytes, time.Since(start), RequestIDFromContext(r.Context()))
+	})
+}
+
+// recoveryMiddleware 捕获处理函数中的 panic，返回 500 而不是中断连接
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				utils.DebugLog(0, "[Web] 处理 %s %s 时发生错误 (%s): %v\n%s",
+					r.Method, r.URL.Path, RequestIDFromContext(r.Context()), err, debug.Stack())
+				wrapResponse(w, http.StatusInternalServerError, utils.T("web.internal_error"), nil)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accessLogEntry 定义一条结构化访问日志
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId"`
+	Remote    string    `json:"remote"`
+	User      string    `json:"user,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query,omitempty"`
+	Proto     string    `json:"proto"`
+	Status    int       `json:"status"`
+	Bytes     int       `json:"bytes"`
+	Latency   float64   `json:"latency"` // 毫秒
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+}
+
+// combined 按 Apache combined 格式输出，末尾附加请求 ID 和耗时
+func (e accessLogEntry) combined() string {
+	user, size, target, referer, agent := "-", "-", e.Path, e.Referer, e.UserAgent
+	if e.User != "" {
+		user = strings.ReplaceAll(e.User, " ", "_")
+	}
+	if e.Bytes > 0 {
+		size = strconv.Itoa(e.Bytes)
+	}
+	if e.Query != "" {
+		target += "?" + e.Query
+	}
+	if referer == "" {
+		referer = "-"
+	}
+	if agent == "" {
+		agent = "-"
+	}
+	return fmt.Sprintf("%s - %s [%s] %q %d %s %q %q %s %.3f\n",
+		e.Remote, user, e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		e.Method+" "+target+" "+e.Proto, e.Status, size, referer, agent, e.RequestID, e.Latency)
+}
+
+// sensitiveQueryKeys 访问日志中需要隐去取值的查询参数
+var sensitiveQueryKeys = map[string]bool{
+	"token":        true,
+	"access_token": true,
+	"password":     true,
+	"secret":       true,
+}
+
+// redactQuery 隐去查询字符串中敏感参数的取值，保留参数顺序和其余内容
+func redactQuery(query string) string {
+	if query == "" {
+		return query
+	}
+	parts := strings.Split(query, "&")
+	for i, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(key); err == nil && sensitiveQueryKeys[strings.ToLower(name)] {
+			parts[i] = key + "=***"
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// redactReferer 隐去 Referer 查询字符串中的敏感参数
+func redactReferer(referer string) string {
+	base, query, ok := strings.Cut(referer, "?")
+	if !ok {
+		return referer
+	}
+	return base + "?" + redactQuery(query)
+}
+
+// accessLogMiddleware 记录每个请求，format 为 json 或 combined
+func accessLogMiddleware(out io.Writer, format string) Middleware {
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			remote := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(remote); err == nil {
+				remote = host
+			}
+			entry := accessLogEntry{
+				Time:      start,
+				RequestID: RequestIDFromContext(r.Context()),
+				Remote:    remote,
+				User:      requestUserFromContext(r.Context()),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Query:     redactQuery(r.URL.RawQuery),
+				Proto:     r.Proto,
+				Status:    rec.status,
+				Bytes:     rec.bytes,
+				Latency:   float64(time.Since(start).Microseconds()) / 1000,
+				Referer:   redactReferer(r.Referer()),
+				UserAgent: r.UserAgent(),
+			}
+
+			var line []byte
+			if format == models.AccessLogCombined {
+				line = []byte(entry.combined())
+			} else {
+				line, _ = json.Marshal(entry)
+				line = append(line, '\n')
+			}
+			mu.Lock()
+			out.Write(line)
+			mu.Unlock()
+		})
+	}
+}
+
+// corsMiddleware 允许列表中的来源跨域调用 API，并直接响应预检请求
+// 跨域调用需要使用访问令牌，会话 Cookie 不会随跨域请求发送
+func corsMiddleware(origins []string) Middleware {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimRight(origin, "/")] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") || !(allowed["*"] || allowed[origin]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Add("Vary", "Origin")
+			header.Set("Access-Control-Expose-Headers", requestIDHeader)
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE")
+				header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, "+requestIDHeader)
+				header.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
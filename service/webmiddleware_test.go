@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+func TestAccessLogRedactsToken(t *testing.T) {
+	for _, format := range []string{models.AccessLogJSON, models.AccessLogCombined} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			handler := accessLogMiddleware(&out, format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/nodes?page=2&token=s3cret&Access_Token=abc", nil)
+			req.Header.Set("Referer", "http://127.0.0.1:8080/?token=s3cret")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			line := out.String()
+			if strings.Contains(line, "s3cret") || strings.Contains(line, "abc") {
+				t.Fatalf("访问日志泄露了令牌: %s", line)
+			}
+			if !strings.Contains(line, "page=2") {
+				t.Fatalf("其他查询参数应保留: %s", line)
+			}
+			if format == models.AccessLogJSON {
+				var entry accessLogEntry
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatal(err)
+				}
+				if entry.Query != "page=2&token=***&Access_Token=***" {
+					t.Fatalf("Query = %q", entry.Query)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	reached := false
+	handler := corsMiddleware([]string{"https://grafana.example.com/"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	do := func(method, path, origin string, preflight bool) *httptest.ResponseRecorder {
+		reached = false
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// 允许的来源 (配置中的末尾斜杠被忽略)
+	rec := do(http.MethodGet, "/api/nodes", "https://grafana.example.com", false)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://grafana.example.com" || !reached {
+		t.Errorf("允许的来源应返回 Access-Control-Allow-Origin 并继续处理，实际为 %q", got)
+	}
+	if rec.Header().Get("Access-Control-Expose-Headers") != requestIDHeader {
+		t.Error("应允许浏览器读取请求 ID 响应头")
+	}
+
+	// 不允许的来源和非 API 路径不返回跨域响应头，请求照常处理
+	for _, req := range []struct{ path, origin string }{
+		{"/api/nodes", "https://evil.example.com"},
+		{"/index.html", "https://grafana.example.com"},
+	} {
+		rec := do(http.MethodGet, req.path, req.origin, false)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" || !reached {
+			t.Errorf("%s 来自 %s 时不应返回跨域响应头，实际为 %q", req.path, req.origin, got)
+		}
+	}
+
+	// 预检请求直接返回 204，不进入后续处理
+	rec = do(http.MethodOptions, "/api/nodes/abc", "https://grafana.example.com", true)
+	if rec.Code != http.StatusNoContent || reached {
+		t.Errorf("预检请求应直接返回 204，实际为 %d", rec.Code)
+	}
+	if !strings.Contains(rec.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch) ||
+		!strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("预检响应缺少允许的方法或请求头: %v", rec.Header())
+	}
+	rec = do(http.MethodOptions, "/api/nodes/abc", "https://evil.example.com", true)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" || !reached {
+		t.Error("不允许的来源的预检请求不应返回跨域响应头")
+	}
+
+	// "*" 允许任意来源
+	handler = corsMiddleware([]string{"*"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	if rec := do(http.MethodGet, "/api/nodes", "https://any.example.com", false); rec.Header().Get("Access-Control-Allow-Origin") != "https://any.example.com" {
+		t.Error("配置为 * 时应允许任意来源")
+	}
+}
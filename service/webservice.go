@@ -1,14 +1,23 @@
 package service
 
 import (
+	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
@@ -18,58 +27,471 @@ import (
 //go:embed web/dist
 var webContent embed.FS
 
+// 端口被占用时依次尝试的后续端口数量，仍失败时由系统分配
+const portFallbackAttempts = 10
+
 type WebService struct {
-	port int
+	port         int
+	config       *models.Config
+	auth         *WebAuthService
+	portFallback bool
+
+	mu       sync.Mutex
+	server   *http.Server
+	redirect *http.Server // HTTP 到 HTTPS 的跳转服务
+	url      string
+	running  bool
+	done     chan error // 服务器退出后收到退出原因，正常关闭时为 nil
+
+	// 服务端轮询和 SSE 推送
+	live       *liveFeed
+	liveCancel context.CancelFunc
+
+	// daemon 模式下提供任务状态，并且不自动打开浏览器
+	daemonStatus func() models.DaemonStatus
+
+	// 无界面模式只提供 API，不包含前端页面，不打开浏览器，访问日志输出到标准输出
+	headless  bool
+	health    *HealthService
+	accessLog *utils.RotatingFile
 }
 
-func NewWeb(port int) *WebService {
+func NewWeb(port int, config *models.Config) *WebService {
 	return &WebService{
-		port: port,
+		port:   port,
+		config: config,
+		health: NewHealth(),
 	}
 }
 
-// StartServer 启动 Web 服务器
+// StartServer 启动 Web 服务器，监听失败时直接返回错误
 func (s *WebService) StartServer() error {
-	// API 路由
-	http.HandleFunc("/api/nodes", s.handleGetNodes)
-	http.HandleFunc("/api/dashboard", s.handleGetDashboard)
-	http.HandleFunc("/api/user", s.handleGetUser)
-	http.HandleFunc("/api/nodes/rank", s.handleGetNodeRank)
-	http.HandleFunc("/api/nodes/", func(w http.ResponseWriter, r *http.Request) {
-		switch {
-		case strings.HasSuffix(r.URL.Path, "/reset-secret"):
-			s.handleResetSecret(w, r)
-		case strings.HasSuffix(r.URL.Path, "/sponsor"):
-			s.handleUpdateNode(w, r)
-		default:
-			s.handleUpdateNode(w, r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return fmt.Errorf(utils.T("web.already_running"), s.url)
+	}
+	// 服务器自行退出后没有调用 Shutdown 时，先释放上一次启动的后台任务和访问日志
+	if s.server != nil {
+		s.release()
+	}
+
+	host, err := CheckListenAddress(s.config.Web)
+	if err != nil {
+		return err
+	}
+	if s.auth, err = NewWebAuth(s.config.Web); err != nil {
+		return err
+	}
+
+	handler, err := s.routes()
+	if err != nil {
+		return err
+	}
+	started := false
+	defer func() {
+		if !started {
+			s.closeAccessLog()
 		}
-	})
+	}()
 
-	// 静态文件服务
-	fsys, err := fs.Sub(webContent, "web/dist")
+	tlsConfig, err := s.tlsConfig(host)
 	if err != nil {
 		return err
 	}
-	http.Handle("/", http.FileServer(http.FS(fsys)))
 
-	// 启动服务器
-	serverURL := fmt.Sprintf("http://localhost:%d", s.port)
-	fmt.Printf("Web 服务器已启动: %s\n", serverURL)
+	listener, err := s.listen(host)
+	if err != nil {
+		return err
+	}
+	s.port = listener.Addr().(*net.TCPAddr).Port
+
+	// HTTP 跳转端口与主端口一起启动，任一失败都不启动服务器
+	var redirect *http.Server
+	if tlsConfig != nil && s.config.Web.TLS.RedirectPort > 0 {
+		redirectListener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(s.config.Web.TLS.RedirectPort)))
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf(utils.T("web.redirect_listen_failed"), s.config.Web.TLS.RedirectPort, err)
+		}
+		redirect = &http.Server{Handler: httpsRedirectHandler(s.port)}
+		go func() {
+			if err := redirect.Serve(redirectListener); err != nil && err != http.ErrServerClosed {
+				fmt.Println(utils.ColorText(utils.RoleError, utils.T("web.redirect_exited", err)))
+			}
+		}()
+	}
 
-	// 在新的 goroutine 中启动服务器
+	displayHost := host
+	if ip := net.ParseIP(host); isLoopbackHost(host) || (ip != nil && ip.IsUnspecified()) {
+		displayHost = "localhost"
+	}
+	if !isLoopbackHost(host) {
+		fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("web.exposed_warning")))
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	s.url = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(displayHost, strconv.Itoa(s.port)))
+	fmt.Printf(utils.T("web.started"), s.url)
+	fmt.Printf(utils.T("web.access_token"), s.auth.Token())
+
+	// 在新的 goroutine 中处理请求
+	server := &http.Server{Handler: handler, TLSConfig: tlsConfig}
+	s.server = server
+	s.redirect = redirect
+	s.running = true
+	started = true
+	done := make(chan error, 1)
+	s.done = done
+
+	liveCtx, liveCancel := context.WithCancel(context.Background())
+	s.live = newLiveFeed()
+	s.liveCancel = liveCancel
+	go s.live.run(liveCtx)
+	// 就绪检查只在 serve 和 daemon 中定期执行，交互界面中由 /api/ready/details 按需检查
+	if s.headless || s.daemonStatus != nil {
+		go s.health.run(liveCtx)
+	}
 	go func() {
-		if err := http.ListenAndServe(fmt.Sprintf(":%d", s.port), nil); err != nil {
-			fmt.Printf("服务器启动失败: %v\n", err)
+		var err error
+		if tlsConfig != nil {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		s.mu.Lock()
+		if s.server == server {
+			s.running = false
+		}
+		s.mu.Unlock()
+		if err == http.ErrServerClosed {
+			err = nil
 		}
+		if err != nil {
+			fmt.Println(utils.ColorText(utils.RoleError, utils.T("web.exited", err)))
+		}
+		done <- err
+		close(done)
 	}()
 
-	// 自动打开浏览器
-	time.Sleep(100 * time.Millisecond)
-	if err := s.openBrowser(serverURL); err != nil {
-		fmt.Printf("无法自动打开浏览器，请手动访问: %s\n", serverURL)
+	if s.daemonStatus != nil || s.headless {
+		return nil
+	}
+
+	// 自动打开浏览器，访问令牌放在 URL 片段中以便直接登录。
+	// 浏览器不会把片段发给服务器，令牌不会出现在访问日志和 Referer 中
+	if err := s.openBrowser(s.url + "/login#token=" + s.auth.Token()); err != nil {
+		fmt.Printf(utils.T("web.open_manually"), s.url)
+	}
+
+	return nil
+}
+
+// listen 监听端口，开启端口回退时在端口被占用后尝试其他端口
+func (s *WebService) listen(host string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(s.port)))
+	if err == nil || !s.portFallback {
+		if err != nil {
+			return nil, fmt.Errorf(utils.T("web.listen_failed"), s.port, err)
+		}
+		return listener, nil
+	}
+
+	firstErr := err
+	ports := make([]int, 0, portFallbackAttempts+1)
+	for i := 1; i <= portFallbackAttempts; i++ {
+		ports = append(ports, s.port+i)
+	}
+	ports = append(ports, 0)
+	for _, port := range ports {
+		if port > 65535 {
+			continue
+		}
+		if listener, err = net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port))); err == nil {
+			fmt.Println(utils.ColorText(utils.RoleWarn, utils.T("web.port_fallback", s.port, listener.Addr().(*net.TCPAddr).Port)))
+			return listener, nil
+		}
+	}
+	return nil, fmt.Errorf(utils.T("web.listen_failed"), s.port, firstErr)
+}
+
+// tlsConfig 根据配置加载证书，未启用 HTTPS 时返回 nil
+// 未指定证书时在数据目录下生成并保存自签名证书
+func (s *WebService) tlsConfig(host string) (*tls.Config, error) {
+	cfg := s.config.Web.TLS
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var cert tls.Certificate
+	var err error
+	switch {
+	case cfg.CertFile != "" && cfg.KeyFile != "":
+		if cert, err = tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile); err != nil {
+			return nil, fmt.Errorf(utils.T("web.load_cert_failed"), err)
+		}
+	case cfg.CertFile != "" || cfg.KeyFile != "":
+		return nil, errors.New(utils.T("web.cert_pair"))
+	default:
+		hosts := cfg.Hosts
+		if len(hosts) == 0 {
+			hosts = []string{"localhost", "127.0.0.1", "::1"}
+			if ip := net.ParseIP(host); !isLoopbackHost(host) && (ip == nil || !ip.IsUnspecified()) {
+				hosts = append(hosts, host)
+			}
+		}
+		if cert, err = utils.LoadOrCreateSelfSigned(filepath.Join(s.config.DataDir, "tls"), hosts); err != nil {
+			return nil, err
+		}
+		fmt.Printf(utils.T("web.fingerprint"), utils.CertFingerprint(cert))
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// httpsRedirectHandler 将 HTTP 请求跳转到同一主机的 HTTPS 端口
+func httpsRedirectHandler(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		target := "https://" + net.JoinHostPort(host, strconv.Itoa(port)) + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+// hstsMiddleware 通过 HTTPS 访问时要求浏览器之后只使用 HTTPS
+func hstsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetHeadless 设置是否以无界面模式运行
+func (s *WebService) SetHeadless(enabled bool) {
+	s.headless = enabled
+}
+
+// SetPortFallback 设置端口被占用时是否自动选择其他端口
+func (s *WebService) SetPortFallback(enabled bool) {
+	s.portFallback = enabled
+}
+
+// Running 返回服务器是否正在运行
+func (s *WebService) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Done 返回最近一次启动的服务器退出时关闭的 channel，服务器异常退出时先收到错误
+func (s *WebService) Done() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// URL 返回服务器地址
+func (s *WebService) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.url
+}
+
+// webRoute 定义一个 API 路由，OpenAPI 文档检查也使用这张表
+type webRoute struct {
+	Pattern    string // 方法和路径，如 "GET /api/nodes/{id}"
+	Capability string // 需要的权限，为空时由处理函数自行校验
+	Handler    http.HandlerFunc
+}
+
+// apiRoutes 返回所有 API 路由
+func (s *WebService) apiRoutes() []webRoute {
+	return []webRoute{
+		// 登录相关路由
+		{"POST /api/auth/login", "", s.handleLogin},
+		{"POST /api/auth/logout", "", s.handleLogout},
+		{"GET /api/auth/session", "", s.handleGetSession},
+		{"GET /api/openapi.json", "", handleOpenAPISpec},
+		{"GET /api/health", "", s.handleHealth},
+		{"GET /api/ready", "", s.handleReady},
+		{"GET /api/ready/details", models.CapView, s.handleReadyDetails},
+
+		// API 路由，按角色权限访问
+		{"GET /api/dashboard", models.CapView, s.handleGetDashboard},
+		{"GET /api/user", models.CapView, s.handleGetUser},
+		{"GET /api/uptime", models.CapView, s.handleGetUptime},
+		{"GET /api/events", models.CapView, s.handleEvents},
+		{"GET /api/daemon/status", models.CapView, s.handleGetDaemonStatus},
+		{"GET /api/nodes", models.CapView, s.handleGetNodes},
+		{"GET /api/nodes/rank", models.CapView, s.handleGetNodeRank},
+		{"GET /api/nodes/{id}", models.CapView, s.handleGetNode},
+		{"PATCH /api/nodes/{id}", models.CapNodeUpdate, s.handleUpdateNode},
+		{"PATCH /api/nodes/{id}/sponsor", models.CapNodeUpdate, s.handleUpdateNodeSponsor},
+		{"POST /api/nodes/{id}/reset-secret", models.CapNodeResetSecret, s.handleResetSecret},
+		{"GET /api/users", models.CapUserManage, s.handleListUsers},
+		{"POST /api/users", models.CapUserManage, s.handleCreateUser},
+		{"PATCH /api/users/{username}", models.CapUserManage, s.handleUpdateUser},
+		{"DELETE /api/users/{username}", models.CapUserManage, s.handleDeleteUser},
+	}
+}
+
+// routes 创建路由和中间件，每次启动都使用新的 ServeMux
+func (s *WebService) routes() (http.Handler, error) {
+	mux := http.NewServeMux()
+
+	for _, route := range s.apiRoutes() {
+		handler := route.Handler
+		if route.Capability != "" {
+			handler = s.auth.Require(route.Capability, handler)
+		}
+		mux.HandleFunc(route.Pattern, handler)
+	}
+
+	// API 文档页面
+	mux.HandleFunc("GET /docs", handleAPIDocs)
+
+	if s.headless {
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			wrapResponse(w, http.StatusNotFound, "Not found", nil)
+		})
+	} else {
+		// 静态文件服务
+		fsys, err := fs.Sub(webContent, "web/dist")
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle("GET /", spaHandler(fsys))
+	}
+
+	middlewares := []Middleware{
+		requestIDMiddleware,
+		loggingMiddleware,
 	}
+	accessLog, err := s.accessLogWriter()
+	if err != nil {
+		return nil, err
+	}
+	if accessLog != nil {
+		middlewares = append(middlewares, accessLogMiddleware(accessLog, s.config.Web.AccessLog.Format))
+	}
+	middlewares = append(middlewares, recoveryMiddleware)
+	if len(s.config.Web.CORS) > 0 {
+		middlewares = append(middlewares, corsMiddleware(s.config.Web.CORS))
+	}
+	if s.config.Web.TLS.Enabled && s.config.Web.TLS.HSTS {
+		middlewares = append(middlewares, hstsMiddleware)
+	}
+	middlewares = append(middlewares, s.auth.Middleware)
+	return chainMiddleware(mux, middlewares...), nil
+}
+
+// accessLogWriter 返回访问日志的输出位置，配置了文件时写入按大小轮转的文件，
+// 否则只在无界面模式下输出到标准输出
+func (s *WebService) accessLogWriter() (io.Writer, error) {
+	cfg := s.config.Web.AccessLog
+	switch cfg.Format {
+	case "", models.AccessLogJSON, models.AccessLogCombined:
+	default:
+		return nil, fmt.Errorf(utils.T("web.unknown_log_format"), cfg.Format)
+	}
+
+	if cfg.File != "" {
+		file, err := utils.NewRotatingFile(cfg.File, int64(cfg.MaxSizeMB)*1024*1024, cfg.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		s.accessLog = file
+		return file, nil
+	}
+	if s.headless {
+		return os.Stdout, nil
+	}
+	return nil, nil
+}
+
+// release 停止已经退出的服务器留下的后台任务并关闭访问日志，调用方需持有 s.mu
+func (s *WebService) release() {
+	s.liveCancel()
+	s.live.close()
+	if s.redirect != nil {
+		s.redirect.Close()
+	}
+	s.server.Close()
+	s.closeAccessLog()
+	s.server, s.redirect, s.liveCancel = nil, nil, nil
+}
+
+func (s *WebService) closeAccessLog() {
+	if s.accessLog != nil {
+		s.accessLog.Close()
+		s.accessLog = nil
+	}
+}
+
+// spaHandler 提供前端静态文件，前端路由 (如 /login) 回退到 index.html
+func spaHandler(fsys fs.FS) http.Handler {
+	fileServer := http.FileServer(http.FS(fsys))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			wrapResponse(w, http.StatusNotFound, "Not found", nil)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name != "" {
+			if _, err := fs.Stat(fsys, name); err != nil {
+				r = r.Clone(r.Context())
+				r.URL.Path = "/"
+			}
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// SetDaemonMode 以 daemon 模式运行，status 用于 /api/daemon/status
+func (s *WebService) SetDaemonMode(status func() models.DaemonStatus) {
+	s.daemonStatus = status
+}
 
+// Shutdown 停止 Web 服务器，等待进行中的请求完成，ctx 超时后强制关闭连接
+func (s *WebService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	server, redirect := s.server, s.redirect
+	live, liveCancel := s.live, s.liveCancel
+	s.server, s.redirect, s.liveCancel = nil, nil, nil
+	s.running = false
+	s.mu.Unlock()
+
+	// 没有启动或已经关闭。服务器自行退出时 s.server 仍然保留，下面照常停止后台轮询并关闭访问日志
+	if server == nil {
+		return nil
+	}
+	// 先断开 SSE 连接，否则 Shutdown 会一直等待这些长连接
+	liveCancel()
+	live.close()
+	if redirect != nil {
+		redirect.Close()
+	}
+	err := server.Shutdown(ctx)
+	if err != nil {
+		server.Close()
+	}
+	s.mu.Lock()
+	s.closeAccessLog()
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf(utils.T("web.shutdown_timeout"), err)
+	}
 	return nil
 }
 
@@ -95,134 +517,272 @@ func wrapResponse(w http.ResponseWriter, code int, msg string, data interface{})
 	} else {
 		resp = models.ResponseError(code, msg)
 	}
+	writeResponse(w, code, resp)
+}
+
+// writeResponse 输出 JSON 响应，出错时附带请求中间件分配的请求 ID
+func writeResponse(w http.ResponseWriter, code int, resp models.APIResponse) {
+	if code != http.StatusOK {
+		resp.RequestID = w.Header().Get(requestIDHeader)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(resp)
 }
 
+// 登录，支持账号密码或访问令牌
+func (s *WebService) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var loginData struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Token    string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&loginData); err != nil {
+		wrapResponse(w, http.StatusBadRequest, "Invalid request data", nil)
+		return
+	}
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	id, session, err := s.auth.Login(ip, loginData.Username, loginData.Password, loginData.Token)
+	if errors.Is(err, ErrLoginThrottled) {
+		wrapResponse(w, http.StatusTooManyRequests, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		utils.DebugLog(1, "[Web API] 登录失败 (%s): %v", r.RemoteAddr, err)
+		// 登录失败时延迟响应，降低暴力破解速度
+		time.Sleep(500 * time.Millisecond)
+		wrapResponse(w, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	s.auth.SetSessionCookie(w, r, id, session)
+	wrapResponse(w, http.StatusOK, "success", session)
+}
+
+func (s *WebService) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if id, _, ok := s.auth.Session(r); ok {
+		s.auth.Logout(id)
+	}
+	s.auth.ClearSessionCookie(w)
+	wrapResponse(w, http.StatusOK, "success", nil)
+}
+
+// 返回当前会话，前端用于判断是否需要登录并获取 CSRF 令牌
+func (s *WebService) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	_, session, ok := s.auth.Session(r)
+	if !ok {
+		wrapResponse(w, http.StatusUnauthorized, utils.T("web.login_required"), nil)
+		return
+	}
+	wrapResponse(w, http.StatusOK, "success", session)
+}
+
+// webUserInfo 定义账号管理接口返回的账号信息，不包含密码哈希
+type webUserInfo struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type webUserData struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// 账号列表
+func (s *WebService) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	users := s.auth.Users()
+	list := make([]webUserInfo, 0, len(users))
+	for _, user := range users {
+		list = append(list, webUserInfo{Username: user.Username, Role: user.EffectiveRole()})
+	}
+	wrapResponse(w, http.StatusOK, "success", list)
+}
+
+// 新建账号
+func (s *WebService) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var userData webUserData
+	if err := json.NewDecoder(r.Body).Decode(&userData); err != nil {
+		wrapResponse(w, http.StatusBadRequest, "Invalid request data", nil)
+		return
+	}
+	if err := s.auth.SaveUser(userData.Username, userData.Password, userData.Role, true); err != nil {
+		wrapResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	wrapResponse(w, http.StatusOK, "success", nil)
+}
+
+// 修改账号的密码或角色
+func (s *WebService) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	var userData webUserData
+	if err := json.NewDecoder(r.Body).Decode(&userData); err != nil {
+		wrapResponse(w, http.StatusBadRequest, "Invalid request data", nil)
+		return
+	}
+	if err := s.auth.SaveUser(r.PathValue("username"), userData.Password, userData.Role, false); err != nil {
+		wrapResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	wrapResponse(w, http.StatusOK, "success", nil)
+}
+
+// 删除账号
+func (s *WebService) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	if session, ok := SessionFromContext(r.Context()); ok && session.Username == username {
+		wrapResponse(w, http.StatusBadRequest, utils.T("web.delete_self"), nil)
+		return
+	}
+	if err := s.auth.RemoveUser(username); err != nil {
+		wrapResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	wrapResponse(w, http.StatusOK, "success", nil)
+}
+
 // API 处理函数
 func (s *WebService) handleGetNodes(w http.ResponseWriter, r *http.Request) {
-	utils.DebugLog(1, "[Web API] GET /api/nodes - 获取节点列表")
-	start := time.Now()
+	if data, ok := s.live.cached(EventNodes); ok {
+		wrapResponse(w, 200, "success", data)
+		return
+	}
 
-	nodeService := NewNode()
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
 	nodes, err := nodeService.GetNodeList()
 	if err != nil {
-		utils.DebugLog(1, "[Web API] 获取节点列表失败: %v", err)
 		wrapResponse(w, 500, err.Error(), nil)
 		return
 	}
 
-	duration := time.Since(start)
-	utils.DebugLog(1, "[Web API] 成功获取 %d 个节点，耗时: %v", len(nodes), duration)
 	wrapResponse(w, 200, "success", nodes)
 }
 
 func (s *WebService) handleGetDashboard(w http.ResponseWriter, r *http.Request) {
-	utils.DebugLog(1, "[Web API] GET /api/dashboard - 获取仪表盘数据")
-	start := time.Now()
+	if data, ok := s.live.cached(EventDashboard); ok {
+		wrapResponse(w, 200, "success", data)
+		return
+	}
 
-	dashboardService := NewDashboard()
+	dashboardService := NewDashboard().WithRequestID(RequestIDFromContext(r.Context()))
 	dashboard, err := dashboardService.GetDashboard()
 	if err != nil {
-		utils.DebugLog(1, "[Web API] 获取仪表盘数据失败: %v", err)
 		wrapResponse(w, 500, err.Error(), nil)
 		return
 	}
 
-	duration := time.Since(start)
-	utils.DebugLog(1, "[Web API] 成功获取仪表盘数据，耗时: %v", duration)
 	wrapResponse(w, 200, "success", dashboard)
 }
 
 func (s *WebService) handleGetUser(w http.ResponseWriter, r *http.Request) {
-	utils.DebugLog(1, "[Web API] GET /api/user - 获取用户信息")
-	start := time.Now()
-
-	authService := NewAuth()
+	authService := NewAuth().WithRequestID(RequestIDFromContext(r.Context()))
 	user, err := authService.GetUserProfile()
 	if err != nil {
-		utils.DebugLog(1, "[Web API] 获取用户信息失败: %v", err)
 		wrapResponse(w, 500, err.Error(), nil)
 		return
 	}
 
-	duration := time.Since(start)
-	utils.DebugLog(1, "[Web API] 成功获取用户信息，耗时: %v", duration)
 	wrapResponse(w, 200, "success", user)
 }
 
-// 添加新的 API 处理函数
-func (s *WebService) handleUpdateNode(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "PATCH" {
-		wrapResponse(w, 405, "Method not allowed", nil)
+// 获取单个节点详情及派生指标
+func (s *WebService) handleGetNode(w http.ResponseWriter, r *http.Request) {
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
+	node, err := nodeService.GetNodeDetail(r.PathValue("id"))
+	if err != nil {
+		wrapResponse(w, nodeErrorStatus(err), err.Error(), nil)
 		return
 	}
 
-	nodeID := strings.TrimPrefix(r.URL.Path, "/api/nodes/")
-	nodeID = strings.TrimSuffix(nodeID, "/sponsor")
+	// 排行榜获取失败时只缺少排行相关字段
+	var ranks []NodeMetricRank
+	if data, ok := s.live.cached(EventRank); ok {
+		json.Unmarshal(data, &ranks)
+	} else if ranks, err = nodeService.GetNodeMetricRank(r.Context()); err != nil {
+		utils.DebugLog(1, "[Web API] 获取排行榜失败 (%s): %v", RequestIDFromContext(r.Context()), err)
+	}
+
+	wrapResponse(w, http.StatusOK, "success", nodeService.BuildNodeDetail(node, ranks, time.Now()))
+}
 
+// 更新节点基本信息
+func (s *WebService) handleUpdateNode(w http.ResponseWriter, r *http.Request) {
 	var updateData struct {
-		Name      string             `json:"name,omitempty"`
-		Bandwidth int                `json:"bandwidth,omitempty"`
-		Sponsor   models.NodeSponsor `json:"sponsor,omitempty"`
+		Name      string `json:"name,omitempty"`
+		Bandwidth int    `json:"bandwidth,omitempty"`
 	}
-
 	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		wrapResponse(w, 400, "Invalid request data", nil)
+		wrapResponse(w, http.StatusBadRequest, "Invalid request data", nil)
 		return
 	}
 
-	nodeService := NewNode()
-	if strings.HasSuffix(r.URL.Path, "/sponsor") {
-		// 更新赞助商信息
-		err := nodeService.UpdateNodeSponsor(nodeID, updateData.Sponsor)
-		if err != nil {
-			wrapResponse(w, 500, err.Error(), nil)
-			return
-		}
-	} else {
-		// 更新节点基本信息
-		err := nodeService.UpdateNode(nodeID, NodeUpdateInfo{
-			Name:      updateData.Name,
-			Bandwidth: updateData.Bandwidth,
-		})
-		if err != nil {
-			wrapResponse(w, 500, err.Error(), nil)
-			return
-		}
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
+	err := nodeService.UpdateNode(r.PathValue("id"), NodeUpdateInfo{
+		Name:      updateData.Name,
+		Bandwidth: updateData.Bandwidth,
+	})
+	if err != nil {
+		wrapResponse(w, nodeErrorStatus(err), err.Error(), nil)
+		return
 	}
+	s.live.invalidate(EventNodes)
 
-	wrapResponse(w, 200, "success", nil)
+	wrapResponse(w, http.StatusOK, "success", nil)
 }
 
-func (s *WebService) handleResetSecret(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "PATCH" {
-		wrapResponse(w, 405, "Method not allowed", nil)
+// 更新赞助商信息
+func (s *WebService) handleUpdateNodeSponsor(w http.ResponseWriter, r *http.Request) {
+	var updateData struct {
+		Sponsor models.NodeSponsor `json:"sponsor"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		wrapResponse(w, http.StatusBadRequest, "Invalid request data", nil)
 		return
 	}
 
-	nodeID := strings.TrimPrefix(r.URL.Path, "/api/nodes/")
-	nodeID = strings.TrimSuffix(nodeID, "/reset-secret")
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
+	if err := nodeService.UpdateNodeSponsor(r.PathValue("id"), updateData.Sponsor); err != nil {
+		wrapResponse(w, nodeErrorStatus(err), err.Error(), nil)
+		return
+	}
+	s.live.invalidate(EventNodes)
+
+	wrapResponse(w, http.StatusOK, "success", nil)
+}
 
-	nodeService := NewNode()
-	secret, err := nodeService.ResetNodeSecret(nodeID)
+func (s *WebService) handleResetSecret(w http.ResponseWriter, r *http.Request) {
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
+	secret, err := nodeService.ResetNodeSecret(r.PathValue("id"))
 	if err != nil {
-		wrapResponse(w, 500, err.Error(), nil)
+		wrapResponse(w, nodeErrorStatus(err), err.Error(), nil)
 		return
 	}
 
-	wrapResponse(w, 200, "success", map[string]string{"secret": secret})
+	wrapResponse(w, http.StatusOK, "success", map[string]string{"secret": secret})
+}
+
+// nodeErrorStatus 返回节点接口出错时的状态码：ID 不合法为 400，节点不存在为 404
+func nodeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidNodeID):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNodeNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
 }
 
 // 添加排行榜处理函数
 func (s *WebService) handleGetNodeRank(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		wrapResponse(w, http.StatusMethodNotAllowed, "Method not allowed", nil)
+	if data, ok := s.live.cached(EventRank); ok {
+		wrapResponse(w, http.StatusOK, "success", data)
 		return
 	}
-
-	nodeService := NewNode()
+	nodeService := NewNode().WithRequestID(RequestIDFromContext(r.Context()))
 	ranks, err := nodeService.GetNodeMetricRank(r.Context())
 	if err != nil {
 		wrapResponse(w, http.StatusInternalServerError, err.Error(), nil)
@@ -231,3 +791,92 @@ func (s *WebService) handleGetNodeRank(w http.ResponseWriter, r *http.Request) {
 
 	wrapResponse(w, http.StatusOK, "success", ranks)
 }
+
+// uptimeEntry 定义可用性接口返回的单个节点数据
+type uptimeEntry struct {
+	ID        string                `json:"id"`
+	Name      string                `json:"name"`
+	IsEnabled bool                  `json:"isEnabled"`
+	FirstSeen time.Time             `json:"firstSeen"`
+	LastPoll  time.Time             `json:"lastPoll"`
+	Reports   []models.UptimeReport `json:"reports"`
+	Outages   []models.Outage       `json:"outages"`
+}
+
+// 可用性统计与离线时间线
+func (s *WebService) handleGetUptime(w http.ResponseWriter, r *http.Request) {
+	uptimeService := NewUptime(s.config.DataDir)
+	records, err := uptimeService.Load()
+	if err != nil {
+		wrapResponse(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	now := time.Now()
+	since := now.Add(-UptimeWindows[len(UptimeWindows)-1].Duration)
+	entries := make([]uptimeEntry, 0, len(records))
+	for _, rec := range sortedRecords(records) {
+		entry := uptimeEntry{
+			ID:        rec.ID,
+			Name:      rec.Name,
+			IsEnabled: rec.IsEnabled,
+			FirstSeen: rec.FirstSeen,
+			LastPoll:  rec.LastPoll,
+			Outages:   []models.Outage{},
+		}
+		for _, window := range UptimeWindows {
+			entry.Reports = append(entry.Reports, uptimeService.Report(rec, window, now))
+		}
+		// 只返回最近 30 天内的离线记录
+		for _, outage := range rec.Outages {
+			if outage.End.IsZero() || outage.End.After(since) {
+				entry.Outages = append(entry.Outages, outage)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	wrapResponse(w, http.StatusOK, "success", entries)
+}
+
+// daemon 任务状态
+func (s *WebService) handleGetDaemonStatus(w http.ResponseWriter, r *http.Request) {
+	if s.daemonStatus == nil {
+		wrapResponse(w, http.StatusNotFound, utils.T("web.not_daemon"), nil)
+		return
+	}
+
+	wrapResponse(w, http.StatusOK, "success", s.daemonStatus())
+}
+
+// 存活检查，进程能处理请求即返回成功
+func (s *WebService) handleHealth(w http.ResponseWriter, r *http.Request) {
+	wrapResponse(w, http.StatusOK, "success", map[string]string{"status": "ok"})
+}
+
+// 就绪检查，不需要登录，只返回后台最近一次检查是否通过，不请求上游，也不返回错误信息。
+// 尚未完成第一次检查、上游不可用或登录凭据失效时返回 503
+func (s *WebService) handleReady(w http.ResponseWriter, r *http.Request) {
+	status := models.HealthStatus{}
+	if last := s.health.Last(); last != nil {
+		status.Ready = last.Ready
+		status.CheckedAt = last.CheckedAt
+	}
+	writeReadyStatus(w, status)
+}
+
+// 就绪检查的详细结果，包括各项检查的耗时和错误信息
+func (s *WebService) handleReadyDetails(w http.ResponseWriter, r *http.Request) {
+	writeReadyStatus(w, s.health.Check())
+}
+
+func writeReadyStatus(w http.ResponseWriter, status models.HealthStatus) {
+	if status.Ready {
+		wrapResponse(w, http.StatusOK, "success", status)
+		return
+	}
+
+	resp := models.ResponseError(http.StatusServiceUnavailable, utils.T("web.not_ready"))
+	resp.Data = status
+	writeResponse(w, http.StatusServiceUnavailable, resp)
+}
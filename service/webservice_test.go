@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MoTeam-org/OpenBMCLAPI-API-Go/models"
+)
+
+// nodeRouteMux 只注册节点相关的路由，不经过鉴权，用于检查路径参数的处理
+func nodeRouteMux(s *WebService) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, route := range s.apiRoutes() {
+		mux.HandleFunc(route.Pattern, route.Handler)
+	}
+	return mux
+}
+
+func TestGetNodeRejectsEncodedPath(t *testing.T) {
+	mux := nodeRouteMux(NewWeb(0, models.DefaultConfig()))
+
+	// PathValue 会解码 %2F 和 %3F，拼进上游地址后会访问其他管理接口
+	for _, path := range []string{
+		"/api/nodes/..%2F..%2Fuser",
+		"/api/nodes/abc%3Fx=1",
+		"/api/nodes/abc%2Freset-secret",
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s 应返回 400，实际为 %d: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestNodeWritesRejectEncodedPath(t *testing.T) {
+	mux := nodeRouteMux(NewWeb(0, models.DefaultConfig()))
+
+	for _, req := range []struct{ method, path, body string }{
+		{http.MethodPatch, "/api/nodes/..%2F..%2Fuser", `{"name": "x"}`},
+		{http.MethodPatch, "/api/nodes/abc%3Fx=1/sponsor", `{"sponsor": {"name": "x"}}`},
+		{http.MethodPost, "/api/nodes/..%2Fother/reset-secret", ""},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(req.method, req.path, strings.NewReader(req.body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s 应返回 400，实际为 %d: %s", req.method, req.path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestGetUptimeUsesConfiguredDataDir(t *testing.T) {
+	cfg := models.DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	node := models.Node{ID: "5f0c1e2d3a4b5c6d7e8f9012", Name: "uptime-node", IsEnabled: true}
+	if err := NewUptime(cfg.DataDir).Record([]models.Node{node}, time.Now(), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// 配置中的数据目录与当前目录下的 config.json 无关
+	rec := httptest.NewRecorder()
+	NewWeb(0, cfg).handleGetUptime(rec, httptest.NewRequest(http.MethodGet, "/api/uptime", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), node.Name) {
+		t.Errorf("应返回配置的数据目录中的记录，实际为 %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestClusterURL(t *testing.T) {
+	got, err := clusterURL("5f0c1e2d3a4b5c6d7e8f9012", "/reset-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://bd.bangbang93.com/openbmclapi/mgmt/cluster/5f0c1e2d3a4b5c6d7e8f9012/reset-secret"; got != want {
+		t.Errorf("clusterURL = %q，应为 %q", got, want)
+	}
+	for _, id := range []string{"", "../user", "abc?x=1", "abc#x", "a b", "abc%2F"} {
+		if _, err := clusterURL(id, ""); err != ErrInvalidNodeID {
+			t.Errorf("clusterURL(%q) 应返回 ErrInvalidNodeID，实际为 %v", id, err)
+		}
+	}
+}
+
+// newLifecycleServer 返回无界面模式的 Web 服务，就绪检查不请求上游，数据目录在临时目录中
+func newLifecycleServer(t *testing.T, port int) *WebService {
+	t.Helper()
+	cfg := models.DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	s := NewWeb(port, cfg)
+	s.SetHeadless(true)
+	s.health.checks = nil
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+// occupyPort 占用一个本机端口，测试结束后释放
+func occupyPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestStartServerReturnsBindError(t *testing.T) {
+	port := occupyPort(t)
+	s := newLifecycleServer(t, port)
+
+	// 端口被占用且没有开启端口回退时同步返回错误
+	if err := s.StartServer(); err == nil || !strings.Contains(err.Error(), strconv.Itoa(port)) {
+		t.Fatalf("端口被占用时应返回监听错误，实际为 %v", err)
+	}
+	if s.Running() || s.Done() != nil {
+		t.Fatal("监听失败后不应处于运行状态")
+	}
+
+	// 换一个端口后可以正常启动
+	s.port = 0
+	if err := s.StartServer(); err != nil {
+		t.Fatalf("监听失败后应可以重新启动: %v", err)
+	}
+	if !s.Running() {
+		t.Fatal("启动后应处于运行状态")
+	}
+}
+
+func TestStartServerFallsBackToFreePort(t *testing.T) {
+	port := occupyPort(t)
+	s := newLifecycleServer(t, port)
+	s.SetPortFallback(true)
+
+	if err := s.StartServer(); err != nil {
+		t.Fatalf("开启端口回退时应自动选择其他端口: %v", err)
+	}
+	if s.port == port || !strings.HasSuffix(s.URL(), ":"+strconv.Itoa(s.port)) {
+		t.Fatalf("应使用其他端口并在地址中显示，实际端口为 %d，地址为 %s", s.port, s.URL())
+	}
+	resp, err := http.Get(s.URL() + "/api/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("回退后的端口应可以访问，实际为 %d", resp.StatusCode)
+	}
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	s := newLifecycleServer(t, 0)
+	if err := s.StartServer(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 登录请求的请求体还没有发送完，处理函数在读取请求体时等待
+	conn, err := net.Dial("tcp", strings.TrimPrefix(s.URL(), "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	body := `{"token": "wrong"}`
+	fmt.Fprintf(conn, "POST /api/auth/login HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", len(body), body[:5])
+	time.Sleep(50 * time.Millisecond)
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- s.Shutdown(context.Background()) }()
+	select {
+	case err := <-shutdown:
+		t.Fatalf("请求完成前 Shutdown 不应返回，实际返回 %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	io.WriteString(conn, body[5:])
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("进行中的请求应正常完成: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("错误的令牌应返回 401，实际为 %d", resp.StatusCode)
+	}
+	select {
+	case err := <-shutdown:
+		if err != nil {
+			t.Fatalf("Shutdown 应正常返回: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("请求完成后 Shutdown 应返回")
+	}
+	if err := <-s.Done(); err != nil {
+		t.Errorf("正常关闭时退出原因应为 nil，实际为 %v", err)
+	}
+}
+
+func TestStartServerAfterServerExited(t *testing.T) {
+	s := newLifecycleServer(t, 0)
+	if err := s.StartServer(); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	server, live, done := s.server, s.live, s.done
+	s.mu.Unlock()
+
+	// 不经过 Shutdown 直接关闭，相当于服务器自行退出
+	server.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务器退出后 Done 应收到通知")
+	}
+	if s.Running() {
+		t.Fatal("服务器退出后不应处于运行状态")
+	}
+
+	if err := s.StartServer(); err != nil {
+		t.Fatalf("服务器自行退出后应可以重新启动: %v", err)
+	}
+	live.mu.Lock()
+	closed := live.closed
+	live.mu.Unlock()
+	if !closed {
+		t.Error("重新启动时应停止上一次的后台轮询")
+	}
+	resp, err := http.Get(s.URL() + "/api/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s.Running() || s.server != nil || s.accessLog != nil {
+		t.Error("Shutdown 后应释放服务器和访问日志")
+	}
+}
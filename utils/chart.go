@@ -0,0 +1,338 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ChartMode 图表的绘制方式
+type ChartMode int
+
+const (
+	// ChartLine 使用盲文点阵绘制折线，每个字符 2x4 个点，可以叠加多条数据线
+	ChartLine ChartMode = iota
+	// ChartBar 使用八分之一方块绘制柱状图，只绘制第一条数据线
+	ChartBar
+)
+
+// ChartSeries 图表中的一条数据线
+type ChartSeries struct {
+	Name   string
+	Values []float64
+	Color  Role
+}
+
+// Chart 自动缩放坐标轴的终端图表
+type Chart struct {
+	Series []ChartSeries
+	Labels []string             // x 轴标签，与数据点一一对应，放不下时自动跳过
+	Height int                  // 绘图区行数，默认 8
+	Width  int                  // 包括 y 轴刻度在内的总宽度，默认使用终端宽度
+	Mode   ChartMode            // 绘制方式
+	Format func(float64) string // y 轴刻度格式，默认按数值大小保留小数
+}
+
+// NiceScale 计算包含 [low, high] 的坐标轴范围和刻度间隔，刻度为 1、2、5 乘以 10 的整数次幂
+func NiceScale(low, high float64, ticks int) (float64, float64, float64) {
+	if ticks < 2 {
+		ticks = 2
+	}
+	if math.IsNaN(low) || math.IsNaN(high) || math.IsInf(low, 0) || math.IsInf(high, 0) {
+		return 0, 1, 1
+	}
+	if high < low {
+		low, high = high, low
+	}
+	if high == low {
+		// 所有数据相同时在数据上方留出空间，全为 0 时使用 0~1
+		if high == 0 {
+			return 0, 1, 0.5
+		}
+		if low > 0 {
+			low = 0
+		} else {
+			high = 0
+		}
+	}
+
+	step := niceNumber((high - low) / float64(ticks-1))
+	return math.Floor(low/step) * step, math.Ceil(high/step) * step, step
+}
+
+// niceNumber 返回与 x 最接近的 1、2、5 乘以 10 的整数次幂
+func niceNumber(x float64) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	var nice float64
+	switch {
+	case f < 1.5:
+		nice = 1
+	case f < 3:
+		nice = 2
+	case f < 7:
+		nice = 5
+	default:
+		nice = 10
+	}
+	return nice * math.Pow(10, exp)
+}
+
+// defaultTickFormat 根据刻度间隔决定保留的小数位数
+func defaultTickFormat(step float64) func(float64) string {
+	decimals := 0
+	if step > 0 && step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	return func(v float64) string {
+		return fmt.Sprintf("%.*f", decimals, v)
+	}
+}
+
+// Render 返回图表的每一行，包括 y 轴、x 轴、时间标签以及多条数据线时的图例
+func (c Chart) Render() []string {
+	height := c.Height
+	if height <= 0 {
+		height = 8
+	}
+	width := c.Width
+	if width <= 0 {
+		width = TerminalWidth()
+	}
+
+	points := 0
+	low, high := 0.0, math.Inf(-1)
+	for _, s := range c.Series {
+		points = max(points, len(s.Values))
+		for _, v := range s.Values {
+			if !isFinite(v) {
+				continue
+			}
+			low = math.Min(low, v)
+			high = math.Max(high, v)
+		}
+	}
+	if points == 0 {
+		return []string{T("chart.no_data")}
+	}
+
+	// 刻度之间至少隔一行，行数较少时减少刻度
+	var axisLow, axisHigh, step float64
+	for ticks := min(height, 5); ticks >= 2; ticks-- {
+		axisLow, axisHigh, step = NiceScale(low, high, ticks)
+		if int(math.Round((axisHigh-axisLow)/step))+1 <= height/2+1 {
+			break
+		}
+	}
+	format := c.Format
+	if format == nil {
+		format = defaultTickFormat(step)
+	}
+
+	// 刻度标签放在离刻度值最近的行上
+	tickRows := make(map[int]string)
+	labelWidth := 0
+	for k := 0; k <= int(math.Round((axisHigh-axisLow)/step)); k++ {
+		v := axisLow + float64(k)*step
+		row := int(math.Round((axisHigh - v) / (axisHigh - axisLow) * float64(height-1)))
+		tickRows[row] = format(v)
+		labelWidth = max(labelWidth, StringWidth(tickRows[row]))
+	}
+
+	plotWidth := max(width-labelWidth-2, 4)
+	var cells [][]string
+	if c.Mode == ChartBar {
+		cells = c.plotBars(plotWidth, height, points, axisLow, axisHigh)
+	} else {
+		cells = c.plotLines(plotWidth, height, points, axisLow, axisHigh)
+	}
+
+	lines := make([]string, 0, height+3)
+	for row := 0; row < height; row++ {
+		axis := "│"
+		label := ""
+		if text, ok := tickRows[row]; ok {
+			axis = "┤"
+			label = text
+		}
+		lines = append(lines, PadWidth(label, labelWidth, false)+" "+axis+strings.Join(cells[row], ""))
+	}
+	lines = append(lines, strings.Repeat(" ", labelWidth+1)+"└"+strings.Repeat("─", plotWidth))
+	if len(c.Labels) > 0 {
+		lines = append(lines, strings.Repeat(" ", labelWidth+2)+c.renderLabels(plotWidth, points))
+	}
+	if len(c.Series) > 1 {
+		var legend []string
+		for _, s := range c.Series {
+			legend = append(legend, ColorText(s.Color, "■")+" "+s.Name)
+		}
+		lines = append(lines, strings.Repeat(" ", labelWidth+2)+strings.Join(legend, "   "))
+	}
+	return lines
+}
+
+// isFinite 判断数据点能否绘制，NaN 和 Inf 表示缺失的数据，绘制时跳过
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// pointColumn 返回第 i 个数据点在 [0, columns) 范围内的位置
+func pointColumn(i, points, columns int) int {
+	if points <= 1 {
+		return 0
+	}
+	return int(math.Round(float64(i) * float64(columns-1) / float64(points-1)))
+}
+
+// plotLines 在盲文点阵上按顺序绘制各条数据线，相邻数据点之间连线
+func (c Chart) plotLines(width, height, points int, low, high float64) [][]string {
+	dotsX, dotsY := width*2, height*4
+	bits := make([][]rune, height)
+	colors := make([][]Role, height)
+	for row := range bits {
+		bits[row] = make([]rune, width)
+		colors[row] = make([]Role, width)
+	}
+	// 盲文字符中每个点对应的位
+	dotBits := [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+	set := func(x, y int, color Role) {
+		if x < 0 || x >= dotsX || y < 0 || y >= dotsY {
+			return
+		}
+		row, col := y/4, x/2
+		bits[row][col] |= dotBits[y%4][x%2]
+		colors[row][col] = color
+	}
+	toY := func(v float64) int {
+		return int(math.Round((high - v) / (high - low) * float64(dotsY-1)))
+	}
+
+	for _, s := range c.Series {
+		prevX, prevY := -1, 0
+		for i, v := range s.Values {
+			// 缺失的数据点断开折线
+			if !isFinite(v) {
+				prevX = -1
+				continue
+			}
+			x, y := pointColumn(i, points, dotsX), toY(v)
+			if prevX < 0 {
+				set(x, y, s.Color)
+			} else {
+				drawLine(prevX, prevY, x, y, func(x, y int) { set(x, y, s.Color) })
+			}
+			prevX, prevY = x, y
+		}
+	}
+
+	cells := make([][]string, height)
+	for row := range cells {
+		cells[row] = make([]string, width)
+		for col := range cells[row] {
+			if bits[row][col] == 0 {
+				cells[row][col] = " "
+				continue
+			}
+			cells[row][col] = ColorText(colors[row][col], string(0x2800+bits[row][col]))
+		}
+	}
+	return cells
+}
+
+// drawLine 用 Bresenham 算法连接两个点
+func drawLine(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// plotBars 每个数据点占相同宽度的柱子，柱顶用八分之一方块提高精度
+func (c Chart) plotBars(width, height, points int, low, high float64) [][]string {
+	blocks := []rune(" ▁▂▃▄▅▆▇█")
+	cells := make([][]string, height)
+	for row := range cells {
+		cells[row] = make([]string, width)
+		for col := range cells[row] {
+			cells[row][col] = " "
+		}
+	}
+	s := c.Series[0]
+	for col := 0; col < width; col++ {
+		i := col * points / width
+		if i >= len(s.Values) || !isFinite(s.Values[i]) {
+			continue
+		}
+		// 柱子之间留一列空隙，数据点较多时不留
+		if width/points >= 3 && (col+1)*points/width != i {
+			continue
+		}
+		eighths := int(math.Round((s.Values[i] - low) / (high - low) * float64(height*8)))
+		for row := 0; row < height; row++ {
+			filled := eighths - (height-1-row)*8
+			switch {
+			case filled >= 8:
+				cells[row][col] = ColorText(s.Color, string(blocks[8]))
+			case filled > 0:
+				cells[row][col] = ColorText(s.Color, string(blocks[filled]))
+			}
+		}
+	}
+	return cells
+}
+
+// renderLabels 在数据点对应的位置放置 x 轴标签，与前一个标签重叠时跳过
+func (c Chart) renderLabels(width, points int) string {
+	line := []rune(strings.Repeat(" ", width))
+	next := 0
+	for i := 0; i < points && i < len(c.Labels); i++ {
+		label := []rune(c.Labels[i])
+		labelWidth := StringWidth(c.Labels[i])
+		var col int
+		if c.Mode == ChartBar {
+			col = (2*i + 1) * width / (2 * points)
+		} else {
+			col = pointColumn(i, points, width*2) / 2
+		}
+		col = min(max(col-labelWidth/2, 0), width-labelWidth)
+		if col < next || col < 0 {
+			continue
+		}
+		// 宽字符占两列，用 0 占位，最后去掉
+		pos := col
+		for _, r := range label {
+			line[pos] = r
+			for w := 1; w < RuneWidth(r); w++ {
+				line[pos+w] = 0
+			}
+			pos += RuneWidth(r)
+		}
+		next = col + labelWidth + 1
+	}
+	return strings.ReplaceAll(string(line), "\x00", "")
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// renderWithin 在 goroutine 中绘制图表，超时说明绘制陷入了死循环
+func renderWithin(t *testing.T, c Chart) []string {
+	t.Helper()
+	done := make(chan []string, 1)
+	go func() { done <- c.Render() }()
+	select {
+	case lines := <-done:
+		return lines
+	case <-time.After(5 * time.Second):
+		t.Fatal("Render 没有在 5 秒内返回")
+		return nil
+	}
+}
+
+func TestChartSkipsNonFiniteValues(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"nan", []float64{1, 2, nan, 4, 5}},
+		{"inf", []float64{1, inf, 3, math.Inf(-1), 5}},
+		{"leading nan", []float64{nan, nan, 3, 4}},
+		{"all nan", []float64{nan, nan, nan}},
+	}
+	modes := map[string]ChartMode{"line": ChartLine, "bar": ChartBar}
+	for modeName, mode := range modes {
+		for _, tt := range tests {
+			t.Run(modeName+"/"+tt.name, func(t *testing.T) {
+				lines := renderWithin(t, Chart{
+					Series: []ChartSeries{{Name: "a", Values: tt.values}},
+					Height: 6,
+					Width:  40,
+					Mode:   mode,
+				})
+				if len(lines) < 7 {
+					t.Fatalf("图表行数不足: %q", lines)
+				}
+				for _, line := range lines {
+					if strings.Contains(line, "NaN") || strings.Contains(line, "Inf") {
+						t.Fatalf("刻度不应包含 NaN 或 Inf: %q", lines)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestChartScaleIgnoresNonFinite(t *testing.T) {
+	lines := renderWithin(t, Chart{
+		Series: []ChartSeries{{Values: []float64{0, 10, math.Inf(1), 20}}},
+		Height: 8,
+		Width:  40,
+	})
+	// 最大刻度应按有限值 20 计算
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), "20") {
+		t.Fatalf("y 轴上限应为 20: %q", lines[0])
+	}
+}
+
+func TestSparklineNonFinite(t *testing.T) {
+	got := Sparkline([]float64{1, math.NaN(), 3, math.Inf(1)}, 0)
+	if got != "▁ █ " {
+		t.Fatalf("Sparkline = %q", got)
+	}
+}
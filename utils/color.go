@@ -1,37 +1,121 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// Role 颜色的语义角色，实际颜色由当前主题决定
+type Role string
 
-// ANSI 颜色转义码
 const (
-	Reset  = "\033[0m"
-	Red    = "\033[31m"
-	Green  = "\033[32m"
-	Yellow = "\033[33m"
-	Blue   = "\033[34m"
-	Purple = "\033[35m"
-	Cyan   = "\033[36m"
-	White  = "\033[37m"
-	Bold   = "\033[1m"
+	RoleOK        Role = "ok"        // 成功、在线
+	RoleWarn      Role = "warn"      // 警告、需要注意的提示
+	RoleError     Role = "error"     // 错误、离线
+	RoleLabel     Role = "label"     // 字段名
+	RoleValue     Role = "value"     // 字段值、数据
+	RoleTitle     Role = "title"     // 标题
+	RoleHeader    Role = "header"    // 表头
+	RoleInfo      Role = "info"      // 说明文字
+	RolePrompt    Role = "prompt"    // 输入提示
+	RoleMuted     Role = "muted"     // 次要文字
+	RoleSelected  Role = "selected"  // 全屏界面中选中的行
+	RoleHighlight Role = "highlight" // 发生变化的数值
 )
 
-// ColorText 为文本添加颜色
-func ColorText(color, text string) string {
-	return color + text + Reset
+// Roles 所有语义角色，用于校验主题配置
+var Roles = []Role{
+	RoleOK, RoleWarn, RoleError, RoleLabel, RoleValue, RoleTitle,
+	RoleHeader, RoleInfo, RolePrompt, RoleMuted, RoleSelected, RoleHighlight,
 }
 
-// PadString 返回固定宽度的字符串，考虑中文字符
-func PadString(str string, width int, alignLeft bool) string {
-	strRunes := []rune(str)
-	strWidth := len(strRunes)
+// 颜色模式
+const (
+	ColorAuto   = "auto"   // 输出到终端且没有设置 NO_COLOR 时使用颜色
+	ColorAlways = "always" // 总是输出颜色
+	ColorNever  = "never"  // 不输出颜色
+)
+
+const ansiReset = "\033[0m"
+
+var (
+	colorMode = ColorAuto
+	theme     = builtinThemes[DefaultTheme]
+	// palette 当前生效的样式，颜色关闭时为空，不输出任何 ANSI 序列
+	palette atomic.Pointer[map[Role]string]
+)
+
+func init() {
+	applyPalette()
+}
+
+// SetColorMode 设置颜色模式：auto、always 或 never
+func SetColorMode(mode string) error {
+	switch mode {
+	case "":
+		mode = ColorAuto
+	case ColorAuto, ColorAlways, ColorNever:
+	default:
+		return fmt.Errorf(T("color.invalid_mode"), mode)
+	}
+	colorMode = mode
+	applyPalette()
+	return nil
+}
 
-	if strWidth >= width {
-		return string(strRunes[:width])
+// ColorEnabled 判断当前是否输出颜色
+func ColorEnabled() bool {
+	switch colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
 	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return false
+	}
+	// 旧版 Windows 控制台无法开启 ANSI 支持时不输出颜色
+	return enableVirtualTerminal(os.Stdout)
+}
+
+func applyPalette() {
+	styles := map[Role]string{}
+	if ColorEnabled() {
+		styles = theme
+	}
+	palette.Store(&styles)
+}
+
+// Style 返回角色对应的 ANSI 序列，颜色关闭时为空
+func Style(role Role) string {
+	return (*palette.Load())[role]
+}
+
+// ColorText 按角色为文本添加颜色
+func ColorText(role Role, text string) string {
+	style := Style(role)
+	if style == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
 
-	padding := strings.Repeat(" ", width-strWidth)
-	if alignLeft {
-		return str + padding
+// MarkText 按角色为文本添加样式；颜色关闭时改用 open 和 close 包住文本，
+// 用于选中项、搜索匹配等不能只靠样式区分的内容
+func MarkText(role Role, text, open, close string) string {
+	if Style(role) == "" {
+		return open + text + close
 	}
-	return padding + str
+	return Style(role) + text + ansiReset
+}
+
+// PadString 返回固定显示宽度的字符串，中文按两列计算，超出时截断
+func PadString(str string, width int, alignLeft bool) string {
+	return PadWidth(str, width, alignLeft)
 }
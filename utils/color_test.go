@@ -0,0 +1,37 @@
+package utils
+
+import "testing"
+
+func TestColorNeverEmitsNoEscapes(t *testing.T) {
+	defer SetColorMode(ColorAuto)
+	if err := SetColorMode(ColorNever); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, role := range Roles {
+		if style := Style(role); style != "" {
+			t.Errorf("关闭颜色后 %s 不应输出样式，实际为 %q", role, style)
+		}
+		if text := ColorText(role, "x"); text != "x" {
+			t.Errorf("关闭颜色后 ColorText(%s) = %q", role, text)
+		}
+	}
+	if got := HighlightRunes("node-01", []int{0, 1, 5}, RoleHighlight); got != "[no]de-[0]1" {
+		t.Errorf("关闭颜色后匹配的字符应用 [] 标出，实际为 %q", got)
+	}
+	if got := MarkText(RoleHighlight, "12", "", "*"); got != "12*" {
+		t.Errorf("MarkText = %q，应为 %q", got, "12*")
+	}
+}
+
+func TestColorAlwaysUsesTheme(t *testing.T) {
+	defer SetColorMode(ColorAuto)
+	if err := SetColorMode(ColorAlways); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Style(RoleHighlight) + "n" + ansiReset + "ode"
+	if got := HighlightRunes("node", []int{0}, RoleHighlight); got != want {
+		t.Errorf("开启颜色时应使用主题样式标出匹配，实际为 %q，应为 %q", got, want)
+	}
+}
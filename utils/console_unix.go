@@ -0,0 +1,25 @@
+//go:build !windows
+
+package utils
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// enableVirtualTerminal 类 Unix 终端本身支持 ANSI 转义序列
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
+
+// waitInput 等待 f 可读，超时返回 false
+func waitInput(f *os.File, timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(f.Fd()), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, int(timeout.Milliseconds()))
+	if err == unix.EINTR {
+		return false, nil
+	}
+	return n > 0, err
+}
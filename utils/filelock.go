@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"time"
+)
+
+// LockFile 用锁文件实现互斥，同一进程内和不同进程之间都有效。锁被持有时每隔 100 毫秒重试一次，
+// 超过 timeout 仍未获得时返回错误。锁文件的格式和加锁方式与 PID 文件相同
+func LockFile(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		release, err := AcquirePidFile(path)
+		if err == nil || time.Now().After(deadline) {
+			return release, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
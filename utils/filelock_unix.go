@@ -0,0 +1,25 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile 对文件加非阻塞的独占锁，已被其他打开的文件持有时返回 errLocked。
+// 锁跟随打开的文件，进程退出时由系统释放
+func lockFile(f *os.File) error {
+	err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return errLocked
+	}
+	return err
+}
+
+// unlockFile 释放 lockFile 加的锁
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
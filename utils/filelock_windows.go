@@ -0,0 +1,31 @@
+//go:build windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange 是加锁的字节范围。Windows 的文件锁会阻止其他句柄读取被锁定的内容，
+// 因此锁定文件末尾之外的一个字节，不影响读取文件中的 PID
+var lockRange = windows.Overlapped{Offset: 0xFFFFFFFF, OffsetHigh: 0x7FFFFFFF}
+
+// lockFile 对文件加非阻塞的独占锁，已被其他句柄持有时返回 errLocked。
+// 锁跟随文件句柄，进程退出时由系统释放
+func lockFile(f *os.File) error {
+	ol := lockRange
+	err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+// unlockFile 释放 lockFile 加的锁
+func unlockFile(f *os.File) error {
+	ol := lockRange
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch 忽略大小写，按顺序在 text 中查找 pattern 的每个字符。匹配时返回得分和匹配到的字符下标（按 rune 计）；
+// 整段子串、靠前的位置、连续字符以及单词开头的字符得分更高
+func FuzzyMatch(pattern, text string) (int, []int, bool) {
+	p := lowerRunes(strings.TrimSpace(pattern))
+	t := lowerRunes(text)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	// 子串匹配优先，越靠前得分越高
+	if i := strings.Index(string(t), string(p)); i >= 0 {
+		start := len([]rune(string(t)[:i]))
+		positions := make([]int, len(p))
+		for k := range positions {
+			positions[k] = start + k
+		}
+		score := 1000 - start
+		if start == 0 {
+			score += 500
+		}
+		if len(p) == len(t) {
+			score += 500
+		}
+		return score, positions, true
+	}
+
+	score, last := 0, -1
+	positions := make([]int, 0, len(p))
+	for i, k := 0, 0; i < len(t) && k < len(p); i++ {
+		if t[i] != p[k] {
+			continue
+		}
+		score += 10
+		if last >= 0 && i == last+1 {
+			score += 15
+		} else if last >= 0 {
+			score -= min(i-last-1, 10)
+		}
+		if i == 0 || isWordBoundary(t[i-1]) {
+			score += 10
+		}
+		positions = append(positions, i)
+		last = i
+		k++
+	}
+	if len(positions) < len(p) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// lowerRunes 逐个字符转为小写，结果与原文的字符一一对应，
+// 匹配到的下标可以直接交给 HighlightRunes 标出原文中的字符
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func isWordBoundary(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// HighlightRunes 用 role 标出 text 中指定下标的字符，颜色关闭时用 [] 标出；text 不能包含颜色序列
+func HighlightRunes(text string, positions []int, role Role) string {
+	if len(positions) == 0 {
+		return text
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	flush := func() {
+		if len(run) > 0 {
+			b.WriteString(MarkText(role, string(run), "[", "]"))
+			run = run[:0]
+		}
+	}
+	for i, r := range []rune(text) {
+		if marked[i] {
+			run = append(run, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFuzzyMatchPositions(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		positions     []int
+		ok            bool
+	}{
+		{"", "anything", nil, true},
+		{"node", "Node-01", []int{0, 1, 2, 3}, true},
+		{"01", "node-01", []int{5, 6}, true},
+		{"bj", "北京 BJ-Telecom", []int{3, 4}, true},
+		{"nd1", "node-01", []int{0, 2, 6}, true},
+		{"电信", "北京电信", []int{2, 3}, true},
+		{"x", "İx", []int{1}, true}, // 下标按原文的字符计算
+		{"i", "İstanbul", []int{0}, true},
+		{"zz", "node-01", nil, false},
+		{"10", "node-01", nil, false}, // 必须按顺序出现
+	}
+	for _, tt := range tests {
+		_, positions, ok := FuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("FuzzyMatch(%q, %q) = %v %v，应为 %v %v", tt.pattern, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyMatchScoreOrder(t *testing.T) {
+	// 按得分从高到低：完全相同、前缀、靠前的子串、靠后的子串、单词开头的子序列、分散的子序列
+	texts := []string{"hk", "hk-node", "a-hk", "node-in-hk", "hello-kitty", "shake"}
+	scores := make([]int, len(texts))
+	for i, text := range texts {
+		score, _, ok := FuzzyMatch("hk", text)
+		if !ok {
+			t.Fatalf("%q 应匹配 hk", text)
+		}
+		scores[i] = score
+	}
+	if !sort.SliceIsSorted(scores, func(i, j int) bool { return scores[i] > scores[j] }) {
+		t.Errorf("得分顺序不正确: %v %v", texts, scores)
+	}
+	for i := 1; i < len(scores); i++ {
+		if scores[i] == scores[i-1] {
+			t.Errorf("%q 与 %q 得分相同: %d", texts[i-1], texts[i], scores[i])
+		}
+	}
+}
+
+func TestHighlightMatchedRunes(t *testing.T) {
+	defer SetColorMode(ColorAuto)
+	if err := SetColorMode(ColorNever); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct{ pattern, text, want string }{
+		{"x", "İx", "İ[x]"},
+		{"电信", "北京电信节点", "北京[电信]节点"},
+		{"nd1", "node-01", "[n]o[d]e-0[1]"},
+	} {
+		_, positions, _ := FuzzyMatch(tt.pattern, tt.text)
+		if got := HighlightRunes(tt.text, positions, RoleHighlight); got != tt.want {
+			t.Errorf("HighlightRunes(%q) = %q，应为 %q", tt.text, got, tt.want)
+		}
+	}
+}
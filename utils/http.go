@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
@@ -26,15 +28,15 @@ const (
 func (s RequestStatus) String() string {
 	switch s {
 	case Preparing:
-		return "准备请求中"
+		return T("status.preparing")
 	case Requesting:
-		return "正在请求中"
+		return T("status.requesting")
 	case Overtime:
-		return "请求超过预期"
+		return T("status.slow")
 	case Timeout:
-		return "请求超时"
+		return T("status.timeout")
 	default:
-		return "未知状态"
+		return T("status.unknown")
 	}
 }
 
@@ -45,9 +47,13 @@ type HTTPResponse struct {
 	Body       []byte
 }
 
+// RequestIDHeader 用于在 Web 请求和上游请求之间传递请求 ID
+const RequestIDHeader = "X-Request-ID"
+
 // HTTPClient 封装 HTTP 请求工具
 type HTTPClient struct {
-	client *http.Client
+	client    *http.Client
+	requestID string
 }
 
 // NewHTTPClient 创建新的 HTTP 客户端
@@ -57,6 +63,12 @@ func NewHTTPClient() *HTTPClient {
 	}
 }
 
+// WithRequestID 在发往上游的请求中携带请求 ID，便于关联日志
+func (c *HTTPClient) WithRequestID(id string) *HTTPClient {
+	c.requestID = id
+	return c
+}
+
 // showProgress 显示请求进度
 func showProgress(done chan bool, requestInfo string) {
 	status := Preparing
@@ -64,7 +76,7 @@ func showProgress(done chan bool, requestInfo string) {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
-	fmt.Printf("\n%s\n", ColorText(Blue, requestInfo))
+	fmt.Printf("\n%s\n", ColorText(RoleInfo, requestInfo))
 
 	for {
 		select {
@@ -84,35 +96,35 @@ func showProgress(done chan bool, requestInfo string) {
 			}
 
 			// 显示状态和耗时
-			statusColor := Yellow
+			statusColor := RoleWarn
 			switch status {
 			case Preparing:
-				statusColor = Cyan
+				statusColor = RoleValue
 			case Requesting:
-				statusColor = Yellow
+				statusColor = RoleWarn
 			case Overtime, Timeout:
-				statusColor = Red
+				statusColor = RoleError
 			}
 
 			fmt.Print(ColorText(statusColor, fmt.Sprintf("[%s] ", status)))
-			fmt.Printf(ColorText(Blue, "请求耗时：%.1f秒"), duration.Seconds())
+			fmt.Printf(ColorText(RoleInfo, T("http.elapsed")), duration.Seconds())
 
 			// 只在正常请求阶段显示预估时间
 			if status == Requesting {
 				remaining := 20.0 - duration.Seconds()
 				if remaining > 0 {
-					fmt.Printf(ColorText(Purple, " 预估剩余：%.1f秒"), remaining)
+					fmt.Printf(ColorText(RolePrompt, T("http.remaining")), remaining)
 				}
 			}
 
 			// 根据状态显示不同的附加信息
 			switch status {
 			case Preparing:
-				fmt.Print(ColorText(Cyan, " 正在初始化..."))
+				fmt.Print(ColorText(RoleValue, T("http.initializing")))
 			case Overtime:
-				fmt.Print(ColorText(Red, " 请求时间已超过预期，但仍在继续..."))
+				fmt.Print(ColorText(RoleError, T("http.slow")))
 			case Timeout:
-				fmt.Print(ColorText(Red, " 请求已超时"))
+				fmt.Print(ColorText(RoleError, T("http.timed_out")))
 			}
 		}
 	}
@@ -126,19 +138,22 @@ func (c *HTTPClient) doRequest(method, url string, body interface{}, cookies []m
 	if body != nil {
 		reqBody, err = json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("序列化请求数据失败: %v", err)
+			return nil, fmt.Errorf(T("http.marshal_failed"), err)
 		}
 	}
 
 	// 创建请求
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %v", err)
+		return nil, fmt.Errorf(T("common.create_request_failed"), err)
 	}
 
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "OpenBMCLAPI-Client/1.0")
+	if c.requestID != "" {
+		req.Header.Set(RequestIDHeader, c.requestID)
+	}
 
 	// 添加 cookie
 	if len(cookies) > 0 {
@@ -169,27 +184,27 @@ func (c *HTTPClient) doRequest(method, url string, body interface{}, cookies []m
 	resp, err := c.client.Do(req)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("请求超时")
+			return nil, errors.New(T("status.timeout"))
 		}
-		return nil, fmt.Errorf("请求失败: %v", err)
+		return nil, fmt.Errorf(T("common.request_failed"), err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %v", err)
+		return nil, fmt.Errorf(T("common.read_response_failed"), err)
 	}
 
 	// 计算请求耗时
 	duration := time.Since(start)
 
 	// 显示响应信息
-	statusColor := Green
+	statusColor := RoleOK
 	if resp.StatusCode >= 400 {
-		statusColor = Red
+		statusColor = RoleError
 	} else if resp.StatusCode >= 300 {
-		statusColor = Yellow
+		statusColor = RoleWarn
 	}
 
 	DebugLog(1, "[HTTP] %s %s %s (耗时: %v)",
@@ -237,21 +252,31 @@ func formatHeaders(headers http.Header) string {
 func getEndpointDescription(url string) string {
 	switch {
 	case strings.Contains(url, "/user"):
-		return "获取用户信息"
+		return T("endpoint.user_profile")
 	case strings.Contains(url, "/metric/dashboard"):
-		return "获取仪表盘数据"
+		return T("endpoint.dashboard")
 	case strings.Contains(url, "/mgmt/cluster/my"):
-		return "获取节点列表"
+		return T("endpoint.node_list")
 	case strings.Contains(url, "/reset-secret"):
-		return "重置节点密钥"
+		return T("endpoint.reset_secret")
 	case strings.Contains(url, "/mgmt/cluster/"):
 		if strings.Contains(url, "/sponsor") {
-			return "更新节点赞助商信息"
+			return T("endpoint.update_sponsor")
 		}
-		return "节点管理"
+		return T("endpoint.node_manage")
 	default:
-		return url
+		return RedactURL(url)
+	}
+}
+
+// RedactURL 只保留地址的协议和主机部分。机器人 webhook 的路径和查询参数中通常带有密钥，
+// 不能原样写入日志或错误信息
+func RedactURL(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
 	}
+	return u.Scheme + "://" + u.Host + "/***"
 }
 
 // DoGet 执行 GET 请求
@@ -0,0 +1,270 @@
+package utils
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// 支持的界面语言
+const (
+	LocaleZH = "zh-CN"
+	LocaleEN = "en"
+)
+
+// DefaultLocale 源语言，其他语言缺少的消息使用它
+const DefaultLocale = LocaleZH
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// message 一条消息，不区分单复数的消息只有 other
+type message struct {
+	One   string `json:"one,omitempty"`
+	Other string `json:"other"`
+}
+
+// catalog 一种语言的全部消息
+type catalog struct {
+	tag      string
+	messages map[string]message
+}
+
+var (
+	catalogs      = mustLoadCatalogs()
+	currentLocale atomic.Pointer[catalog]
+)
+
+func init() {
+	currentLocale.Store(catalogs[DefaultLocale])
+}
+
+func mustLoadCatalogs() map[string]*catalog {
+	loaded, err := loadCatalogs()
+	if err != nil {
+		panic(err)
+	}
+	return loaded
+}
+
+// loadCatalogs 读取内嵌的消息文件，值为字符串或包含 one/other 的对象
+func loadCatalogs() (map[string]*catalog, error) {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]*catalog)
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", file.Name(), err)
+		}
+
+		c := &catalog{tag: strings.TrimSuffix(file.Name(), ".json"), messages: make(map[string]message, len(raw))}
+		for key, value := range raw {
+			var m message
+			if err := json.Unmarshal(value, &m.Other); err != nil {
+				if err := json.Unmarshal(value, &m); err != nil {
+					return nil, fmt.Errorf("%s 中的 %s 格式错误: %v", file.Name(), key, err)
+				}
+			}
+			c.messages[key] = m
+		}
+		loaded[c.tag] = c
+	}
+	return loaded, nil
+}
+
+// Locales 返回支持的语言
+func Locales() []string {
+	tags := make([]string, 0, len(catalogs))
+	for tag := range catalogs {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// MatchLocale 把 zh_CN.UTF-8、en-US 之类的写法转换为支持的语言
+func MatchLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	switch {
+	case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+		return LocaleZH, true
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return LocaleEN, true
+	}
+	return "", false
+}
+
+// DetectLocale 按 LC_ALL、LC_MESSAGES、LANG 的顺序从环境变量推断语言，都无法识别时返回默认语言
+func DetectLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			if tag, ok := MatchLocale(value); ok {
+				return tag
+			}
+			// 按 POSIX 的规则，排在前面的变量设置后就不再看后面的
+			return DefaultLocale
+		}
+	}
+	return DefaultLocale
+}
+
+// SetLocale 设置界面语言
+func SetLocale(tag string) error {
+	matched, ok := MatchLocale(tag)
+	if !ok {
+		return fmt.Errorf(T("i18n.unknown_locale"), tag, strings.Join(Locales(), ", "))
+	}
+	currentLocale.Store(catalogs[matched])
+	return nil
+}
+
+// Locale 返回当前语言
+func Locale() string {
+	return currentLocale.Load().tag
+}
+
+// LocaleName 返回语言以自身书写的名称，用于让用户选择语言
+func LocaleName(tag string) string {
+	if c, ok := catalogs[tag]; ok {
+		if m, ok := c.messages["i18n.locale_name"]; ok {
+			return m.Other
+		}
+	}
+	return tag
+}
+
+// lookup 查找消息，当前语言缺少时使用默认语言，都没有时返回键名
+func lookup(key string) message {
+	if m, ok := currentLocale.Load().messages[key]; ok {
+		return m
+	}
+	if m, ok := catalogs[DefaultLocale].messages[key]; ok {
+		return m
+	}
+	return message{Other: key}
+}
+
+// T 返回当前语言的消息；带参数时按 fmt.Sprintf 格式化，不带参数时原样返回，可以再作为格式串使用
+func T(key string, args ...interface{}) string {
+	text := lookup(key).Other
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// TN 按数量 n 选择单复数形式，n 作为第一个格式化参数
+func TN(key string, n int, args ...interface{}) string {
+	m := lookup(key)
+	text := m.Other
+	if pluralOne(Locale(), n) && m.One != "" {
+		text = m.One
+	}
+	return fmt.Sprintf(text, append([]interface{}{n}, args...)...)
+}
+
+// messageError 在调用 Error 时才翻译的错误
+type messageError string
+
+func (e messageError) Error() string {
+	return T(string(e))
+}
+
+// NewError 返回一个按当前语言输出的错误，用于在选择语言之前就创建的包级错误
+func NewError(key string) error {
+	return messageError(key)
+}
+
+// pluralOne 判断 n 在该语言中是否使用单数形式，中文不区分单复数
+func pluralOne(tag string, n int) bool {
+	return tag == LocaleEN && n == 1
+}
+
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+// CheckCatalogs 检查所有语言的消息是否完整：每个键在所有语言中都存在、需要复数形式的语言提供了 one，
+// 并且各语言的格式化参数一致
+func CheckCatalogs() []string {
+	var problems []string
+	keys := make(map[string]bool)
+	for _, c := range catalogs {
+		for key := range c.messages {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	base := catalogs[DefaultLocale]
+	for _, tag := range Locales() {
+		c := catalogs[tag]
+		for _, key := range sorted {
+			m, ok := c.messages[key]
+			if !ok {
+				problems = append(problems, T("i18n.missing", tag, key))
+				continue
+			}
+			if m.Other == "" {
+				problems = append(problems, T("i18n.empty", tag, key))
+			}
+			ref, ok := base.messages[key]
+			if !ok || tag == DefaultLocale {
+				continue
+			}
+			// 复数消息在区分单复数的语言中需要两种形式
+			if ref.One != "" || m.One != "" {
+				if pluralOne(tag, 1) && m.One == "" {
+					problems = append(problems, T("i18n.missing_one", tag, key))
+				}
+			}
+			if !sameVerbs(ref.Other, m.Other) {
+				problems = append(problems, T("i18n.verbs_mismatch", tag, key, DefaultLocale))
+			}
+		}
+	}
+	return problems
+}
+
+// HasMessage 判断默认语言中是否有该键
+func HasMessage(key string) bool {
+	_, ok := catalogs[DefaultLocale].messages[key]
+	return ok
+}
+
+// sameVerbs 比较两个格式串中的格式化参数；译文用 %[n]v 调整了顺序时只比较参数种类
+func sameVerbs(a, b string) bool {
+	verbs := func(s string, sorted bool) string {
+		var list []string
+		for _, v := range formatVerb.FindAllString(s, -1) {
+			if v != "%%" {
+				list = append(list, v[len(v)-1:])
+			}
+		}
+		if sorted {
+			sort.Strings(list)
+		}
+		return strings.Join(list, "")
+	}
+	reordered := strings.Contains(a, "%[") || strings.Contains(b, "%[")
+	return verbs(a, reordered) == verbs(b, reordered)
+}
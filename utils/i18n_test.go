@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, problem := range CheckCatalogs() {
+		t.Error(problem)
+	}
+}
+
+func TestCheckCatalogsReportsProblems(t *testing.T) {
+	zh, en := catalogs[LocaleZH], catalogs[LocaleEN]
+	// 只有中文的消息；中文给出了复数形式但英文缺少 one
+	zh.messages["test.only_zh"] = message{Other: "仅中文"}
+	zh.messages["test.plural"] = message{One: "%d 个节点", Other: "%d 个节点"}
+	en.messages["test.plural"] = message{Other: "%d nodes"}
+	defer func() {
+		delete(zh.messages, "test.only_zh")
+		delete(zh.messages, "test.plural")
+		delete(en.messages, "test.plural")
+	}()
+
+	problems := strings.Join(CheckCatalogs(), "\n")
+	for _, key := range []string{"test.only_zh", "test.plural"} {
+		if !strings.Contains(problems, key) {
+			t.Errorf("应报告 %s，实际为:\n%s", key, problems)
+		}
+	}
+
+	en.messages["test.plural"] = message{One: "%d node", Other: "%d nodes"}
+	if problems := strings.Join(CheckCatalogs(), "\n"); strings.Contains(problems, "test.plural") {
+		t.Errorf("补上 one 后不应再报告 test.plural，实际为:\n%s", problems)
+	}
+}
+
+// messageKeyPattern 匹配源码中以字面量引用的消息键：T、TN、NewError 的参数，命令的 Usage、flag 的 Desc 以及模板中的 t
+var messageKeyPattern = regexp.MustCompile(`(?:\bTN?\(|NewError\(|(?:Usage|Desc):\s*|\{\{t )"([a-z0-9_]+(?:\.[a-z0-9_]+)+)"`)
+
+func TestSourceMessageKeysExist(t *testing.T) {
+	root := ".."
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "web" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range messageKeyPattern.FindAllSubmatchIndex(data, -1) {
+			key := string(data[m[2]:m[3]])
+			if !HasMessage(key) {
+				line := bytes.Count(data[:m[0]], []byte("\n")) + 1
+				t.Errorf("%s:%d: 消息键 %s 不存在", path, line, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
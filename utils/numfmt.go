@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numberFormat 一种语言的数字格式
+type numberFormat struct {
+	group   string      // 千位分隔符
+	decimal string      // 小数点
+	units   []countUnit // 大数的计数单位，从大到小；为空时只添加千位分隔符
+}
+
+// countUnit 计数单位，例如中文的万和亿
+type countUnit struct {
+	value  float64
+	suffix string
+}
+
+var numberFormats = map[string]numberFormat{
+	// 中文习惯按万、亿计数，例如 1.23亿
+	LocaleZH: {group: ",", decimal: ".", units: []countUnit{{1e8, "亿"}, {1e4, "万"}}},
+	LocaleEN: {group: ",", decimal: "."},
+}
+
+func currentNumberFormat() numberFormat {
+	if f, ok := numberFormats[Locale()]; ok {
+		return f
+	}
+	return numberFormats[DefaultLocale]
+}
+
+// FormatNumber 按当前语言格式化计数：中文达到一万时按万、亿计数并最多保留两位小数，其他语言添加千位分隔符
+func FormatNumber(n int64) string {
+	f := currentNumberFormat()
+	abs := math.Abs(float64(n))
+	for i, unit := range f.units {
+		// 用下一级单位保留两位小数后会进位到本单位时也使用本单位，避免出现 10,000万
+		rounds := i+1 < len(f.units) && math.Round(abs/f.units[i+1].value*100)/100 >= unit.value/f.units[i+1].value
+		if abs >= unit.value || rounds {
+			text := FormatFloat(float64(n)/unit.value, 2)
+			text = strings.TrimSuffix(strings.TrimRight(text, "0"), f.decimal)
+			return text + unit.suffix
+		}
+	}
+	return FormatFloat(float64(n), 0)
+}
+
+// FormatFloat 按当前语言格式化小数，整数部分添加千位分隔符
+func FormatFloat(v float64, decimals int) string {
+	f := currentNumberFormat()
+	text := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(text, ".")
+
+	var b strings.Builder
+	if v < 0 && text != strconv.FormatFloat(0, 'f', decimals, 64) {
+		b.WriteByte('-')
+	}
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(r)
+	}
+	if fraction != "" {
+		b.WriteString(f.decimal + fraction)
+	}
+	return b.String()
+}
+
+// FormatBytes 按 1024 进制把字节数转换为可读格式
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%s B", FormatNumber(bytes))
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%s %cB", FormatFloat(float64(bytes)/float64(div), 2), "KMGTPE"[exp])
+}
+
+// FormatBandwidth 格式化带宽，输入单位是 Mbps
+func FormatBandwidth(mbps float64) string {
+	if mbps < 1000 {
+		return FormatFloat(mbps, 2) + " Mbps"
+	}
+	return FormatFloat(mbps/1000, 2) + " Gbps"
+}
@@ -0,0 +1,30 @@
+package utils
+
+import "testing"
+
+func TestFormatNumberByLocale(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	tests := []struct {
+		n      int64
+		zh, en string
+	}{
+		{0, "0", "0"},
+		{9999, "9,999", "9,999"},
+		{12345, "1.23万", "12,345"},
+		{50000, "5万", "50,000"},
+		{99999999, "1亿", "99,999,999"},
+		{123456789, "1.23亿", "123,456,789"},
+		{-250000, "-25万", "-250,000"},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct{ locale, want string }{{LocaleZH, tt.zh}, {LocaleEN, tt.en}} {
+			if err := SetLocale(c.locale); err != nil {
+				t.Fatal(err)
+			}
+			if got := FormatNumber(tt.n); got != c.want {
+				t.Errorf("%s: FormatNumber(%d) = %q，应为 %q", c.locale, tt.n, got, c.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// errLocked 表示文件锁已被其他打开的文件持有
+var errLocked = errors.New("file is locked")
+
+// AcquirePidFile 打开 PID 文件并加独占锁作为实例锁，加锁成功后写入当前进程的 PID，返回释放函数。
+// 锁已被持有时返回错误，同一进程内重复获取同样失败；持有锁的进程退出后由系统释放锁，
+// 因此文件中残留的内容不影响下一次获取。释放时清空内容但保留文件，
+// 删除文件会让已经打开旧文件的等待者和新建文件的进程同时持有锁
+func AcquirePidFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf(T("pid.mkdir_failed"), err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf(T("pid.create_failed"), err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if !errors.Is(err, errLocked) {
+			return nil, fmt.Errorf(T("pid.create_failed"), err)
+		}
+		// 持有者可能还没写入 PID，这时只报告文件被锁定
+		data, _ := ioutil.ReadFile(path)
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid > 0 {
+			return nil, fmt.Errorf(T("pid.running"), pid, path)
+		}
+		return nil, fmt.Errorf(T("pid.locked"), path)
+	}
+
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	if err != nil {
+		unlockFile(file)
+		file.Close()
+		return nil, fmt.Errorf(T("pid.write_failed"), err)
+	}
+
+	return func() {
+		file.Truncate(0)
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcquirePidFileConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "daemon.pid")
+
+	// 两个获取者同时竞争，只有一个成功
+	var wg sync.WaitGroup
+	releases := make(chan func(), 2)
+	errs := make(chan error, 2)
+	start := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			release, err := AcquirePidFile(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			releases <- release
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(releases)
+	close(errs)
+	if len(releases) != 1 || len(errs) != 1 {
+		t.Fatalf("应只有一个获取者成功，实际成功 %d 个，失败 %d 个", len(releases), len(errs))
+	}
+	if err := <-errs; !strings.Contains(err.Error(), strconv.Itoa(os.Getpid())) {
+		t.Errorf("失败时应报告持有者的 PID: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PID 文件内容应为当前进程的 PID，实际为 %q", data)
+	}
+
+	// 释放后可以再次获取
+	(<-releases)()
+	release, err := AcquirePidFile(path)
+	if err != nil {
+		t.Fatalf("释放后应可以再次获取: %v", err)
+	}
+	release()
+}
+
+func TestAcquirePidFileIgnoresLeftoverContent(t *testing.T) {
+	dir := t.TempDir()
+	// 文件内容不决定锁的归属：没有进程持有锁时，空文件、无效内容和当前进程的 PID 都可以获取
+	for _, content := range []string{"", "garbage", strconv.Itoa(os.Getpid())} {
+		path := filepath.Join(dir, "daemon.pid")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		release, err := AcquirePidFile(path)
+		if err != nil {
+			t.Fatalf("内容为 %q 且未被锁定时应可以获取: %v", content, err)
+		}
+		if _, err := AcquirePidFile(path); err == nil {
+			t.Errorf("内容为 %q 时，持有期间同一进程再次获取应失败", content)
+		}
+		release()
+	}
+}
+
+func TestLockFileSerializesHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	var holders, maxHolders int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := LockFile(path, 5*time.Second)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			n := atomic.AddInt32(&holders, 1)
+			for {
+				max := atomic.LoadInt32(&maxHolders)
+				if n <= max || atomic.CompareAndSwapInt32(&maxHolders, max, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if maxHolders != 1 {
+		t.Errorf("同一时间应只有一个持有者，实际最多 %d 个", maxHolders)
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile 是按大小轮转的日志文件，超过 maxBytes 时把 name 重命名为 name.1，
+// 原有的 name.1 依次后移，最多保留 maxBackups 个旧文件
+type RotatingFile struct {
+	mu         sync.Mutex
+	name       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	closed     bool
+}
+
+// NewRotatingFile 以追加方式打开日志文件，不存在时创建目录和文件
+func NewRotatingFile(name string, maxBytes int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return nil, fmt.Errorf(T("log.mkdir_failed"), err)
+	}
+	f := &RotatingFile{name: name, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf(T("log.open_failed"), err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf(T("log.stat_failed"), err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write 写入一条日志，写入后超过大小限制时先轮转。轮转失败时继续写入当前文件，下次写入时重试
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	// 上次轮转后没能重新打开文件时再试一次
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.maxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			if f.file == nil {
+				return 0, err
+			}
+			DebugLog(1, "[Log] %v", err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate 关闭当前文件并依次重命名旧文件，超出数量的最旧文件被删除。
+// 无论是否成功都会重新打开 name，失败时之后的日志继续追加到当前文件
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		err = fmt.Errorf(T("log.close_failed"), err)
+	} else {
+		err = f.shift()
+	}
+	if openErr := f.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// shift 把 name 重命名为 name.1，原有的旧文件依次后移；不保留旧文件时直接删除 name
+func (f *RotatingFile) shift() error {
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.name); err != nil {
+			return fmt.Errorf(T("log.rotate_failed"), err)
+		}
+		return nil
+	}
+	os.Remove(fmt.Sprintf("%s.%d", f.name, f.maxBackups))
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.name, i), fmt.Sprintf("%s.%d", f.name, i+1))
+	}
+	if err := os.Rename(f.name, f.name+".1"); err != nil {
+		return fmt.Errorf(T("log.rotate_failed"), err)
+	}
+	return nil
+}
+
+// Close 关闭日志文件
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readLog 返回日志文件的内容，不存在时返回 "-"
+func readLog(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return "-"
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeLines(t *testing.T, f *RotatingFile, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if _, err := f.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("写入 %q 失败: %v", line, err)
+		}
+	}
+}
+
+func TestRotatingFileShiftsBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "logs", "access.log")
+	f, err := NewRotatingFile(name, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// 每行 10 字节，写入第二行时超过大小限制
+	writeLines(t, f, "aaaaaaaaa", "bbbbbbbbb")
+	if readLog(t, name) != "bbbbbbbbb\n" || readLog(t, name+".1") != "aaaaaaaaa\n" {
+		t.Fatalf("第一次轮转后: %q %q", readLog(t, name), readLog(t, name+".1"))
+	}
+
+	writeLines(t, f, "ccccccccc", "ddddddddd")
+	got := []string{readLog(t, name), readLog(t, name+".1"), readLog(t, name+".2"), readLog(t, name+".3")}
+	want := []string{"ddddddddd\n", "ccccccccc\n", "bbbbbbbbb\n", "-"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("最多保留 2 个旧文件，实际为 %q", got)
+	}
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "access.log")
+	f, err := NewRotatingFile(name, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	writeLines(t, f, "aaaaaaaaa", "bbbbbbbbb")
+	if readLog(t, name) != "bbbbbbbbb\n" || readLog(t, name+".1") != "-" {
+		t.Fatalf("不保留旧文件时应直接清空: %q %q", readLog(t, name), readLog(t, name+".1"))
+	}
+}
+
+func TestRotatingFileRecoversFromFailedRotation(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "access.log")
+	f, err := NewRotatingFile(name, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// name.1 是非空目录时无法删除也无法重命名过去
+	if err := os.MkdirAll(filepath.Join(name+".1", "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeLines(t, f, "aaaaaaaaa", "bbbbbbbbb")
+	if readLog(t, name) != "aaaaaaaaa\nbbbbbbbbb\n" {
+		t.Fatalf("轮转失败时应继续写入当前文件: %q", readLog(t, name))
+	}
+
+	// 障碍消除后的下一次写入重新轮转
+	if err := os.RemoveAll(name + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeLines(t, f, "ccccccccc")
+	if readLog(t, name) != "ccccccccc\n" || readLog(t, name+".1") != "aaaaaaaaa\nbbbbbbbbb\n" {
+		t.Fatalf("恢复后应正常轮转: %q %q", readLog(t, name), readLog(t, name+".1"))
+	}
+
+	f.Close()
+	if _, err := f.Write([]byte("x")); err != os.ErrClosed {
+		t.Errorf("关闭后写入应返回 os.ErrClosed，实际为 %v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// KeyCode 按键类型，普通字符为 KeyRune
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEsc
+	KeyTab
+	KeyBackTab
+	KeyBackspace
+	KeyDelete
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDn
+	KeyCtrlC
+	KeyCtrlL
+)
+
+// Key 一次按键
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// Screen 全屏终端：进入备用屏幕缓冲区并把终端切换到原始模式，按键通过 Keys 读取
+type Screen struct {
+	in    *os.File
+	out   *os.File
+	state *term.State
+
+	mu     sync.Mutex
+	keys   chan Key
+	done   chan struct{} // Close 时关闭，通知读取按键的 goroutine 退出
+	output chan string
+	pipe   *os.File
+	closed bool
+}
+
+const (
+	// inputPollInterval 等待输入的间隔，退出全屏后最迟在这段时间内停止读取标准输入
+	inputPollInterval = 100 * time.Millisecond
+	// escTimeout 收到 ESC 后等待后续字节的时间，超时仍不完整时按单独的 ESC 键处理。
+	// 慢速连接和 SSH 中方向键等序列可能分两次到达
+	escTimeout = 50 * time.Millisecond
+	// maxEscapeLen 转义序列的最大长度，超过时丢弃，避免一直等待
+	maxEscapeLen = 32
+)
+
+// IsTerminal 判断标准输入和标准输出是否都是终端
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// OpenScreen 进入全屏模式。全屏期间写入 os.Stdout 的内容会被截获，通过 Output 按行读取，避免破坏界面
+func OpenScreen() (*Screen, error) {
+	if !IsTerminal() {
+		return nil, errors.New(T("screen.not_terminal"))
+	}
+	in, out := os.Stdin, os.Stdout
+	if !enableVirtualTerminal(out) {
+		return nil, errors.New(T("screen.no_ansi"))
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf(T("screen.raw_failed"), err)
+	}
+
+	s := &Screen{
+		in:     in,
+		out:    out,
+		state:  state,
+		keys:   make(chan Key, 64),
+		done:   make(chan struct{}),
+		output: make(chan string, 64),
+	}
+	// 备用屏幕缓冲区、隐藏光标
+	out.WriteString("\033[?1049h\033[?25l\033[2J")
+
+	if r, w, err := os.Pipe(); err == nil {
+		s.pipe = w
+		os.Stdout = w
+		go s.captureOutput(r)
+	}
+	go s.readKeys()
+	return s, nil
+}
+
+// Close 恢复终端状态和标准输出，可以重复调用
+func (s *Screen) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+	if s.pipe != nil {
+		os.Stdout = s.out
+		s.pipe.Close()
+	}
+	s.out.WriteString("\033[0m\033[?25h\033[?1049l")
+	term.Restore(int(s.in.Fd()), s.state)
+}
+
+// Keys 返回按键通道，标准输入关闭或调用 Close 后通道关闭
+func (s *Screen) Keys() <-chan Key {
+	return s.keys
+}
+
+// Output 返回全屏期间其他代码写到标准输出的内容，已去掉颜色序列
+func (s *Screen) Output() <-chan string {
+	return s.output
+}
+
+// Size 返回终端的列数和行数，获取失败时按 80x24 处理
+func (s *Screen) Size() (int, int) {
+	width, height, err := term.GetSize(int(s.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// SetClipboard 通过 OSC 52 序列请求终端把文本写入剪贴板，SSH 连接中也可用，
+// 终端不支持时没有任何效果
+func (s *Screen) SetClipboard(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		io.WriteString(s.out, "\033]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+	}
+}
+
+// Render 一次性重绘整屏，每行先截断到终端宽度，减少闪烁
+func (s *Screen) Render(lines []string) {
+	width, height := s.Size()
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for i := 0; i < height; i++ {
+		b.WriteString("\033[" + strconv.Itoa(i+1) + ";1H")
+		if i < len(lines) {
+			b.WriteString(TruncateWidth(lines[i], width))
+		}
+		b.WriteString("\033[0m\033[K")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		io.WriteString(s.out, b.String())
+	}
+}
+
+// captureOutput 把截获的输出按行转发，界面来不及处理时丢弃
+func (s *Screen) captureOutput(r *os.File) {
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(StripANSI(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		select {
+		case s.output <- line:
+		default:
+		}
+	}
+}
+
+// readKeys 读取标准输入并解析为按键。只在有输入时读取，Close 之后不再读取，
+// 不会吞掉退出全屏后输入的内容；不完整的转义序列保留到下一次读取
+func (s *Screen) readKeys() {
+	defer close(s.keys)
+	buf := make([]byte, 256)
+	var pending []byte
+	for {
+		timeout := inputPollInterval
+		if escPending(pending) {
+			timeout = escTimeout
+		}
+		ready, err := waitInput(s.in, timeout)
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		if err != nil {
+			return
+		}
+		if !ready {
+			if escPending(pending) {
+				pending = s.parseKeys(pending, true)
+			}
+			continue
+		}
+
+		n, err := s.in.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+			pending = s.parseKeys(pending, false)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// escPending 判断是否有未完成的转义序列在等待后续字节
+func escPending(pending []byte) bool {
+	return len(pending) > 0 && pending[0] == 0x1b
+}
+
+// parseKeys 解析尽可能多的按键，返回末尾不完整的字节 (UTF-8 字符或转义序列)；
+// flush 为 true 时不再等待，不完整的转义序列按 ESC 键处理
+func (s *Screen) parseKeys(data []byte, flush bool) []byte {
+	for len(data) > 0 {
+		key, n := parseKey(data)
+		if n == 0 {
+			if !flush || data[0] != 0x1b {
+				return data
+			}
+			key, n = &Key{Code: KeyEsc}, 1
+		}
+		data = data[n:]
+		if key != nil {
+			select {
+			case s.keys <- *key:
+			case <-s.done:
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// parseKey 解析一个按键，返回按键和消耗的字节数；字节不完整时返回 0，无法识别时按键为 nil
+func parseKey(data []byte) (*Key, int) {
+	switch b := data[0]; {
+	case b == 0x1b:
+		// 单独的 ESC 可能是序列的开头，等待后续字节或超时
+		if len(data) == 1 {
+			return nil, 0
+		}
+		if data[1] != '[' && data[1] != 'O' {
+			return &Key{Code: KeyEsc}, 1
+		}
+		return parseEscape(data)
+	case b == '\r' || b == '\n':
+		return &Key{Code: KeyEnter}, 1
+	case b == '\t':
+		return &Key{Code: KeyTab}, 1
+	case b == 0x7f || b == 0x08:
+		return &Key{Code: KeyBackspace}, 1
+	case b == 0x03:
+		return &Key{Code: KeyCtrlC}, 1
+	case b == 0x0c:
+		return &Key{Code: KeyCtrlL}, 1
+	case b < 0x20:
+		return nil, 1
+	}
+
+	if !utf8.FullRune(data) {
+		return nil, 0
+	}
+	r, size := utf8.DecodeRune(data)
+	if r == utf8.RuneError {
+		return nil, size
+	}
+	return &Key{Code: KeyRune, Rune: r}, size
+}
+
+// parseEscape 解析 ESC [ 和 ESC O 开头的方向键、翻页键等序列，序列不完整时返回 0
+func parseEscape(data []byte) (*Key, int) {
+	end := 2
+	for end < len(data) && !(data[end] >= 0x40 && data[end] <= 0x7E) {
+		end++
+	}
+	if end >= len(data) {
+		if len(data) > maxEscapeLen {
+			return nil, len(data)
+		}
+		return nil, 0
+	}
+	params := string(data[2:end])
+	n := end + 1
+
+	switch data[end] {
+	case 'A':
+		return &Key{Code: KeyUp}, n
+	case 'B':
+		return &Key{Code: KeyDown}, n
+	case 'C':
+		return &Key{Code: KeyRight}, n
+	case 'D':
+		return &Key{Code: KeyLeft}, n
+	case 'H':
+		return &Key{Code: KeyHome}, n
+	case 'F':
+		return &Key{Code: KeyEnd}, n
+	case 'Z':
+		return &Key{Code: KeyBackTab}, n
+	case '~':
+		if i := strings.IndexByte(params, ';'); i >= 0 {
+			params = params[:i]
+		}
+		switch params {
+		case "1", "7":
+			return &Key{Code: KeyHome}, n
+		case "4", "8":
+			return &Key{Code: KeyEnd}, n
+		case "3":
+			return &Key{Code: KeyDelete}, n
+		case "5":
+			return &Key{Code: KeyPgUp}, n
+		case "6":
+			return &Key{Code: KeyPgDn}, n
+		}
+	}
+	return nil, n
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// pipeScreen 用管道代替终端的标准输入，只用于测试按键读取
+func pipeScreen(t *testing.T) (*Screen, *os.File) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 的管道不能等待输入")
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+		w.Close()
+	})
+	s := &Screen{in: r, keys: make(chan Key, 64), done: make(chan struct{})}
+	return s, w
+}
+
+// stopKeys 通知读取按键的 goroutine 退出，并等待按键通道关闭
+func stopKeys(t *testing.T, s *Screen) {
+	t.Helper()
+	close(s.done)
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-s.keys:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Close 之后读取按键的 goroutine 应退出")
+		}
+	}
+}
+
+func nextKey(t *testing.T, s *Screen) Key {
+	t.Helper()
+	select {
+	case key, ok := <-s.keys:
+		if !ok {
+			t.Fatal("按键通道已关闭")
+		}
+		return key
+	case <-time.After(time.Second):
+		t.Fatal("没有收到按键")
+		return Key{}
+	}
+}
+
+func TestReadKeysSplitEscape(t *testing.T) {
+	s, w := pipeScreen(t)
+	go s.readKeys()
+	defer stopKeys(t, s)
+
+	// 方向键和翻页键的序列分两次到达
+	for _, tt := range []struct {
+		parts []string
+		want  KeyCode
+	}{
+		{[]string{"\x1b", "[A"}, KeyUp},
+		{[]string{"\x1b[", "B"}, KeyDown},
+		{[]string{"\x1b[6", "~"}, KeyPgDn},
+	} {
+		for _, part := range tt.parts {
+			w.WriteString(part)
+			time.Sleep(escTimeout / 5)
+		}
+		if key := nextKey(t, s); key.Code != tt.want {
+			t.Errorf("%q 解析为 %v，应为 %v", tt.parts, key.Code, tt.want)
+		}
+	}
+
+	// 单独的 ESC 在超时后作为 ESC 键
+	w.WriteString("\x1b")
+	if key := nextKey(t, s); key.Code != KeyEsc {
+		t.Errorf("单独的 ESC 解析为 %v", key.Code)
+	}
+	w.WriteString("q")
+	if key := nextKey(t, s); key.Code != KeyRune || key.Rune != 'q' {
+		t.Errorf("ESC 之后的按键解析为 %+v", key)
+	}
+}
+
+func TestReadKeysStopsAfterClose(t *testing.T) {
+	s, w := pipeScreen(t)
+	go s.readKeys()
+	stopKeys(t, s)
+
+	// 退出后输入的内容留给后续读取标准输入的代码
+	w.WriteString("x")
+	buf := make([]byte, 1)
+	if _, err := s.in.Read(buf); err != nil || buf[0] != 'x' {
+		t.Fatalf("退出后的输入被吞掉了: %q %v", buf, err)
+	}
+}
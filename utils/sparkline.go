@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline 把数值序列画成一行迷你折线，只取最后 width 个点；数值都相同时画在中间高度，
+// 都为 0 时画在最低处；NaN 和 Inf 画成空格
+func Sparkline(values []float64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !isFinite(v) {
+			continue
+		}
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		if !isFinite(v) {
+			b.WriteByte(' ')
+			continue
+		}
+		level := len(sparkLevels) / 2
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkLevels)-1))
+		} else if high == 0 {
+			level = 0
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"空序列", nil, 10, ""},
+		{"从低到高", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 0, "▁▂▃▄▅▆▇█"},
+		{"只取最后 width 个点", []float64{100, 0, 7}, 2, "▁█"},
+		{"数值都相同时画在中间高度", []float64{5, 5, 5}, 0, "▅▅▅"},
+		{"都为 0 时画在最低处", []float64{0, 0}, 0, "▁▁"},
+		{"NaN 和 Inf 画成空格", []float64{0, math.NaN(), 7, math.Inf(1)}, 0, "▁ █ "},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("%s: Sparkline(%v, %d) = %q，应为 %q", tt.name, tt.values, tt.width, got, tt.want)
+		}
+	}
+}
@@ -1,13 +1,30 @@
 package utils
 
-import "strings"
+import (
+	"time"
+)
 
-// TruncateString 截断字符串，考虑中文字符
+// TruncateString 把字符串截断或补空格到 length 列，中文按两列计算
 func TruncateString(s string, length int) string {
-	r := []rune(s)
-	if len(r) > length {
-		return string(r[:length-3]) + "..."
+	return PadWidth(s, length, true)
+}
+
+// FormatDuration 将时长格式化为当前语言的易读描述，只保留最大的两个单位
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return T("duration.seconds", int(d.Seconds()))
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return T("duration.days_hours", days, hours)
+	case hours > 0:
+		return T("duration.hours_minutes", hours, minutes)
+	default:
+		return T("duration.minutes", minutes)
 	}
-	padding := strings.Repeat(" ", length-len(r))
-	return s + padding
 }
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// SVG 图表尺寸
+const (
+	svgWidth        = 640
+	svgHeight       = 240
+	svgMarginLeft   = 56
+	svgMarginRight  = 16
+	svgMarginTop    = 32
+	svgMarginBottom = 36
+	svgGridLines    = 4
+)
+
+// niceCeil 将最大值向上取整到 1/2/5 × 10^n，便于显示刻度
+func niceCeil(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if value <= step*exp {
+			return step * exp
+		}
+	}
+	return 10 * exp
+}
+
+// svgFrame 绘制标题、网格和坐标轴标签，返回绘图区的纵轴最大值
+func svgFrame(sb *strings.Builder, title, unit string, labels []string, values []float64) float64 {
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	maxValue = niceCeil(maxValue)
+
+	fmt.Fprintf(sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(sb, `<rect width="100%%" height="100%%" fill="#ffffff"/>`)
+	fmt.Fprintf(sb, `<text x="%d" y="18" font-size="13" font-weight="bold" fill="#333">%s</text>`,
+		svgMarginLeft, html.EscapeString(title))
+
+	plotHeight := float64(svgHeight - svgMarginTop - svgMarginBottom)
+	for i := 0; i <= svgGridLines; i++ {
+		y := float64(svgMarginTop) + plotHeight*float64(i)/svgGridLines
+		value := maxValue * float64(svgGridLines-i) / svgGridLines
+		fmt.Fprintf(sb, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e8e8e8"/>`,
+			svgMarginLeft, y, svgWidth-svgMarginRight, y)
+		fmt.Fprintf(sb, `<text x="%d" y="%.1f" text-anchor="end" fill="#666">%s</text>`,
+			svgMarginLeft-6, y+4, html.EscapeString(formatAxisValue(value)+unit))
+	}
+
+	// 标签过多时间隔显示
+	step := 1
+	if len(labels) > 12 {
+		step = (len(labels) + 11) / 12
+	}
+	slot := float64(svgWidth-svgMarginLeft-svgMarginRight) / math.Max(1, float64(len(labels)))
+	for i := 0; i < len(labels); i += step {
+		x := float64(svgMarginLeft) + slot*(float64(i)+0.5)
+		fmt.Fprintf(sb, `<text x="%.1f" y="%d" text-anchor="middle" fill="#666">%s</text>`,
+			x, svgHeight-svgMarginBottom+16, html.EscapeString(labels[i]))
+	}
+	return maxValue
+}
+
+func formatAxisValue(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+// SVGBarChart 生成柱状图
+func SVGBarChart(title, unit string, labels []string, values []float64, color string) string {
+	var sb strings.Builder
+	maxValue := svgFrame(&sb, title, unit, labels, values)
+
+	plotHeight := float64(svgHeight - svgMarginTop - svgMarginBottom)
+	slot := float64(svgWidth-svgMarginLeft-svgMarginRight) / math.Max(1, float64(len(values)))
+	for i, v := range values {
+		h := plotHeight * v / maxValue
+		x := float64(svgMarginLeft) + slot*float64(i) + slot*0.15
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s%s</title></rect>`,
+			x, float64(svgMarginTop)+plotHeight-h, slot*0.7, h, color,
+			html.EscapeString(labels[i]), formatAxisValue(v), html.EscapeString(unit))
+	}
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+// SVGLineChart 生成折线图
+func SVGLineChart(title, unit string, labels []string, values []float64, color string) string {
+	var sb strings.Builder
+	maxValue := svgFrame(&sb, title, unit, labels, values)
+
+	plotHeight := float64(svgHeight - svgMarginTop - svgMarginBottom)
+	slot := float64(svgWidth-svgMarginLeft-svgMarginRight) / math.Max(1, float64(len(values)))
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := float64(svgMarginLeft) + slot*(float64(i)+0.5)
+		y := float64(svgMarginTop) + plotHeight - plotHeight*v/maxValue
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`,
+		strings.Join(points, " "), color)
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableColumn 表格的一列
+type TableColumn struct {
+	Title    string
+	Right    bool // 右对齐，适合数字
+	Wrap     bool // 超出列宽时换行显示，否则截断
+	MinWidth int  // 表格过宽需要压缩时的最小列宽，默认 6
+}
+
+// Table 按显示宽度对齐的终端表格：中文和 emoji 按两列计算，颜色序列不占宽度，
+// 总宽度超过终端宽度时压缩最宽的列，再对单元格截断或换行
+type Table struct {
+	Columns     []TableColumn
+	Rows        [][]string
+	Border      bool // 使用方框线绘制，否则列之间用两个空格分隔
+	Width       int  // 最大总宽度，0 表示使用终端宽度
+	HeaderColor Role // 表头颜色，默认 RoleHeader
+}
+
+// NewTable 创建表格
+func NewTable(columns ...TableColumn) *Table {
+	return &Table{Columns: columns, HeaderColor: RoleHeader}
+}
+
+// AddRow 添加一行，单元格可以带颜色
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Print 输出到标准输出
+func (t *Table) Print() {
+	t.Fprint(os.Stdout)
+}
+
+// Fprint 输出到 w
+func (t *Table) Fprint(w io.Writer) error {
+	for _, line := range t.Render() {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Widths 返回各列的宽度，总宽度超过限制时从最宽的列开始压缩
+func (t *Table) Widths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, c := range t.Columns {
+		widths[i] = StringWidth(c.Title)
+	}
+	for _, row := range t.Rows {
+		for i := range widths {
+			if i < len(row) {
+				widths[i] = max(widths[i], StringWidth(row[i]))
+			}
+		}
+	}
+
+	limit := t.Width
+	if limit <= 0 {
+		limit = TerminalWidth()
+	}
+	overhead := 2 * (len(widths) - 1)
+	if t.Border {
+		overhead = 3*len(widths) + 1
+	}
+	total := overhead
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > limit {
+		widest := -1
+		for i, w := range widths {
+			minWidth := t.Columns[i].MinWidth
+			if minWidth <= 0 {
+				minWidth = 6
+			}
+			if w > minWidth && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// Render 返回表格的每一行
+func (t *Table) Render() []string {
+	widths := t.Widths()
+	var lines []string
+
+	rule := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w+2)
+		}
+		return left + strings.Join(parts, mid) + right
+	}
+	if t.Border {
+		lines = append(lines, rule("┌", "┬", "┐"))
+	}
+
+	titles := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		titles[i] = c.Title
+	}
+	lines = append(lines, t.renderRow(titles, widths, t.HeaderColor)...)
+	if t.Border {
+		lines = append(lines, rule("├", "┼", "┤"))
+	}
+	for _, row := range t.Rows {
+		lines = append(lines, t.renderRow(row, widths, "")...)
+	}
+	if t.Border {
+		lines = append(lines, rule("└", "┴", "┘"))
+	}
+	return lines
+}
+
+// renderRow 渲染一行，换行的单元格使该行占多行
+func (t *Table) renderRow(cells []string, widths []int, color Role) []string {
+	columns := make([][]string, len(widths))
+	height := 1
+	for i, w := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if t.Columns[i].Wrap {
+			columns[i] = WrapWidth(cell, w)
+		} else {
+			columns[i] = []string{TruncateWidth(cell, w)}
+		}
+		height = max(height, len(columns[i]))
+	}
+
+	lines := make([]string, height)
+	for row := range lines {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			text := ""
+			if row < len(columns[i]) {
+				text = columns[i][row]
+			}
+			text = PadWidth(text, w, !t.Columns[i].Right)
+			if color != "" {
+				text = ColorText(color, text)
+			}
+			parts[i] = text
+		}
+		if t.Border {
+			lines[row] = "│ " + strings.Join(parts, " │ ") + " │"
+		} else {
+			lines[row] = strings.TrimRight(strings.Join(parts, "  "), " ")
+		}
+	}
+	return lines
+}
+
+// WrapWidth 按显示宽度把字符串折成多行，颜色在换行处延续
+func WrapWidth(s string, width int) []string {
+	if width <= 0 || StringWidth(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	var b strings.Builder
+	active := "" // 当前生效的颜色序列
+	used := 0
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			seq := s[i : i+n]
+			if seq == ansiReset {
+				active = ""
+			} else {
+				active += seq
+			}
+			b.WriteString(seq)
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if w := RuneWidth(r); used+w > width {
+			if active != "" {
+				b.WriteString(ansiReset)
+			}
+			lines = append(lines, b.String())
+			b.Reset()
+			b.WriteString(active)
+			used = 0
+		}
+		b.WriteRune(r)
+		used += RuneWidth(r)
+		i += size
+	}
+	if b.Len() > 0 {
+		lines = append(lines, b.String())
+	}
+	return lines
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// newNodeTable 返回包含中文、emoji 名称和带颜色单元格的表格
+func newNodeTable(wrap bool) *Table {
+	table := NewTable(
+		TableColumn{Title: "排名", Right: true},
+		TableColumn{Title: "节点名称", Wrap: wrap, MinWidth: 8},
+		TableColumn{Title: "状态"},
+		TableColumn{Title: "流量", Right: true},
+	)
+	table.AddRow("1", "🚀 北京电信 BGP 多线高速节点", "\033[32m在线\033[0m", "1.2 TB")
+	table.AddRow("2", "上海联通", "\033[31m离线\033[0m", "512 GB")
+	table.AddRow("3", "node-hk-01 🇭🇰", "\033[33m⚠️ 警告\033[0m", "20 GB")
+	return table
+}
+
+func TestTableFitsWidth(t *testing.T) {
+	for _, wrap := range []bool{false, true} {
+		for _, border := range []bool{false, true} {
+			table := newNodeTable(wrap)
+			table.Border = border
+			table.Width = 40
+
+			lines := table.Render()
+			for _, line := range lines {
+				width := StringWidth(line)
+				if width > 40 {
+					t.Errorf("wrap=%v border=%v: %q 的宽度 %d 超过 40", wrap, border, StripANSI(line), width)
+				}
+				// 带边框时每行宽度相同，竖线才能对齐
+				if border && width != StringWidth(lines[0]) {
+					t.Errorf("wrap=%v: %q 的宽度 %d 与边框 %d 不同", wrap, StripANSI(line), width, StringWidth(lines[0]))
+				}
+			}
+
+			text := StripANSI(strings.Join(lines, "\n"))
+			if wrap {
+				if strings.Contains(text, "…") {
+					t.Errorf("border=%v: 换行的列不应截断:\n%s", border, text)
+				}
+				if len(lines) <= len(table.Rows)+1+boolInt(border)*3 {
+					t.Errorf("border=%v: 过长的名称应占多行:\n%s", border, text)
+				}
+			} else if !strings.Contains(text, "…") {
+				t.Errorf("border=%v: 过长的名称应被截断:\n%s", border, text)
+			}
+		}
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestTableWidthsShrinkWidestColumn(t *testing.T) {
+	table := newNodeTable(false)
+	table.Width = 200
+	full := table.Widths()
+	if full[1] != StringWidth("🚀 北京电信 BGP 多线高速节点") {
+		t.Fatalf("空间足够时名称列应为内容宽度，实际为 %v", full)
+	}
+
+	table.Width = 30
+	widths := table.Widths()
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	if total > 30 {
+		t.Errorf("压缩后总宽度 %d 超过 30: %v", total, widths)
+	}
+	for i := range widths {
+		if i != 1 && widths[i] != full[i] {
+			t.Errorf("应只压缩最宽的名称列，第 %d 列从 %d 变为 %d", i+1, full[i], widths[i])
+		}
+	}
+
+	// 无法再压缩时保留最小列宽
+	table.Width = 10
+	widths = table.Widths()
+	if widths[1] != 8 {
+		t.Errorf("名称列不应小于 MinWidth 8，实际为 %d", widths[1])
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// 非终端输入共用同一个缓冲读取器，避免多次读取时丢失已缓冲的内容
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// ReadPassword 从终端读取密码，不回显输入；标准输入不是终端时按行读取
+func ReadPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		data, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf(T("terminal.read_password_failed"), err)
+		}
+		return string(data), nil
+	}
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf(T("terminal.read_password_failed"), err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ReadLine 读取一行输入并去掉首尾空白，与 ReadPassword 共用缓冲读取器
+func ReadLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// TerminalWidth 返回标准输出所在终端的列数，不是终端时按 80 列处理
+func TerminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return 80
+	}
+	return width
+}
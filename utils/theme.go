@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTheme 默认主题
+const DefaultTheme = "dark"
+
+// builtinThemes 内置主题，值为完整的 ANSI 序列
+var builtinThemes = map[string]map[Role]string{
+	// dark 深色背景，与早期版本的配色一致
+	"dark": {
+		RoleOK:        sgr("32"),
+		RoleWarn:      sgr("33"),
+		RoleError:     sgr("31"),
+		RoleLabel:     sgr("33"),
+		RoleValue:     sgr("36"),
+		RoleTitle:     sgr("1;34"),
+		RoleHeader:    sgr("1"),
+		RoleInfo:      sgr("34"),
+		RolePrompt:    sgr("35"),
+		RoleMuted:     sgr("2"),
+		RoleSelected:  sgr("7"),
+		RoleHighlight: sgr("1;7"),
+	},
+	// light 浅色背景，避免黄色和青色在白底上看不清
+	"light": {
+		RoleOK:        sgr("32"),
+		RoleWarn:      sgr("38;5;130"),
+		RoleError:     sgr("31"),
+		RoleLabel:     sgr("1"),
+		RoleValue:     sgr("34"),
+		RoleTitle:     sgr("1;34"),
+		RoleHeader:    sgr("1"),
+		RoleInfo:      sgr("34"),
+		RolePrompt:    sgr("35"),
+		RoleMuted:     sgr("2"),
+		RoleSelected:  sgr("7"),
+		RoleHighlight: sgr("1;7"),
+	},
+	// high-contrast 加粗的高亮色
+	"high-contrast": {
+		RoleOK:        sgr("1;92"),
+		RoleWarn:      sgr("1;93"),
+		RoleError:     sgr("1;91"),
+		RoleLabel:     sgr("1;97"),
+		RoleValue:     sgr("1;96"),
+		RoleTitle:     sgr("1;4;97"),
+		RoleHeader:    sgr("1;4"),
+		RoleInfo:      sgr("1;94"),
+		RolePrompt:    sgr("1;95"),
+		RoleMuted:     sgr("37"),
+		RoleSelected:  sgr("1;7"),
+		RoleHighlight: sgr("1;7;93"),
+	},
+	// colorblind-safe 使用 Okabe-Ito 配色，成功和错误用蓝色和朱红色区分，不依赖红绿对比
+	"colorblind-safe": {
+		RoleOK:        sgr("38;5;32"),
+		RoleWarn:      sgr("38;5;214"),
+		RoleError:     sgr("1;38;5;202"),
+		RoleLabel:     sgr("38;5;220"),
+		RoleValue:     sgr("38;5;117"),
+		RoleTitle:     sgr("1;38;5;32"),
+		RoleHeader:    sgr("1"),
+		RoleInfo:      sgr("38;5;117"),
+		RolePrompt:    sgr("38;5;176"),
+		RoleMuted:     sgr("2"),
+		RoleSelected:  sgr("7"),
+		RoleHighlight: sgr("1;7"),
+	},
+}
+
+// 主题配置中可以使用的颜色名称
+var colorNames = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"purple":    "35",
+	"cyan":      "36",
+	"white":     "37",
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"reverse":   "7",
+}
+
+var (
+	hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	rawSGR   = regexp.MustCompile(`^[0-9]+(;[0-9]+)*$`)
+)
+
+func sgr(params string) string {
+	return "\033[" + params + "m"
+}
+
+// ThemeNames 返回内置主题和自定义主题的名称
+func ThemeNames(custom map[string]map[string]string) []string {
+	names := make([]string, 0, len(builtinThemes)+len(custom))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := builtinThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme 切换主题。custom 为配置文件中的自定义主题，角色名映射到颜色描述，
+// 可以用 "base" 指定继承的内置主题（默认 dark），与内置主题同名时只覆盖其中的角色
+func SetTheme(name string, custom map[string]map[string]string) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+	styles, err := buildTheme(name, custom)
+	if err != nil {
+		return err
+	}
+	theme = styles
+	applyPalette()
+	return nil
+}
+
+func buildTheme(name string, custom map[string]map[string]string) (map[Role]string, error) {
+	specs, isCustom := custom[name]
+	base := name
+	if isCustom {
+		if _, ok := builtinThemes[name]; !ok {
+			base = DefaultTheme
+		}
+		if b, ok := specs["base"]; ok {
+			base = b
+		}
+	}
+	builtin, ok := builtinThemes[base]
+	if !ok {
+		return nil, fmt.Errorf(T("theme.unknown"), base, strings.Join(ThemeNames(custom), ", "))
+	}
+
+	styles := make(map[Role]string, len(builtin))
+	for role, style := range builtin {
+		styles[role] = style
+	}
+	for key, spec := range specs {
+		if key == "base" {
+			continue
+		}
+		role := Role(key)
+		if !validRole(role) {
+			return nil, fmt.Errorf(T("theme.unknown_role"), name, key)
+		}
+		style, err := ParseStyle(spec)
+		if err != nil {
+			return nil, fmt.Errorf(T("theme.role_error"), name, key, err)
+		}
+		styles[role] = style
+	}
+	return styles, nil
+}
+
+func validRole(role Role) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStyle 解析颜色描述，由空格或 + 分隔的多个部分组成，例如 "bold yellow"、"bright-red"、
+// "#ff8800"（真彩色）或 "38;5;208"（原始 SGR 参数）。"none" 或空字符串表示不使用样式
+func ParseStyle(spec string) (string, error) {
+	parts := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ' ' || r == '+' || r == ','
+	})
+	var params []string
+	for _, part := range parts {
+		switch {
+		case part == "none" || part == "default":
+		case colorNames[part] != "":
+			params = append(params, colorNames[part])
+		case strings.HasPrefix(part, "bright-") && colorNames[strings.TrimPrefix(part, "bright-")] != "":
+			code, _ := strconv.Atoi(colorNames[strings.TrimPrefix(part, "bright-")])
+			if code < 30 {
+				return "", fmt.Errorf(T("theme.invalid_color"), part)
+			}
+			params = append(params, strconv.Itoa(code+60))
+		case hexColor.MatchString(part):
+			r, _ := strconv.ParseUint(part[1:3], 16, 8)
+			g, _ := strconv.ParseUint(part[3:5], 16, 8)
+			b, _ := strconv.ParseUint(part[5:7], 16, 8)
+			params = append(params, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+		case rawSGR.MatchString(part):
+			params = append(params, part)
+		default:
+			return "", fmt.Errorf(T("theme.invalid_color"), part)
+		}
+	}
+	if len(params) == 0 {
+		return "", nil
+	}
+	return sgr(strings.Join(params, ";")), nil
+}
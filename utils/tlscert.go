@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 自签名证书有效期，剩余不足 30 天时重新生成
+const (
+	selfSignedValidity = 365 * 24 * time.Hour
+	selfSignedRenew    = 30 * 24 * time.Hour
+)
+
+// LoadOrCreateSelfSigned 读取 dir 下的自签名证书，不存在、即将过期或主机名变化时重新生成。
+// 生成的是只能用于服务端认证的终端证书，用户信任它后私钥也不能签发其他证书
+func LoadOrCreateSelfSigned(dir string, hosts []string) (tls.Certificate, error) {
+	if len(hosts) == 0 {
+		hosts = []string{"localhost"}
+	}
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if cert, err := tls.LoadX509KeyPair(certFile, keyFile); err == nil {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		// 旧版本生成的是 CA 证书，需要重新生成
+		if err == nil && !leaf.IsCA && time.Until(leaf.NotAfter) > selfSignedRenew && sameHosts(certHosts(leaf), hosts) {
+			cert.Leaf = leaf
+			return cert, nil
+		}
+		DebugLog(1, "[TLS] 自签名证书已过期、主机名已变化或是旧版的 CA 证书，重新生成")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.mkdir_failed"), err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.key_failed"), err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.serial_failed"), err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"OpenBMCLAPI Dashboard"}, CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.cert_failed"), err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.marshal_key_failed"), err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.save_key_failed"), err)
+	}
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		return tls.Certificate{}, fmt.Errorf(T("tls.save_cert_failed"), err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cert.Leaf, _ = x509.ParseCertificate(der)
+	fmt.Println(ColorText(RoleOK, T("tls.generated", certFile)))
+	return cert, nil
+}
+
+// CertFingerprint 返回证书的 SHA-256 指纹，便于在浏览器中核对
+func CertFingerprint(cert tls.Certificate) string {
+	if len(cert.Certificate) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(cert.Certificate[0])
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func certHosts(cert *x509.Certificate) []string {
+	hosts := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	return hosts
+}
+
+// sameHosts 忽略顺序比较主机名列表
+func sameHosts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	normalize := func(list []string) []string {
+		out := make([]string, len(list))
+		for i, host := range list {
+			if ip := net.ParseIP(host); ip != nil {
+				host = ip.String()
+			}
+			out[i] = strings.ToLower(host)
+		}
+		sort.Strings(out)
+		return out
+	}
+	x, y := normalize(a), normalize(b)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/x509"
+	"testing"
+)
+
+func TestSelfSignedIsLeafCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cert, err := LoadOrCreateSelfSigned(dir, []string{"localhost", "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := cert.Leaf
+	if leaf.IsCA || leaf.KeyUsage&x509.KeyUsageCertSign != 0 {
+		t.Fatal("自签名证书不应能签发其他证书")
+	}
+	if leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		t.Error("自签名证书应允许数字签名")
+	}
+	if len(leaf.ExtKeyUsage) != 1 || leaf.ExtKeyUsage[0] != x509.ExtKeyUsageServerAuth {
+		t.Errorf("扩展用途应只有 ServerAuth，实际为 %v", leaf.ExtKeyUsage)
+	}
+
+	// 主机名不变时复用已有证书
+	again, err := LoadOrCreateSelfSigned(dir, []string{"127.0.0.1", "localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CertFingerprint(again) != CertFingerprint(cert) {
+		t.Error("主机名不变时不应重新生成证书")
+	}
+}
+
+func TestSelfSignedWithoutHosts(t *testing.T) {
+	cert, err := LoadOrCreateSelfSigned(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Leaf.Subject.CommonName != "localhost" {
+		t.Errorf("没有主机名时应使用 localhost，实际为 %q", cert.Leaf.Subject.CommonName)
+	}
+}
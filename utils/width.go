@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 终端中占两列的字符范围：中日韩文字、全角符号和常见 emoji
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
+
+// RuneWidth 返回字符在终端中占用的列数
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F):
+		return 0
+	case r < 0x20 || r == 0x7F:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// ansiLen 返回 s 开头的 ANSI 转义序列长度，不是转义序列时返回 0
+func ansiLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7E {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// StringWidth 返回字符串的显示宽度，忽略 ANSI 颜色转义序列
+func StringWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// StripANSI 去掉字符串中的 ANSI 转义序列
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// TruncateWidth 把字符串截断到不超过 width 列，被截断时以 "…" 结尾，保留其中的颜色序列
+func TruncateWidth(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	used, colored := 0, false
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			colored = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := RuneWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+		i += size
+	}
+	b.WriteString("…")
+	if colored {
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}
+
+// PadWidth 截断或补空格使字符串正好占 width 列，alignLeft 为 false 时右对齐
+func PadWidth(s string, width int, alignLeft bool) string {
+	s = TruncateWidth(s, width)
+	padding := strings.Repeat(" ", max(width-StringWidth(s), 0))
+	if alignLeft {
+		return s + padding
+	}
+	return padding + s
+}
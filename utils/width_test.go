@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+const red = "\033[31m"
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"node-01", 7},
+		{"北京电信", 8},
+		{"节点A", 5},
+		{"🚀node", 6},
+		{"Ａ１", 4},                   // 全角字母和数字
+		{"e\u0301", 1},              // 组合重音符不占宽度
+		{"👩\u200d💻", 4},             // 零宽连接符不占宽度
+		{"\u2764\ufe0f", 1},         // 变体选择符不占宽度
+		{red + "红色" + ansiReset, 4}, // 颜色序列不占宽度
+		{red + "🔥hot" + ansiReset + " ok", 8},
+	}
+	for _, tt := range tests {
+		if got := StringWidth(tt.s); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d，应为 %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"node-01", 10, "node-01"},
+		{"node-01", 5, "node…"},
+		{"北京电信节点", 7, "北京电…"},
+		{"北京电信节点", 6, "北京…"}, // 宽字符放不下时不拆开，留出空位
+		{"🚀火箭节点", 5, "🚀火…"},
+		{"中文", 0, ""},
+	}
+	for _, tt := range tests {
+		got := TruncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("TruncateWidth(%q, %d) = %q，应为 %q", tt.s, tt.width, got, tt.want)
+		}
+		if StringWidth(got) > tt.width {
+			t.Errorf("TruncateWidth(%q, %d) 的宽度为 %d", tt.s, tt.width, StringWidth(got))
+		}
+	}
+
+	colored := red + "上海联通节点" + ansiReset
+	got := TruncateWidth(colored, 5)
+	if StringWidth(got) != 5 || StripANSI(got) != "上海…" {
+		t.Errorf("TruncateWidth 带颜色的单元格 = %q", got)
+	}
+	if !strings.HasPrefix(got, red) || !strings.HasSuffix(got, ansiReset) {
+		t.Errorf("截断后应保留颜色并在结尾重置: %q", got)
+	}
+}
+
+func TestWrapWidth(t *testing.T) {
+	lines := WrapWidth("北京电信节点", 5)
+	if strings.Join(lines, "|") != "北京|电信|节点" {
+		t.Errorf("WrapWidth = %q", lines)
+	}
+
+	lines = WrapWidth("🚀 rocket 节点", 6)
+	if StripANSI(strings.Join(lines, "")) != "🚀 rocket 节点" {
+		t.Errorf("换行后内容不应丢失: %q", lines)
+	}
+	for _, line := range lines {
+		if StringWidth(line) > 6 {
+			t.Errorf("%q 超过 6 列", line)
+		}
+	}
+
+	// 颜色在换行处延续，每行单独重置
+	lines = WrapWidth(red+"广州移动节点"+ansiReset, 4)
+	if len(lines) != 3 {
+		t.Fatalf("应折成 3 行: %q", lines)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, red) {
+			t.Errorf("第 %d 行没有延续颜色: %q", i+1, line)
+		}
+		if i < len(lines)-1 && !strings.HasSuffix(line, ansiReset) {
+			t.Errorf("第 %d 行结尾没有重置颜色: %q", i+1, line)
+		}
+		if StringWidth(line) != 4 {
+			t.Errorf("第 %d 行宽度为 %d", i+1, StringWidth(line))
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	for _, s := range []string{"中文", "ab", red + "红" + ansiReset, "超过宽度的中文名称"} {
+		for _, left := range []bool{true, false} {
+			if got := PadWidth(s, 6, left); StringWidth(got) != 6 {
+				t.Errorf("PadWidth(%q, 6, %v) 的宽度为 %d", s, left, StringWidth(got))
+			}
+		}
+	}
+	if got := PadWidth("中", 4, false); got != "  中" {
+		t.Errorf("右对齐 = %q", got)
+	}
+}
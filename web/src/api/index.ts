@@ -1,10 +1,55 @@
 import axios from 'axios'
-import type { User, Node, DashboardData, NodeMetricRank } from '../types'
+import type { User, Node, DashboardData, NodeMetricRank, UptimeEntry, WebSession, LoginPayload, WebUser, WebUserPayload, LiveEventHandlers, NodeDetail } from '../types'
 
 const api = axios.create({
   baseURL: '/api'
 })
 
+// 修改数据的请求需要携带登录时返回的 CSRF 令牌
+let csrfToken = ''
+let onUnauthorized: (() => void) | null = null
+
+export function setCsrfToken(token: string) {
+  csrfToken = token
+}
+
+export function setUnauthorizedHandler(handler: () => void) {
+  onUnauthorized = handler
+}
+
+api.interceptors.request.use(config => {
+  const method = (config.method || 'get').toLowerCase()
+  if (csrfToken && !['get', 'head', 'options'].includes(method)) {
+    config.headers.set('X-CSRF-Token', csrfToken)
+  }
+  return config
+})
+
+api.interceptors.response.use(
+  response => response,
+  error => {
+    const url: string = error.config?.url || ''
+    if (error.response?.status === 401 && !url.startsWith('/auth/') && onUnauthorized) {
+      onUnauthorized()
+    }
+    return Promise.reject(error)
+  }
+)
+
+export async function login(payload: LoginPayload): Promise<WebSession> {
+  const { data } = await api.post('/auth/login', payload)
+  return data.data
+}
+
+export async function logout(): Promise<void> {
+  await api.post('/auth/logout')
+}
+
+export async function fetchSession(): Promise<WebSession> {
+  const { data } = await api.get('/auth/session')
+  return data.data
+}
+
 export async function fetchUser(): Promise<User> {
   const { data } = await api.get('/user')
   return data.data
@@ -15,12 +60,17 @@ export async function fetchNodes(): Promise<Node[]> {
   return data.data
 }
 
+export async function fetchNodeDetail(nodeId: string): Promise<NodeDetail> {
+  const { data } = await api.get(`/nodes/${encodeURIComponent(nodeId)}`)
+  return data.data
+}
+
 export async function updateNode(nodeId: string, nodeData: Partial<Node>): Promise<void> {
   await api.patch(`/nodes/${nodeId}`, nodeData)
 }
 
 export async function resetNodeSecret(nodeId: string): Promise<string> {
-  const { data } = await api.patch(`/nodes/${nodeId}/reset-secret`)
+  const { data } = await api.post(`/nodes/${nodeId}/reset-secret`)
   return data.data.secret
 }
 
@@ -32,4 +82,40 @@ export async function fetchDashboard(): Promise<DashboardData> {
 export async function getNodeMetricRank(): Promise<NodeMetricRank[]> {
   const { data } = await api.get('/nodes/rank')
   return data.data
-} 
\ No newline at end of file
+}
+
+export async function fetchUptime(): Promise<UptimeEntry[]> {
+  const { data } = await api.get('/uptime')
+  return data.data
+}
+
+export async function fetchWebUsers(): Promise<WebUser[]> {
+  const { data } = await api.get('/users')
+  return data.data
+}
+
+export async function createWebUser(payload: WebUserPayload): Promise<void> {
+  await api.post('/users', payload)
+}
+
+export async function updateWebUser(username: string, payload: WebUserPayload): Promise<void> {
+  await api.patch(`/users/${encodeURIComponent(username)}`, payload)
+}
+
+export async function deleteWebUser(username: string): Promise<void> {
+  await api.delete(`/users/${encodeURIComponent(username)}`)
+}
+
+// 订阅服务器推送的数据更新，连接断开时浏览器会自动重连
+export function subscribeEvents(handlers: LiveEventHandlers, onStateChange?: (connected: boolean) => void): EventSource {
+  const source = new EventSource('/api/events')
+  const events = Object.keys(handlers) as (keyof LiveEventHandlers)[]
+  for (const type of events) {
+    source.addEventListener(type, event => {
+      handlers[type](JSON.parse((event as MessageEvent).data))
+    })
+  }
+  source.onopen = () => onStateChange?.(true)
+  source.onerror = () => onStateChange?.(false)
+  return source
+}
@@ -3,6 +3,8 @@ import { createPinia } from 'pinia'
 import Antd from 'ant-design-vue'
 import App from './App.vue'
 import router from './router'
+import { setUnauthorizedHandler } from './api'
+import { useAuthStore } from './stores/auth'
 import 'ant-design-vue/dist/reset.css'
 
 const app = createApp(App)
@@ -11,4 +13,12 @@ app.use(createPinia())
 app.use(router)
 app.use(Antd)
 
+// 会话过期时回到登录页
+setUnauthorizedHandler(() => {
+  useAuthStore().clear()
+  if (router.currentRoute.value.name !== 'Login') {
+    router.push({ name: 'Login', query: { redirect: router.currentRoute.value.fullPath } })
+  }
+})
+
 app.mount('#app') 
\ No newline at end of file
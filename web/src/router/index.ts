@@ -1,6 +1,8 @@
 import { createRouter, createWebHistory } from 'vue-router'
 import Dashboard from '../views/Dashboard.vue'
+import Login from '../views/Login.vue'
 import NotFound from '../views/NotFound.vue'
+import { useAuthStore } from '../stores/auth'
 
 const router = createRouter({
   history: createWebHistory(),
@@ -10,12 +12,35 @@ const router = createRouter({
       name: 'Dashboard',
       component: Dashboard
     },
+    {
+      path: '/login',
+      name: 'Login',
+      component: Login,
+      meta: { public: true }
+    },
     {
       path: '/:pathMatch(.*)*',
       name: 'NotFound',
-      component: NotFound
+      component: NotFound,
+      meta: { public: true }
     }
   ]
 })
 
-export default router 
\ No newline at end of file
+router.beforeEach(async to => {
+  // 通过终端打开的链接在 URL 片段中带有访问令牌，交给登录页处理
+  if (to.name !== 'Login' && to.hash.startsWith('#token=')) {
+    return { name: 'Login', hash: to.hash, query: { redirect: to.path } }
+  }
+  if (to.meta.public) {
+    return true
+  }
+
+  const authStore = useAuthStore()
+  if (!(await authStore.checkSession())) {
+    return { name: 'Login', query: { redirect: to.fullPath } }
+  }
+  return true
+})
+
+export default router
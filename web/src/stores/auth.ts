@@ -0,0 +1,59 @@
+import { defineStore } from 'pinia'
+import { ref } from 'vue'
+import type { Capability, LoginPayload, WebSession } from '../types'
+import { fetchSession, login as apiLogin, logout as apiLogout, setCsrfToken } from '../api'
+
+export const useAuthStore = defineStore('auth', () => {
+  const session = ref<WebSession | null>(null)
+  const checked = ref(false)
+
+  function setSession(value: WebSession | null) {
+    session.value = value
+    setCsrfToken(value?.csrfToken || '')
+  }
+
+  // 检查是否已登录，只在首次进入页面时请求
+  async function checkSession(): Promise<boolean> {
+    if (!checked.value) {
+      checked.value = true
+      try {
+        setSession(await fetchSession())
+      } catch {
+        setSession(null)
+      }
+    }
+    return session.value !== null
+  }
+
+  async function login(payload: LoginPayload) {
+    setSession(await apiLogin(payload))
+    checked.value = true
+  }
+
+  async function logout() {
+    try {
+      await apiLogout()
+    } finally {
+      setSession(null)
+    }
+  }
+
+  // 界面只用于隐藏无权限的操作，实际权限由服务器校验
+  function can(capability: Capability): boolean {
+    return session.value?.capabilities.includes(capability) ?? false
+  }
+
+  function clear() {
+    setSession(null)
+    checked.value = true
+  }
+
+  return {
+    session,
+    checkSession,
+    login,
+    logout,
+    can,
+    clear
+  }
+})
@@ -0,0 +1,87 @@
+import { defineStore } from 'pinia'
+import { ref } from 'vue'
+import type { NodeStatusChange } from '../types'
+import { subscribeEvents } from '../api'
+import { useDashboardStore } from './dashboard'
+import { useNodeStore } from './node'
+
+// 连接被服务器关闭 (如会话过期) 后的重连间隔
+const RECONNECT_DELAY = 10000
+
+export const useLiveStore = defineStore('live', () => {
+  const connected = ref(false)
+  const lastUpdate = ref<Date | null>(null)
+  const changes = ref<NodeStatusChange[]>([])
+
+  let source: EventSource | null = null
+  let reconnectTimer: ReturnType<typeof setTimeout> | null = null
+  let subscribers = 0
+
+  function open() {
+    const dashboardStore = useDashboardStore()
+    const nodeStore = useNodeStore()
+
+    source = subscribeEvents(
+      {
+        dashboard: data => {
+          dashboardStore.dashboard = data
+          lastUpdate.value = new Date()
+        },
+        nodes: data => {
+          nodeStore.nodes = data.nodes
+          if (data.changes.length > 0) {
+            changes.value = data.changes
+          }
+          lastUpdate.value = new Date()
+        },
+        rank: data => {
+          nodeStore.ranks = data
+          lastUpdate.value = new Date()
+        }
+      },
+      state => {
+        connected.value = state
+        // 浏览器不会重连被服务器拒绝的连接，稍后重新检查
+        if (!state && source?.readyState === EventSource.CLOSED) {
+          close()
+          // 通过普通请求确认会话状态，会话过期时会跳转到登录页
+          dashboardStore.fetchDashboard()
+          reconnectTimer = setTimeout(open, RECONNECT_DELAY)
+        }
+      }
+    )
+  }
+
+  function close() {
+    if (reconnectTimer) {
+      clearTimeout(reconnectTimer)
+      reconnectTimer = null
+    }
+    source?.close()
+    source = null
+    connected.value = false
+  }
+
+  // 多个组件共享同一个连接，最后一个组件卸载时断开
+  function connect() {
+    subscribers++
+    if (subscribers === 1) {
+      open()
+    }
+  }
+
+  function disconnect() {
+    subscribers = Math.max(0, subscribers - 1)
+    if (subscribers === 0) {
+      close()
+    }
+  }
+
+  return {
+    connected,
+    lastUpdate,
+    changes,
+    connect,
+    disconnect
+  }
+})
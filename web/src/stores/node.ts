@@ -8,7 +8,6 @@ export const useNodeStore = defineStore('node', () => {
   const ranks = ref<NodeMetricRank[]>([])
   const loading = ref(false)
   const error = ref<string | null>(null)
-  const refreshCount = ref(0)
 
   async function fetchNodesData() {
     loading.value = true
@@ -58,25 +57,14 @@ export const useNodeStore = defineStore('node', () => {
     }
   }
 
-  function incrementRefreshCount() {
-    refreshCount.value++
-  }
-
-  function resetRefreshCount() {
-    refreshCount.value = 0
-  }
-
   return {
     nodes,
     ranks,
     loading,
     error,
-    refreshCount,
     fetchNodes: fetchNodesData,
     fetchNodeRanks,
     updateNode: updateNodeData,
-    resetNodeSecret: resetSecret,
-    incrementRefreshCount,
-    resetRefreshCount
+    resetNodeSecret: resetSecret
   }
 }) 
\ No newline at end of file
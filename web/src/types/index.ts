@@ -77,4 +77,95 @@ export interface NodeMetricRank {
     bytes: number
     hits: number
   }
-} 
\ No newline at end of file
+} 
+export interface UptimeReport {
+  window: string
+  observed: number
+  unknown: number
+  downtime: number
+  availability: number
+  failures: number
+  mtbf: number
+  mttr: number
+}
+
+export interface Outage {
+  start: string
+  end?: string
+  reason?: string
+}
+
+export interface UptimeEntry {
+  id: string
+  name: string
+  isEnabled: boolean
+  firstSeen: string
+  lastPoll: string
+  reports: UptimeReport[]
+  outages: Outage[]
+}
+
+export type WebRole = 'viewer' | 'operator' | 'admin'
+
+export type Capability = 'view' | 'node:update' | 'node:reset-secret' | 'user:manage'
+
+export interface WebSession {
+  username: string
+  role: WebRole
+  capabilities: Capability[]
+  csrfToken: string
+  expiresAt: string
+}
+
+export interface LoginPayload {
+  username?: string
+  password?: string
+  token?: string
+}
+
+export interface WebUser {
+  username: string
+  role: WebRole
+}
+
+export interface WebUserPayload {
+  username?: string
+  password?: string
+  role?: WebRole
+}
+
+export type NodeStatusChangeType = 'online' | 'offline' | 'banned' | 'unbanned' | 'added' | 'removed'
+
+export interface NodeStatusChange {
+  id: string
+  name: string
+  type: NodeStatusChangeType
+  isEnabled: boolean
+  isBanned: boolean
+  reason?: string
+}
+
+export interface NodesEvent {
+  nodes: Node[]
+  changes: NodeStatusChange[]
+}
+
+export interface LiveEventHandlers {
+  dashboard: (data: DashboardData) => void
+  nodes: (data: NodesEvent) => void
+  rank: (data: NodeMetricRank[]) => void
+}
+
+export interface NodeTodayRank {
+  rank: number
+  total: number
+  bytes: number
+  hits: number
+}
+
+export interface NodeDetail extends Node {
+  onlineSeconds: number
+  sinceLastActivitySeconds: number
+  bandwidthUtilization: number | null
+  todayRank: NodeTodayRank | null
+}
@@ -16,4 +16,21 @@ export function formatBytes(bytes: number): string {
   }
   
   return `${size.toFixed(2)} ${units[unitIndex]}`
-} 
\ No newline at end of file
+}
+
+export function formatDuration(seconds: number): string {
+  if (seconds <= 0) {
+    return '-'
+  }
+  const days = Math.floor(seconds / 86400)
+  const hours = Math.floor((seconds % 86400) / 3600)
+  const minutes = Math.floor((seconds % 3600) / 60)
+
+  if (days > 0) {
+    return `${days}天${hours}小时`
+  }
+  if (hours > 0) {
+    return `${hours}小时${minutes}分钟`
+  }
+  return minutes > 0 ? `${minutes}分钟` : `${Math.floor(seconds)}秒`
+}